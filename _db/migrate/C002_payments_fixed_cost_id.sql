@@ -0,0 +1,11 @@
+-- +migrate Up
+
+ALTER TABLE payments ADD COLUMN fixed_cost_id INTEGER REFERENCES fixed_costs(id) ON DELETE SET NULL;
+
+-- 同じ固定費から同じ日の支払いが重複して作成されないようにする
+CREATE UNIQUE INDEX payments_fixed_cost_id_payment_date_idx ON payments (fixed_cost_id, payment_date);
+
+-- +migrate Down
+
+DROP INDEX payments_fixed_cost_id_payment_date_idx;
+ALTER TABLE payments DROP COLUMN fixed_cost_id;
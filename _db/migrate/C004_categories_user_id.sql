@@ -0,0 +1,13 @@
+-- +migrate Up
+
+-- user_idがNULLのカテゴリは全ユーザー共通
+ALTER TABLE categories ADD COLUMN user_id INTEGER REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE categories ADD COLUMN archived_at TIMESTAMPTZ;
+
+CREATE INDEX categories_user_id_idx ON categories (user_id);
+
+-- +migrate Down
+
+DROP INDEX categories_user_id_idx;
+ALTER TABLE categories DROP COLUMN archived_at;
+ALTER TABLE categories DROP COLUMN user_id;
@@ -0,0 +1,8 @@
+-- +migrate Up
+
+-- 支払い一覧の期間絞り込みとページングで使う
+CREATE INDEX payments_user_id_payment_date_idx ON payments (user_id, payment_date, id);
+
+-- +migrate Down
+
+DROP INDEX payments_user_id_payment_date_idx;
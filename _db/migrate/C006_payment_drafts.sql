@@ -0,0 +1,20 @@
+-- +migrate Up
+
+-- 利用明細から読み込んだ、支払いとして確定する前の下書き
+CREATE TABLE payment_drafts (
+  id              SERIAL        PRIMARY KEY
+, user_id         INTEGER       NOT NULL REFERENCES users(id) ON DELETE CASCADE
+, source          TEXT          NOT NULL
+, description     TEXT
+, payment_date    TIMESTAMPTZ   NOT NULL
+, payment         INTEGER       NOT NULL
+, created_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+, updated_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX payment_drafts_user_id_idx ON payment_drafts (user_id);
+
+-- +migrate Down
+
+DROP INDEX payment_drafts_user_id_idx;
+DROP TABLE payment_drafts;
@@ -0,0 +1,8 @@
+-- +migrate Up
+
+-- 相手の承認待ち(pending)・承認済み(approved)・却下(rejected)。精算と月ごとの集計は承認済みの支払いのみを対象にする
+ALTER TABLE payments ADD COLUMN status TEXT NOT NULL DEFAULT 'approved' CHECK (status IN ('pending', 'approved', 'rejected'));
+
+-- +migrate Down
+
+ALTER TABLE payments DROP COLUMN status;
@@ -0,0 +1,18 @@
+-- +migrate Up
+
+-- 2人のアカウントで共有する家計簿。支払いなどのデータは作成したユーザー(owner_id)のデータとして保存し、
+-- 招待を承認したユーザー(partner_id)も同じデータを扱う。支払者1はowner_id、支払者2はpartner_idのアカウントに対応する
+CREATE TABLE households (
+  id                     SERIAL        PRIMARY KEY
+, owner_id               INTEGER       NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE
+, partner_id             INTEGER       UNIQUE REFERENCES users(id) ON DELETE SET NULL
+, invitation_token       TEXT          UNIQUE -- 招待トークンのSHA-256。トークン自体は保存しない
+, invitation_expires_at  TIMESTAMPTZ
+, created_at             TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+, updated_at             TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+, CHECK (owner_id <> partner_id)
+);
+
+-- +migrate Down
+
+DROP TABLE households;
@@ -0,0 +1,54 @@
+-- +migrate Up
+
+-- 3人以上でも使える割り勘のグループ。2人の家計簿(payments)とは別に、シェアハウスや旅行などの精算に使う
+CREATE TABLE groups (
+  id              SERIAL        PRIMARY KEY
+, user_id         INTEGER       NOT NULL REFERENCES users(id) ON DELETE CASCADE
+, name            TEXT          NOT NULL
+, created_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+, updated_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX groups_user_id_idx ON groups (user_id);
+
+-- グループのメンバー。アカウントを持たない人も名前で登録できる
+CREATE TABLE group_members (
+  id              SERIAL        PRIMARY KEY
+, group_id        INTEGER       NOT NULL REFERENCES groups(id) ON DELETE CASCADE
+, name            TEXT          NOT NULL
+, created_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+, updated_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+, UNIQUE (group_id, name)
+);
+
+-- split_typeは負担額の分け方。equal:均等、percentage:割合(%)、shares:口数、exact:金額を指定
+CREATE TABLE group_payments (
+  id               SERIAL        PRIMARY KEY
+, group_id         INTEGER       NOT NULL REFERENCES groups(id) ON DELETE CASCADE
+, payer_member_id  INTEGER       NOT NULL REFERENCES group_members(id)
+, description      TEXT
+, payment_date     TIMESTAMPTZ   NOT NULL
+, payment          INTEGER       NOT NULL CHECK (payment > 0)
+, split_type       TEXT          NOT NULL CHECK (split_type IN ('equal', 'percentage', 'shares', 'exact'))
+, created_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+, updated_at       TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX group_payments_group_id_idx ON group_payments (group_id);
+
+-- 支払いを負担するメンバー。valueはsplit_typeに応じた割合・口数・金額で、equalの場合は使わない
+CREATE TABLE group_payment_splits (
+  group_payment_id  INTEGER   NOT NULL REFERENCES group_payments(id) ON DELETE CASCADE
+, member_id         INTEGER   NOT NULL REFERENCES group_members(id)
+, value             INTEGER   NOT NULL DEFAULT 0 CHECK (value >= 0)
+, PRIMARY KEY (group_payment_id, member_id)
+);
+
+-- +migrate Down
+
+DROP TABLE group_payment_splits;
+DROP INDEX group_payments_group_id_idx;
+DROP TABLE group_payments;
+DROP TABLE group_members;
+DROP INDEX groups_user_id_idx;
+DROP TABLE groups;
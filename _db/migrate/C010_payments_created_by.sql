@@ -0,0 +1,8 @@
+-- +migrate Up
+
+-- 支払いを登録したユーザー。登録したユーザー自身は承認・却下できない。NULLの場合は誰でも承認・却下できる
+ALTER TABLE payments ADD COLUMN created_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+-- +migrate Down
+
+ALTER TABLE payments DROP COLUMN created_by;
@@ -0,0 +1,10 @@
+-- +migrate Up
+
+-- 退会済みのユーザーのメールアドレスは、物理削除されるまでの間も新しいユーザーが使えるようにする
+DROP INDEX users_email_idx;
+CREATE UNIQUE INDEX users_email_idx ON users (email) WHERE deleted_at IS NULL;
+
+-- +migrate Down
+
+DROP INDEX users_email_idx;
+CREATE UNIQUE INDEX users_email_idx ON users (email);
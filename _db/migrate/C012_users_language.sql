@@ -0,0 +1,8 @@
+-- +migrate Up
+
+-- エラーメッセージなどの応答の言語(ja/en)。空文字の場合はAccept-Languageヘッダーで決める
+ALTER TABLE users ADD COLUMN language TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+
+ALTER TABLE users DROP COLUMN language;
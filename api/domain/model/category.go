@@ -0,0 +1,16 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Category : UserIDがNULLのカテゴリは全ユーザー共通
+type Category struct {
+	ID        int           `json:"id"`
+	UserID    sql.NullInt64 `json:"-"`
+	Name      string        `json:"name"`
+	Archived  bool          `json:"archived"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
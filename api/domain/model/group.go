@@ -0,0 +1,51 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// グループの支払いの負担額の分け方
+const (
+	SplitEqual      = "equal"
+	SplitPercentage = "percentage"
+	SplitShares     = "shares"
+	SplitExact      = "exact"
+)
+
+// Group : 3人以上でも使える割り勘のグループ。UserIDはグループを作成した家計簿のユーザー
+type Group struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type GroupMember struct {
+	ID        int       `json:"id"`
+	GroupID   int       `json:"group_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GroupPayment : PayerMemberIDのメンバーが支払い、SplitsのメンバーがSplitTypeの分け方で負担する
+type GroupPayment struct {
+	ID            int                  `json:"id"`
+	GroupID       int                  `json:"group_id"`
+	PayerMemberID int                  `json:"payer_member_id"`
+	Description   sql.NullString       `json:"description"`
+	PaymentDate   time.Time            `json:"payment_date"`
+	Payment       int                  `json:"payment"`
+	SplitType     string               `json:"split_type"`
+	Splits        []*GroupPaymentSplit `json:"splits"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+// GroupPaymentSplit : ValueはSplitTypeに応じた割合(%)・口数・金額。均等の場合は使わない
+type GroupPaymentSplit struct {
+	MemberID int `json:"member_id"`
+	Value    int `json:"value"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// Household : 2人のアカウントで共有する家計簿。支払いなどはOwnerIDのユーザーのデータとして保存する。
+// PartnerIDが0の場合はまだ招待が承認されていない
+type Household struct {
+	ID                  int
+	OwnerID             int
+	PartnerID           int
+	InvitationExpiresAt time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
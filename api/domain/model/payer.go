@@ -0,0 +1,8 @@
+package model
+
+// Payer : UserIDは支払者に対応するアカウントのユーザーID。パートナーが家計簿に参加していない場合はnull
+type Payer struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	UserID *int   `json:"user_id"`
+}
@@ -16,6 +16,43 @@ type Payment struct {
 	PaymentDate      time.Time      `json:"payment_date"`
 	PaymentYearMonth string         `json:"-"`
 	Payment          int            `json:"payment"`
+	Status           string         `json:"status"`
+	CreatedBy        sql.NullInt64  `json:"created_by"`
 	CreatedAt        time.Time      `json:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at"`
 }
+
+// 支払いの承認状態。精算と月ごとの集計は承認済みの支払いのみを対象にする。
+// 承認済み・却下の支払いのカテゴリー・支払者・支払日・金額を変更した場合は、変更したユーザーの登録として承認待ちに戻す
+const (
+	PaymentStatusPending  = "pending"
+	PaymentStatusApproved = "approved"
+	PaymentStatusRejected = "rejected"
+)
+
+type MonthlyCategoryPayment struct {
+	YearMonth    string `json:"year_month"`
+	CategoryID   int    `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	PayerID      int    `json:"payer_id"`
+	Payment      int    `json:"payment"`
+	Count        int    `json:"count"`
+}
+
+// PaymentCursor : 支払日・IDの降順で並べたときに、この位置より後の支払いを取得する
+type PaymentCursor struct {
+	PaymentDate time.Time
+	ID          int
+}
+
+// PaymentFilter : ゼロ値の項目は絞り込みに使わない。期間は[From, To)で指定する
+type PaymentFilter struct {
+	From        time.Time
+	To          time.Time
+	CategoryID  int
+	PayerID     int
+	MinPayment  int
+	MaxPayment  int
+	Description string
+	Status      string
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PaymentDraft : 利用明細から読み込んだ支払いの下書き。Sourceは読み込んだ明細の形式
+type PaymentDraft struct {
+	ID          int            `json:"id"`
+	UserID      int            `json:"user_id"`
+	Source      string         `json:"source"`
+	Description sql.NullString `json:"description"`
+	PaymentDate time.Time      `json:"payment_date"`
+	Payment     int            `json:"payment"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package model
+
+// 支払者ID。payersテーブルの固定値と対応する。
+// 家計簿を共有している場合、PayerUserは家計簿を作成したユーザー(households.owner_id)、
+// PayerPartnerは招待を承認したユーザー(households.partner_id)のアカウントに対応する。
+// payments・fixed_costs・payment_draftsのpayer_idにはアカウントのユーザーIDではなくこの固定値を保存する。
+// 既存の支払いと精算の計算はこの2値を前提にしているため、アカウントへの変換はhouseholdsを使って読み出し時に行う
+const (
+	PayerUser    = 1
+	PayerPartner = 2
+)
+
+type CategoryPayment struct {
+	CategoryID   int    `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	PayerID      int    `json:"payer_id"`
+	Payment      int    `json:"payment"`
+}
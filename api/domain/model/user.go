@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// User : Languageは応答の言語。空文字の場合はAccept-Languageヘッダーの言語を使う
+type User struct {
+	ID           int       `json:"id"`
+	UserName     string    `json:"user_name"`
+	PartnerName  string    `json:"partner_name"`
+	Email        string    `json:"email"`
+	Password     string    `json:"-"`
+	UserImage    string    `json:"user_image"`
+	PartnerImage string    `json:"partner_image"`
+	Proportion   int       `json:"proportion"`
+	Language     string    `json:"language"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
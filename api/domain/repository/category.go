@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"github.com/warikan/api/domain/model"
+)
+
+type CategoryRepository interface {
+	GetData(userID int) ([]*model.Category, error)
+	// FindByID : ユーザーが作成したカテゴリのみ取得できる。共通カテゴリと他のユーザーのカテゴリはErrNotFoundを返す
+	FindByID(userID, categoryID int) (*model.Category, error)
+	Create(*model.Category) (*model.Category, error)
+	Update(*model.Category) (*model.Category, error)
+	// DeleteByID : 共通カテゴリと他のユーザーのカテゴリはErrNotFound、支払いまたは固定費から参照されているカテゴリはErrConflictを返す
+	DeleteByID(userID, categoryID int) error
+	// IsReferenced : カテゴリがuserIDのユーザーの支払いまたは固定費から参照されているかどうかを返す
+	IsReferenced(userID, categoryID int) (bool, error)
+}
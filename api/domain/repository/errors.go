@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNotFound : 対象のデータが存在しない
+	ErrNotFound = errors.New("not found")
+	// ErrForbidden : 対象のデータは存在するが、他のユーザーのもの
+	ErrForbidden = errors.New("forbidden")
+	// ErrConflict : 一意制約に違反する
+	ErrConflict = errors.New("conflict")
+)
+
+// InvalidFieldError : 存在しないIDを参照しているなど、Fieldの値をデータベースに保存できない。Fieldが特定できない場合は空
+type InvalidFieldError struct {
+	Field string
+}
+
+func (e InvalidFieldError) Error() string { return "invalid field: " + e.Field }
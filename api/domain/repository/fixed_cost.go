@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/warikan/api/domain/model"
+)
+
+type FixedCostRepository interface {
+	GetData(ctx context.Context, userID int) ([]*model.FixedCost, error)
+	GetAll(ctx context.Context) ([]*model.FixedCost, error)
+	Create(context.Context, *model.FixedCost) (*model.FixedCost, error)
+	// Update : 存在しない固定費と他のユーザーの固定費はErrNotFoundを返す
+	Update(context.Context, *model.FixedCost) (*model.FixedCost, error)
+	// DeleteByID : 存在しない固定費と他のユーザーの固定費はErrNotFoundを返す
+	DeleteByID(ctx context.Context, userID, fixedCostID int) error
+	// CreatePayment : paymentDateと同じ月に固定費から作成した支払いがなければ作成し、作成したかどうかを返す
+	CreatePayment(ctx context.Context, f *model.FixedCost, paymentDate time.Time) (bool, error)
+	// PropagateToPayments : from以降に固定費から作成された支払いへ固定費の内容を反映し、更新件数を返す。
+	// 支払日は月を変えずに、Materializeで作成する場合と同じ日付にする。手で変更した支払いも上書きするため、
+	// 承認待ちでない支払いの精算に関わる項目が変わる場合は承認待ちに戻し、updatedByが登録したものとする(0の場合は記録しない)
+	PropagateToPayments(ctx context.Context, f *model.FixedCost, from time.Time, updatedBy int) (int, error)
+}
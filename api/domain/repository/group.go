@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/warikan/api/domain/model"
+)
+
+type GroupRepository interface {
+	GetData(ctx context.Context, userID int) ([]*model.Group, error)
+	// FindByID : 存在しないグループと他のユーザーのグループはErrNotFoundを返す
+	FindByID(ctx context.Context, userID, groupID int) (*model.Group, error)
+	Create(context.Context, *model.Group) (*model.Group, error)
+	// DeleteByID : メンバーと支払いも削除する。存在しないグループと他のユーザーのグループはErrNotFoundを返す
+	DeleteByID(ctx context.Context, userID, groupID int) error
+	// GetMembers : 登録順に返す
+	GetMembers(ctx context.Context, groupID int) ([]*model.GroupMember, error)
+	// CreateMember : グループに同じ名前のメンバーがいる場合はErrConflictを返す
+	CreateMember(context.Context, *model.GroupMember) (*model.GroupMember, error)
+	// GetPayments : 負担するメンバーを含めて、支払日・IDの昇順で返す
+	GetPayments(ctx context.Context, groupID int) ([]*model.GroupPayment, error)
+	// CreatePayment : 負担するメンバーも保存する
+	CreatePayment(context.Context, *model.GroupPayment) (*model.GroupPayment, error)
+	// DeletePayment : groupIDのグループに存在しない支払いはErrNotFoundを返す
+	DeletePayment(ctx context.Context, groupID, groupPaymentID int) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/warikan/api/domain/model"
+)
+
+type HouseholdRepository interface {
+	// FindByUserID : userIDのユーザーが作成または参加している家計簿を返す。ない場合はErrNotFoundを返す
+	FindByUserID(ctx context.Context, userID int) (*model.Household, error)
+	// SaveInvitation : ownerIDのユーザーの家計簿に招待トークンのハッシュを設定する。家計簿がない場合は作成する
+	SaveInvitation(ctx context.Context, ownerID int, tokenHash string, expiresAt time.Time) (*model.Household, error)
+	// Accept : tokenHashの招待が有効期限内でパートナーが未参加の場合のみpartnerIDのユーザーを参加させる。
+	// 該当する招待がない場合はErrNotFoundを返す
+	Accept(ctx context.Context, tokenHash string, partnerID int, now time.Time) (*model.Household, error)
+	DeleteByID(ctx context.Context, householdID int) error
+}
@@ -0,0 +1,10 @@
+package repository
+
+import (
+	"github.com/warikan/api/domain/model"
+)
+
+type PayerRepository interface {
+	// GetData : 支払者名はユーザー名・パートナー名に置き換えて返す
+	GetData(userID int) ([]*model.Payer, error)
+}
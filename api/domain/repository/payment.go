@@ -1,13 +1,30 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/warikan/api/domain/model"
 )
 
+// PaymentRepository : ctxがキャンセルされた場合は実行中のクエリも中断する
 type PaymentRepository interface {
-	GetData(userID, cursor int) ([]*model.Payment, error)
-	Create(*model.Payment) (*model.Payment, error)
-	Update(*model.Payment) (*model.Payment, error)
-	DeleteByID(userID, paymentID int) error
-	FetchDate(userID int) ([]*string, error)
+	// GetData : filterに一致する支払いを支払日・IDの降順で、cursorより後から最大limit件返す
+	GetData(ctx context.Context, userID int, filter *model.PaymentFilter, cursor *model.PaymentCursor, limit int) ([]*model.Payment, error)
+	// FindByID : 他のユーザーの支払いは存在しないものとしてErrNotFoundを返す
+	FindByID(ctx context.Context, userID, paymentID int) (*model.Payment, error)
+	Create(context.Context, *model.Payment) (*model.Payment, error)
+	// Update : 存在しない支払いと他のユーザーの支払いはErrNotFoundを返す。
+	// UpdatedAtがゼロ値でない場合、保存されている支払いのupdated_atと異なればErrConflictを返す。
+	// StatusとCreatedByも指定した値で更新する
+	Update(context.Context, *model.Payment) (*model.Payment, error)
+	// DeleteByID : 存在しない支払いと他のユーザーの支払いはErrNotFoundを返す。
+	// updatedAtがゼロ値でない場合、保存されている支払いのupdated_atと異なればErrConflictを返す
+	DeleteByID(ctx context.Context, userID, paymentID int, updatedAt time.Time) error
+	// UpdateStatus : 承認待ちの支払いの承認状態をstatusに更新する。存在しない支払いと他のユーザーの支払いはErrNotFound、
+	// reviewerIDが登録した支払いはErrForbidden、承認待ちでない支払いはErrConflictを返す
+	UpdateStatus(ctx context.Context, userID, reviewerID, paymentID int, status string) (*model.Payment, error)
+	FetchMonthlyCosts(ctx context.Context, userID int) ([]*model.MonthlyCategoryPayment, error)
+	// CountDuplicates : pと支払日(JSTの日付)・カテゴリー・支払者・金額・内容が同じ、pのユーザーの支払いの件数を返す
+	CountDuplicates(ctx context.Context, p *model.Payment) (int, error)
 }
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/warikan/api/domain/model"
+)
+
+type PaymentDraftRepository interface {
+	GetData(ctx context.Context, userID int) ([]*model.PaymentDraft, error)
+	FindByID(ctx context.Context, userID, paymentDraftID int) (*model.PaymentDraft, error)
+	Create(context.Context, *model.PaymentDraft) (*model.PaymentDraft, error)
+	// CountDuplicates : dと支払日(JSTの日付)・金額・内容が同じ、dのユーザーの下書きの件数を返す
+	CountDuplicates(ctx context.Context, d *model.PaymentDraft) (int, error)
+	DeleteByID(ctx context.Context, userID, paymentDraftID int) error
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/warikan/api/domain/model"
+)
+
+type SettlementRepository interface {
+	FetchProportion(userID int) (int, error)
+	FetchCategoryPayments(userID int, from, to time.Time) ([]*model.CategoryPayment, error)
+}
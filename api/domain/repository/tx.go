@@ -0,0 +1,10 @@
+package repository
+
+import "context"
+
+// TxManager : 複数のリポジトリの操作を1つのトランザクションで実行する
+type TxManager interface {
+	// WithinTx : fnがエラーを返した場合はロールバックし、それ以外はコミットする。
+	// fnに渡すctxを各リポジトリに渡すと同じトランザクションで実行される。既にトランザクション中の場合はそのトランザクションで実行する
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/warikan/api/domain/model"
+)
+
+type UserRepository interface {
+	// FindByID : 存在しないユーザーと退会済みのユーザーはErrNotFoundを返す
+	FindByID(userID int) (*model.User, error)
+	// FindByEmail : 退会していないユーザーのみ取得できる。該当するユーザーがいない場合はErrNotFoundを返す
+	FindByEmail(email string) (*model.User, error)
+	// Create : 退会していないユーザーとメールアドレスが重複する場合はErrConflictを返す
+	Create(*model.User) (*model.User, error)
+	// Update : 退会していない他のユーザーとメールアドレスが重複する場合はErrConflictを返す
+	Update(*model.User) (*model.User, error)
+	// DeleteByID : 退会済みとしてdeleted_atを設定し、家計簿から外す。存在しないユーザーと退会済みのユーザーはErrNotFoundを返す。
+	// 両方を確定させるため、呼び出し側でTxManager.WithinTxのトランザクション中に呼ぶ
+	DeleteByID(ctx context.Context, userID int) error
+	// PurgeDeletedBefore : before以前に退会したユーザーのデータを物理削除し、削除したユーザー数を返す。
+	// 呼び出し側でTxManager.WithinTxのトランザクション中に呼ぶ
+	PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error)
+}
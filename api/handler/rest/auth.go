@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/warikan/api/usecase"
+)
+
+type AuthHandler interface {
+	Signup(http.ResponseWriter, *http.Request)
+	Login(http.ResponseWriter, *http.Request)
+}
+
+type authHandler struct {
+	useCase usecase.AuthUseCase
+}
+
+func NewAuthHandler(u usecase.AuthUseCase) AuthHandler {
+	return &authHandler{
+		useCase: u,
+	}
+}
+
+func (h *authHandler) Signup(w http.ResponseWriter, r *http.Request) {
+	req := usecase.SignupParam{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	resp, err := h.useCase.Signup(&req)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		httpError(w, r, err, "")
+	}
+}
+
+func (h *authHandler) Login(w http.ResponseWriter, r *http.Request) {
+	req := usecase.LoginParam{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	resp, err := h.useCase.Login(&req)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		httpError(w, r, err, "")
+	}
+}
@@ -0,0 +1,166 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/handler/rest"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_authHandler_Signup(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		req          *usecase.SignupParam
+		token        *usecase.Token
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name: "Success",
+			body: `{"user_name":"ユーザー","email":"test@example.com","password":"password1234"}`,
+			req: &usecase.SignupParam{
+				UserName: "ユーザー",
+				Email:    "test@example.com",
+				Password: "password1234",
+			},
+			token: &usecase.Token{
+				UserID:    1,
+				Token:     "token",
+				ExpiresAt: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantCode: http.StatusCreated,
+			wantBody: `{"user_id":1,"token":"token","expires_at":"2020-04-01T00:00:00Z"}` + "\n",
+		},
+		{
+			name: "Conflict error",
+			body: `{"user_name":"ユーザー","email":"test@example.com","password":"password1234"}`,
+			req: &usecase.SignupParam{
+				UserName: "ユーザー",
+				Email:    "test@example.com",
+				Password: "password1234",
+			},
+			useCaseError: usecase.ConflictError{},
+			wantCode:     http.StatusConflict,
+			wantBody:     `{"code":"conflict","msg":"競合が発生しました。"}` + "\n",
+		},
+		{
+			name:     "Bad request error",
+			body:     `{`,
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockAuthUseCase{}
+			mock.On("Signup", tt.req).Return(tt.token, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			h := rest.NewAuthHandler(mock)
+
+			h.Signup(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("Signup() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("Signup() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_authHandler_Login(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		req          *usecase.LoginParam
+		token        *usecase.Token
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name: "Success",
+			body: `{"email":"test@example.com","password":"password1234"}`,
+			req:  &usecase.LoginParam{Email: "test@example.com", Password: "password1234"},
+			token: &usecase.Token{
+				UserID:    1,
+				Token:     "token",
+				ExpiresAt: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"user_id":1,"token":"token","expires_at":"2020-04-01T00:00:00Z"}` + "\n",
+		},
+		{
+			name:         "Unauthorized error",
+			body:         `{"email":"test@example.com","password":"password"}`,
+			req:          &usecase.LoginParam{Email: "test@example.com", Password: "password"},
+			useCaseError: usecase.UnauthorizedError{},
+			wantCode:     http.StatusUnauthorized,
+			wantBody:     `{"code":"unauthorized","msg":"サーバーとの認証に失敗しました。再度ログインしてください。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockAuthUseCase{}
+			mock.On("Login", tt.req).Return(tt.token, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			h := rest.NewAuthHandler(mock)
+
+			h.Login(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("Login() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("Login() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type mockAuthUseCase struct {
+	mock.Mock
+}
+
+func (m *mockAuthUseCase) Signup(param *usecase.SignupParam) (*usecase.Token, error) {
+	ret := m.Called(param)
+	return ret.Get(0).(*usecase.Token), ret.Error(1)
+}
+
+func (m *mockAuthUseCase) Login(param *usecase.LoginParam) (*usecase.Token, error) {
+	ret := m.Called(param)
+	return ret.Get(0).(*usecase.Token), ret.Error(1)
+}
+
+func (m *mockAuthUseCase) Authenticate(token string) (*model.User, error) {
+	ret := m.Called(token)
+	return ret.Get(0).(*model.User), ret.Error(1)
+}
+
+func (m *mockAuthUseCase) AuthorizeLedger(ctx context.Context, userID, ledgerUserID int) error {
+	ret := m.Called(userID, ledgerUserID)
+	return ret.Error(0)
+}
@@ -0,0 +1,132 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/warikan/api/usecase"
+)
+
+type CategoriesHandler interface {
+	GetData(http.ResponseWriter, *http.Request)
+	CreateData(http.ResponseWriter, *http.Request)
+	UpdateData(http.ResponseWriter, *http.Request)
+	DeleteData(http.ResponseWriter, *http.Request)
+}
+
+type categoriesHandler struct {
+	useCase usecase.CategoryUseCase
+}
+
+func NewCategoriesHandler(u usecase.CategoryUseCase) CategoriesHandler {
+	return &categoriesHandler{
+		useCase: u,
+	}
+}
+
+type categoryHandlerResponse struct {
+	Categories []*usecase.Category `json:"categories"`
+}
+
+func (h *categoriesHandler) GetData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	categories, err := h.useCase.GetData(userID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	res := categoryHandlerResponse{Categories: categories}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		internalServerError(w, r, "")
+	}
+}
+
+func (h *categoriesHandler) CreateData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	req := usecase.CreateCategoryParam{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	resp, err := h.useCase.Create(&req, userID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		httpError(w, r, err, "")
+	}
+}
+
+func (h *categoriesHandler) UpdateData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	strCategoryID := chi.URLParam(r, "category_id")
+
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+	categoryID, err := strconv.Atoi(strCategoryID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	req := usecase.UpdateCategoryParam{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	resp, err := h.useCase.Update(&req, userID, categoryID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		httpError(w, r, err, "")
+	}
+}
+
+func (h *categoriesHandler) DeleteData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	strCategoryID := chi.URLParam(r, "category_id")
+
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+	categoryID, err := strconv.Atoi(strCategoryID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	if err := h.useCase.DeleteByID(userID, categoryID); err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,242 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/google/go-cmp/cmp"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/warikan/api/handler/rest"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_categoriesHandler_GetData(t *testing.T) {
+	tests := []struct {
+		name         string
+		strUserID    string
+		userID       int
+		categories   []*usecase.Category
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:      "Success",
+			strUserID: "1",
+			userID:    1,
+			categories: []*usecase.Category{
+				{ID: 1, Name: "家賃"},
+				{ID: 100, Name: "ペット", Custom: true},
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"categories":[{"id":1,"name":"家賃","custom":false,"archived":false},{"id":100,"name":"ペット","custom":true,"archived":false}]}` + "\n",
+		},
+		{
+			name:      "Bad request error userID is String",
+			strUserID: "string",
+			wantCode:  http.StatusBadRequest,
+			wantBody:  `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:         "Internal server error",
+			strUserID:    "1",
+			userID:       1,
+			categories:   []*usecase.Category{},
+			useCaseError: usecase.InternalServerError{},
+			wantCode:     http.StatusInternalServerError,
+			wantBody:     `{"code":"internal_server_error","msg":"システム内部エラーが発生しました。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockCategoryUseCase{}
+			mock.On("GetData", tt.userID).Return(tt.categories, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewCategoriesHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.strUserID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.GetData(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("GetData() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("GetData() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_categoriesHandler_CreateData(t *testing.T) {
+	tests := []struct {
+		name         string
+		strUserID    string
+		userID       int
+		body         string
+		category     *usecase.Category
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:      "Success",
+			strUserID: "1",
+			userID:    1,
+			body:      `{"name":"ペット"}`,
+			category:  &usecase.Category{ID: 100, Name: "ペット", Custom: true},
+			wantCode:  http.StatusCreated,
+			wantBody:  `{"id":100,"name":"ペット","custom":true,"archived":false}` + "\n",
+		},
+		{
+			name:      "Bad request error invalid json",
+			strUserID: "1",
+			userID:    1,
+			body:      `{"name":`,
+			wantCode:  http.StatusBadRequest,
+			wantBody:  `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:         "Invalid param error",
+			strUserID:    "1",
+			userID:       1,
+			body:         `{"name":""}`,
+			category:     &usecase.Category{},
+			useCaseError: usecase.InvalidParamError{},
+			wantCode:     http.StatusBadRequest,
+			wantBody:     `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockCategoryUseCase{}
+			m.On("Create", mock.Anything, tt.userID).Return(tt.category, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			h := rest.NewCategoriesHandler(m)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.strUserID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.CreateData(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("CreateData() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("CreateData() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_categoriesHandler_DeleteData(t *testing.T) {
+	tests := []struct {
+		name          string
+		strUserID     string
+		userID        int
+		strCategoryID string
+		categoryID    int
+		useCaseError  error
+		wantCode      int
+		wantBody      string
+	}{
+		{
+			name:          "Success",
+			strUserID:     "1",
+			userID:        1,
+			strCategoryID: "100",
+			categoryID:    100,
+			wantCode:      http.StatusNoContent,
+			wantBody:      "",
+		},
+		{
+			name:          "Bad request error categoryID is String",
+			strUserID:     "1",
+			userID:        1,
+			strCategoryID: "string",
+			wantCode:      http.StatusBadRequest,
+			wantBody:      `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:          "Conflict error category is referenced",
+			strUserID:     "1",
+			userID:        1,
+			strCategoryID: "100",
+			categoryID:    100,
+			useCaseError:  usecase.ConflictError{},
+			wantCode:      http.StatusConflict,
+			wantBody:      `{"code":"conflict","msg":"競合が発生しました。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockCategoryUseCase{}
+			mock.On("DeleteByID", tt.userID, tt.categoryID).Return(tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewCategoriesHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.strUserID)
+			rctx.URLParams.Add("category_id", tt.strCategoryID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.DeleteData(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("DeleteData() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("DeleteData() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type mockCategoryUseCase struct {
+	mock.Mock
+}
+
+func (m *mockCategoryUseCase) GetData(userID int) ([]*usecase.Category, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).([]*usecase.Category), ret.Error(1)
+}
+
+func (m *mockCategoryUseCase) Create(param *usecase.CreateCategoryParam, userID int) (*usecase.Category, error) {
+	ret := m.Called(param, userID)
+	return ret.Get(0).(*usecase.Category), ret.Error(1)
+}
+
+func (m *mockCategoryUseCase) Update(param *usecase.UpdateCategoryParam, userID, categoryID int) (*usecase.Category, error) {
+	ret := m.Called(param, userID, categoryID)
+	return ret.Get(0).(*usecase.Category), ret.Error(1)
+}
+
+func (m *mockCategoryUseCase) DeleteByID(userID, categoryID int) error {
+	ret := m.Called(userID, categoryID)
+	return ret.Error(0)
+}
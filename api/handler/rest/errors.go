@@ -10,82 +10,116 @@ import (
 	"github.com/warikan/log"
 )
 
+// errorMessage : Codeはクライアントが判定に使うエラーの種類、Messageは画面に表示するメッセージ
 type errorMessage struct {
+	Code    string        `json:"code"`
+	Message string        `json:"msg"`
+	Fields  []*fieldError `json:"fields,omitempty"`
+}
+
+// fieldError : 不正な値が指定された項目。Ruleは失敗した検証ルール
+type fieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
 	Message string `json:"msg"`
 }
 
 const (
-	badRequestErrorMsg     = "要求の形式が正しくありません。"
-	notFoundErrorMsg       = "ページが見つかりません。"
-	internalServerErrorMsg = "システム内部エラーが発生しました。"
-	conflictErrorMsg       = "競合が発生しました。"
+	badRequestCode          = "bad_request"
+	invalidParamCode        = "invalid_param"
+	unauthorizedCode        = "unauthorized"
+	forbiddenCode           = "forbidden"
+	notFoundCode            = "not_found"
+	conflictCode            = "conflict"
+	unprocessableEntityCode = "unprocessable_entity"
+	internalServerErrorCode = "internal_server_error"
 )
 
-func httpError(w http.ResponseWriter, err error, msg string) {
-	switch err.(type) {
-	case usecase.UnauthorizedError:
-		unauthorizedError(w, msg)
+func httpError(w http.ResponseWriter, r *http.Request, err error, msg string) {
+	switch e := err.(type) {
+	case usecase.UnauthorizedError, usecase.TokenExpiredError:
+		unauthorizedError(w, r, msg)
+	case usecase.ForbiddenError:
+		forbiddenError(w, r, msg)
 	case usecase.BadRequestError:
-		badRequestError(w, msg)
+		badRequestError(w, r, msg)
 	case usecase.InvalidParamError:
-		badRequestError(w, msg)
+		invalidParamError(w, r, msg, e.Fields)
 	case usecase.NotFoundError:
-		notFoundError(w, msg)
+		notFoundError(w, r, msg)
 	case usecase.ConflictError:
-		conflictError(w, msg)
+		conflictError(w, r, msg)
+	case usecase.UnprocessableEntityError:
+		unprocessableEntityError(w, r, msg, e.Field)
 	default:
-		internalServerError(w, msg)
+		internalServerError(w, r, msg)
 	}
 }
 
-func unauthorizedError(w http.ResponseWriter, msg string) {
-	code := http.StatusUnauthorized
-	m := msg
-	if msg == "" {
-		m = "サーバーとの認証に失敗しました。再度ログインしてください。"
-	}
-	errorResponse(w, code, m)
+func unauthorizedError(w http.ResponseWriter, r *http.Request, msg string) {
+	errorResponse(w, r, http.StatusUnauthorized, unauthorizedCode, msg)
 }
 
-func badRequestError(w http.ResponseWriter, msg string) {
-	code := http.StatusBadRequest
-	m := msg
-	if msg == "" {
-		m = badRequestErrorMsg
-	}
-	errorResponse(w, code, m)
+func badRequestError(w http.ResponseWriter, r *http.Request, msg string) {
+	errorResponse(w, r, http.StatusBadRequest, badRequestCode, msg)
 }
 
-func notFoundError(w http.ResponseWriter, msg string) {
-	code := http.StatusNotFound
-	m := msg
-	if msg == "" {
-		m = notFoundErrorMsg
+func forbiddenError(w http.ResponseWriter, r *http.Request, msg string) {
+	errorResponse(w, r, http.StatusForbidden, forbiddenCode, msg)
+}
+
+func notFoundError(w http.ResponseWriter, r *http.Request, msg string) {
+	errorResponse(w, r, http.StatusNotFound, notFoundCode, msg)
+}
+
+func internalServerError(w http.ResponseWriter, r *http.Request, msg string) {
+	errorResponse(w, r, http.StatusInternalServerError, internalServerErrorCode, msg)
+}
+
+func conflictError(w http.ResponseWriter, r *http.Request, msg string) {
+	errorResponse(w, r, http.StatusConflict, conflictCode, msg)
+}
+
+// unprocessableEntityError : fieldが空の場合は項目を特定せずに返す
+func unprocessableEntityError(w http.ResponseWriter, r *http.Request, msg, field string) {
+	c := responseCatalog(r)
+	em := newErrorMessage(c, unprocessableEntityCode, msg)
+	if field != "" {
+		em.Fields = []*fieldError{{Field: field, Rule: "exists", Message: c.ruleMessage("exists", "")}}
 	}
-	errorResponse(w, code, m)
+	writeErrorMessage(w, http.StatusUnprocessableEntity, em)
 }
 
-func internalServerError(w http.ResponseWriter, msg string) {
-	code := http.StatusInternalServerError
-	m := msg
-	if msg == "" {
-		m = internalServerErrorMsg
+// invalidParamError : 項目が特定できない場合は項目なしのbad_requestとして返す
+func invalidParamError(w http.ResponseWriter, r *http.Request, msg string, fields []usecase.FieldError) {
+	if len(fields) == 0 {
+		badRequestError(w, r, msg)
+		return
+	}
+
+	c := responseCatalog(r)
+	em := newErrorMessage(c, invalidParamCode, msg)
+	for _, f := range fields {
+		em.Fields = append(em.Fields, &fieldError{Field: f.Field, Rule: f.Rule, Message: c.ruleMessage(f.Rule, f.Param)})
 	}
-	errorResponse(w, code, m)
+	writeErrorMessage(w, http.StatusBadRequest, em)
+}
+
+// errorResponse : msgが空の場合は応答の言語のメッセージを返す
+func errorResponse(w http.ResponseWriter, r *http.Request, status int, code, msg string) {
+	writeErrorMessage(w, status, newErrorMessage(responseCatalog(r), code, msg))
 }
 
-func conflictError(w http.ResponseWriter, msg string) {
-	code := http.StatusConflict
+func newErrorMessage(c *catalog, code, msg string) *errorMessage {
 	m := msg
 	if msg == "" {
-		m = conflictErrorMsg
+		m = c.errorMessage(code)
 	}
-	errorResponse(w, code, m)
+	return &errorMessage{Code: code, Message: m}
 }
 
-func errorResponse(w http.ResponseWriter, code int, msg string) {
-	w.WriteHeader(code)
-	em := errorMessage{Message: msg}
+func writeErrorMessage(w http.ResponseWriter, status int, em *errorMessage) {
+	w.WriteHeader(status)
 	err := json.NewEncoder(w).Encode(em)
 	if err != nil {
 		log.Logger.Error("failed to json encode", zap.Error(err))
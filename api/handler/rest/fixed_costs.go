@@ -0,0 +1,133 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/warikan/api/usecase"
+)
+
+type FixedCostsHandler interface {
+	GetData(http.ResponseWriter, *http.Request)
+	CreateData(http.ResponseWriter, *http.Request)
+	UpdateData(http.ResponseWriter, *http.Request)
+	DeleteData(http.ResponseWriter, *http.Request)
+}
+
+type fixedCostsHandler struct {
+	useCase usecase.FixedCostUseCase
+}
+
+func NewFixedCostsHandler(u usecase.FixedCostUseCase) FixedCostsHandler {
+	return &fixedCostsHandler{
+		useCase: u,
+	}
+}
+
+type fixedCostHandlerResponse struct {
+	FixedCosts []*usecase.FixedCost `json:"fixed_costs"`
+}
+
+func (h *fixedCostsHandler) GetData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	fixedCosts, err := h.useCase.GetData(r.Context(), userID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	res := fixedCostHandlerResponse{FixedCosts: fixedCosts}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		internalServerError(w, r, "")
+	}
+}
+
+func (h *fixedCostsHandler) CreateData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	req := usecase.CreateFixedCostParam{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	resp, err := h.useCase.Create(r.Context(), &req, userID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		httpError(w, r, err, "")
+	}
+}
+
+func (h *fixedCostsHandler) UpdateData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	strFixedCostID := chi.URLParam(r, "fixed_cost_id")
+
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+	fixedCostID, err := strconv.Atoi(strFixedCostID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	req := usecase.UpdateFixedCostParam{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+	req.UpdatedBy, _ = UserIDFromContext(r.Context())
+
+	resp, err := h.useCase.Update(r.Context(), &req, userID, fixedCostID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		httpError(w, r, err, "")
+	}
+}
+
+func (h *fixedCostsHandler) DeleteData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	strFixedCostID := chi.URLParam(r, "fixed_cost_id")
+
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+	fixedCostID, err := strconv.Atoi(strFixedCostID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	if err := h.useCase.DeleteByID(r.Context(), userID, fixedCostID); err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
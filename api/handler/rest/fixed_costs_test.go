@@ -0,0 +1,355 @@
+package rest_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/go-cmp/cmp"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/handler/rest"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_fixedCostsHandler_GetData(t *testing.T) {
+	tests := []struct {
+		name         string
+		strUserID    string
+		userID       int
+		fixedCosts   []*usecase.FixedCost
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:      "Success",
+			strUserID: "1",
+			userID:    1,
+			fixedCosts: []*usecase.FixedCost{
+				{
+					ID:           1,
+					CategoryID:   1,
+					CategoryName: "家賃",
+					PayerID:      1,
+					PayerName:    "ユーザー",
+					PaymentDate:  "2020-04-25",
+					Payment:      80000,
+					CreatedAt:    "2020-04-01 09:00:00",
+				},
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"fixed_costs":[{"id":1,"category_id":1,"category_name":"家賃","payer_id":1,"payer_name":"ユーザー","description":{"String":"","Valid":false},"payment_date":"2020-04-25","payment":80000,"created_at":"2020-04-01 09:00:00"}]}` + "\n",
+		},
+		{
+			name:      "Bad request error userID is String",
+			strUserID: "string",
+			wantCode:  http.StatusBadRequest,
+			wantBody:  `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:         "Internal server error",
+			strUserID:    "1",
+			userID:       1,
+			fixedCosts:   []*usecase.FixedCost{},
+			useCaseError: usecase.InternalServerError{},
+			wantCode:     http.StatusInternalServerError,
+			wantBody:     `{"code":"internal_server_error","msg":"システム内部エラーが発生しました。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockFixedCostUseCase{}
+			mock.On("GetData", tt.userID).Return(tt.fixedCosts, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewFixedCostsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.strUserID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.GetData(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("GetData() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("GetData() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_fixedCostsHandler_CreateData(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		id           int
+		req          *usecase.CreateFixedCostParam
+		want         *model.FixedCost
+		body         string
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:   "Success",
+			userID: "1",
+			id:     1,
+			req: &usecase.CreateFixedCostParam{
+				CategoryID:  1,
+				PayerID:     1,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     80000,
+			},
+			want: &model.FixedCost{
+				ID:          1,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     1,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     80000,
+				CreatedAt:   time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt:   time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+			},
+			body:     `{"category_id":1,"payer_id":1,"payment_date":"2020-04-25T00:00:00Z","payment":80000}`,
+			wantCode: http.StatusCreated,
+			wantBody: `{"id":1,"user_id":1,"category_id":1,"payer_id":1,"description":{"String":"","Valid":false},"payment_date":"2020-04-25T00:00:00Z","payment":80000,"created_at":"2020-04-01T00:00:00Z","updated_at":"2020-04-01T00:00:00Z"}` + "\n",
+		},
+		{
+			name:     "Bad request error invalid body",
+			userID:   "1",
+			id:       1,
+			body:     `{"category_id":"1"}`,
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockFixedCostUseCase{}
+			mock.On("Create", tt.req, tt.id).Return(tt.want, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			h := rest.NewFixedCostsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.userID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.CreateData(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("CreateData() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("CreateData() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_fixedCostsHandler_UpdateData(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		fixedCostID  string
+		id           int
+		fID          int
+		req          *usecase.UpdateFixedCostParam
+		want         *model.FixedCost
+		body         string
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:        "Success",
+			userID:      "1",
+			fixedCostID: "1",
+			id:          1,
+			fID:         1,
+			req: &usecase.UpdateFixedCostParam{
+				CategoryID:  1,
+				PayerID:     2,
+				Description: sql.NullString{String: "家賃", Valid: true},
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     90000,
+			},
+			want: &model.FixedCost{
+				ID:          1,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     2,
+				Description: sql.NullString{String: "家賃", Valid: true},
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     90000,
+				CreatedAt:   time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt:   time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+			},
+			body:     `{"category_id":1,"payer_id":2,"description":{"String":"家賃","Valid":true},"payment_date":"2020-04-25T00:00:00Z","payment":90000}`,
+			wantCode: http.StatusOK,
+			wantBody: `{"id":1,"user_id":1,"category_id":1,"payer_id":2,"description":{"String":"家賃","Valid":true},"payment_date":"2020-04-25T00:00:00Z","payment":90000,"created_at":"2020-04-01T00:00:00Z","updated_at":"2020-04-01T00:00:00Z"}` + "\n",
+		},
+		{
+			name:        "Not found error",
+			userID:      "1",
+			fixedCostID: "999",
+			id:          1,
+			fID:         999,
+			req: &usecase.UpdateFixedCostParam{
+				CategoryID:  1,
+				PayerID:     2,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     90000,
+			},
+			want:         &model.FixedCost{},
+			body:         `{"category_id":1,"payer_id":2,"payment_date":"2020-04-25T00:00:00Z","payment":90000}`,
+			useCaseError: usecase.NotFoundError{},
+			wantCode:     http.StatusNotFound,
+			wantBody:     `{"code":"not_found","msg":"ページが見つかりません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockFixedCostUseCase{}
+			mock.On("Update", tt.req, tt.id, tt.fID).Return(tt.want, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			h := rest.NewFixedCostsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.userID)
+			rctx.URLParams.Add("fixed_cost_id", tt.fixedCostID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.UpdateData(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("UpdateData() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("UpdateData() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_fixedCostsHandler_DeleteData(t *testing.T) {
+	tests := []struct {
+		name           string
+		strUserID      string
+		userID         int
+		strFixedCostID string
+		fixedCostID    int
+		useCaseError   error
+		wantCode       int
+		wantBody       string
+	}{
+		{
+			name:           "Success",
+			strUserID:      "1",
+			userID:         1,
+			strFixedCostID: "1",
+			fixedCostID:    1,
+			wantCode:       http.StatusNoContent,
+			wantBody:       "",
+		},
+		{
+			name:           "Bad request error fixedCostID is String",
+			strUserID:      "1",
+			userID:         1,
+			strFixedCostID: "string",
+			wantCode:       http.StatusBadRequest,
+			wantBody:       `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:           "Not found error",
+			strUserID:      "1",
+			userID:         1,
+			strFixedCostID: "999",
+			fixedCostID:    999,
+			useCaseError:   usecase.NotFoundError{},
+			wantCode:       http.StatusNotFound,
+			wantBody:       `{"code":"not_found","msg":"ページが見つかりません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockFixedCostUseCase{}
+			mock.On("DeleteByID", tt.userID, tt.fixedCostID).Return(tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewFixedCostsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.strUserID)
+			rctx.URLParams.Add("fixed_cost_id", tt.strFixedCostID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.DeleteData(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("DeleteData() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("DeleteData() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type mockFixedCostUseCase struct {
+	mock.Mock
+}
+
+func (m *mockFixedCostUseCase) GetData(ctx context.Context, userID int) ([]*usecase.FixedCost, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).([]*usecase.FixedCost), ret.Error(1)
+}
+
+func (m *mockFixedCostUseCase) Create(ctx context.Context, param *usecase.CreateFixedCostParam, userID int) (*model.FixedCost, error) {
+	ret := m.Called(param, userID)
+	return ret.Get(0).(*model.FixedCost), ret.Error(1)
+}
+
+func (m *mockFixedCostUseCase) Update(ctx context.Context, param *usecase.UpdateFixedCostParam, userID, fixedCostID int) (*model.FixedCost, error) {
+	ret := m.Called(param, userID, fixedCostID)
+	return ret.Get(0).(*model.FixedCost), ret.Error(1)
+}
+
+func (m *mockFixedCostUseCase) DeleteByID(ctx context.Context, userID, fixedCostID int) error {
+	ret := m.Called(userID, fixedCostID)
+	return ret.Error(0)
+}
+
+func (m *mockFixedCostUseCase) Materialize(ctx context.Context, yearMonth string) (int, error) {
+	ret := m.Called(yearMonth)
+	return ret.Int(0), ret.Error(1)
+}
@@ -0,0 +1,238 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"github.com/warikan/api/usecase"
+)
+
+type GroupsHandler interface {
+	GetData(http.ResponseWriter, *http.Request)
+	CreateData(http.ResponseWriter, *http.Request)
+	GetDetail(http.ResponseWriter, *http.Request)
+	DeleteData(http.ResponseWriter, *http.Request)
+	CreateMember(http.ResponseWriter, *http.Request)
+	GetPayments(http.ResponseWriter, *http.Request)
+	CreatePayment(http.ResponseWriter, *http.Request)
+	DeletePayment(http.ResponseWriter, *http.Request)
+	GetSettlement(http.ResponseWriter, *http.Request)
+}
+
+type groupsHandler struct {
+	useCase usecase.GroupUseCase
+}
+
+func NewGroupsHandler(u usecase.GroupUseCase) GroupsHandler {
+	return &groupsHandler{
+		useCase: u,
+	}
+}
+
+type groupsHandlerResponse struct {
+	Groups []*usecase.Group `json:"groups"`
+}
+
+type groupPaymentsHandlerResponse struct {
+	Payments []*usecase.GroupPayment `json:"payments"`
+}
+
+// groupPath : パスのuser_idとgroup_idを返す
+func groupPath(r *http.Request) (int, int, error) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "user_id"))
+	if err != nil {
+		return 0, 0, err
+	}
+	groupID, err := strconv.Atoi(chi.URLParam(r, "group_id"))
+	if err != nil {
+		return 0, 0, err
+	}
+	return userID, groupID, nil
+}
+
+func (h *groupsHandler) GetData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	groups, err := h.useCase.GetData(r.Context(), userID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	res := groupsHandlerResponse{Groups: groups}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		internalServerError(w, r, "")
+	}
+}
+
+func (h *groupsHandler) CreateData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	req := usecase.CreateGroupParam{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	resp, err := h.useCase.Create(r.Context(), &req, userID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		internalServerError(w, r, "")
+	}
+}
+
+func (h *groupsHandler) GetDetail(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, err := groupPath(r)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	res, err := h.useCase.Get(r.Context(), userID, groupID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		internalServerError(w, r, "")
+	}
+}
+
+func (h *groupsHandler) DeleteData(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, err := groupPath(r)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	if err := h.useCase.DeleteByID(r.Context(), userID, groupID); err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *groupsHandler) CreateMember(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, err := groupPath(r)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	req := usecase.CreateGroupMemberParam{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	resp, err := h.useCase.AddMember(r.Context(), &req, userID, groupID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		internalServerError(w, r, "")
+	}
+}
+
+func (h *groupsHandler) GetPayments(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, err := groupPath(r)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	payments, err := h.useCase.GetPayments(r.Context(), userID, groupID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	res := groupPaymentsHandlerResponse{Payments: payments}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		internalServerError(w, r, "")
+	}
+}
+
+func (h *groupsHandler) CreatePayment(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, err := groupPath(r)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	req := usecase.CreateGroupPaymentParam{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	resp, err := h.useCase.CreatePayment(r.Context(), &req, userID, groupID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		internalServerError(w, r, "")
+	}
+}
+
+func (h *groupsHandler) DeletePayment(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, err := groupPath(r)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+	groupPaymentID, err := strconv.Atoi(chi.URLParam(r, "group_payment_id"))
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	if err := h.useCase.DeletePayment(r.Context(), userID, groupID, groupPaymentID); err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *groupsHandler) GetSettlement(w http.ResponseWriter, r *http.Request) {
+	userID, groupID, err := groupPath(r)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	res, err := h.useCase.Settle(r.Context(), userID, groupID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		internalServerError(w, r, "")
+	}
+}
@@ -0,0 +1,221 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/warikan/api/handler/rest"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_groupsHandler_CreatePayment(t *testing.T) {
+	tests := []struct {
+		name         string
+		strGroupID   string
+		body         string
+		param        *usecase.CreateGroupPaymentParam
+		payment      *usecase.GroupPayment
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:       "Success",
+			strGroupID: "1",
+			body:       `{"payer_member_id":1,"payment_date":"2020-04-01T00:00:00Z","payment":1000,"split_type":"shares","splits":[{"member_id":1,"value":1},{"member_id":2,"value":3}]}`,
+			param: &usecase.CreateGroupPaymentParam{
+				PayerMemberID: 1,
+				PaymentDate:   time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:       1000,
+				SplitType:     "shares",
+				Splits: []*usecase.GroupPaymentSplitParam{
+					{MemberID: 1, Value: 1},
+					{MemberID: 2, Value: 3},
+				},
+			},
+			payment: &usecase.GroupPayment{
+				ID:            1,
+				PayerMemberID: 1,
+				PaymentDate:   "2020-04-01",
+				Payment:       1000,
+				SplitType:     "shares",
+				Splits: []*usecase.GroupPaymentSplit{
+					{MemberID: 1, Value: 1, Amount: 250},
+					{MemberID: 2, Value: 3, Amount: 750},
+				},
+			},
+			wantCode: http.StatusCreated,
+			wantBody: `{"id":1,"payer_member_id":1,"description":{"String":"","Valid":false},"payment_date":"2020-04-01","payment":1000,"split_type":"shares","splits":[{"member_id":1,"value":1,"amount":250},{"member_id":2,"value":3,"amount":750}]}` + "\n",
+		},
+		{
+			name:       "Bad request error groupID is String",
+			strGroupID: "string",
+			body:       `{}`,
+			wantCode:   http.StatusBadRequest,
+			wantBody:   `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:         "Unprocessable entity error member not in group",
+			strGroupID:   "1",
+			body:         `{"payer_member_id":9}`,
+			param:        &usecase.CreateGroupPaymentParam{PayerMemberID: 9},
+			payment:      &usecase.GroupPayment{},
+			useCaseError: usecase.UnprocessableEntityError{Field: "payer_member_id"},
+			wantCode:     http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockGroupUseCase{}
+			mock.On("CreatePayment", tt.param, 1, 1).Return(tt.payment, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			h := rest.NewGroupsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", "1")
+			rctx.URLParams.Add("group_id", tt.strGroupID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.CreatePayment(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("CreatePayment() mismatch status code (-want +got):\n%s", diff)
+			}
+			if tt.wantBody == "" {
+				return
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("CreatePayment() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_groupsHandler_GetSettlement(t *testing.T) {
+	tests := []struct {
+		name         string
+		settlement   *usecase.GroupSettlement
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name: "Success",
+			settlement: &usecase.GroupSettlement{
+				GroupID: 1,
+				Total:   3000,
+				Members: []*usecase.GroupBalance{
+					{MemberID: 1, Name: "A", Paid: 3000, Burden: 1000, Balance: 2000},
+					{MemberID: 2, Name: "B", Burden: 1000, Balance: -1000},
+					{MemberID: 3, Name: "C", Burden: 1000, Balance: -1000},
+				},
+				Transfers: []*usecase.GroupTransfer{
+					{FromMemberID: 2, ToMemberID: 1, Amount: 1000},
+					{FromMemberID: 3, ToMemberID: 1, Amount: 1000},
+				},
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"group_id":1,"total":3000,"members":[{"member_id":1,"name":"A","paid":3000,"burden":1000,"balance":2000},{"member_id":2,"name":"B","paid":0,"burden":1000,"balance":-1000},{"member_id":3,"name":"C","paid":0,"burden":1000,"balance":-1000}],"transfers":[{"from_member_id":2,"to_member_id":1,"amount":1000},{"from_member_id":3,"to_member_id":1,"amount":1000}]}` + "\n",
+		},
+		{
+			name:         "Not found error other user's group",
+			settlement:   &usecase.GroupSettlement{},
+			useCaseError: usecase.NotFoundError{},
+			wantCode:     http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockGroupUseCase{}
+			mock.On("Settle", 1, 1).Return(tt.settlement, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewGroupsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", "1")
+			rctx.URLParams.Add("group_id", "1")
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.GetSettlement(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("GetSettlement() mismatch status code (-want +got):\n%s", diff)
+			}
+			if tt.wantBody == "" {
+				return
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("GetSettlement() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type mockGroupUseCase struct {
+	mock.Mock
+}
+
+func (m *mockGroupUseCase) GetData(ctx context.Context, userID int) ([]*usecase.Group, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).([]*usecase.Group), ret.Error(1)
+}
+
+func (m *mockGroupUseCase) Get(ctx context.Context, userID, groupID int) (*usecase.Group, error) {
+	ret := m.Called(userID, groupID)
+	return ret.Get(0).(*usecase.Group), ret.Error(1)
+}
+
+func (m *mockGroupUseCase) Create(ctx context.Context, param *usecase.CreateGroupParam, userID int) (*usecase.Group, error) {
+	ret := m.Called(param, userID)
+	return ret.Get(0).(*usecase.Group), ret.Error(1)
+}
+
+func (m *mockGroupUseCase) DeleteByID(ctx context.Context, userID, groupID int) error {
+	ret := m.Called(userID, groupID)
+	return ret.Error(0)
+}
+
+func (m *mockGroupUseCase) AddMember(ctx context.Context, param *usecase.CreateGroupMemberParam, userID, groupID int) (*usecase.GroupMember, error) {
+	ret := m.Called(param, userID, groupID)
+	return ret.Get(0).(*usecase.GroupMember), ret.Error(1)
+}
+
+func (m *mockGroupUseCase) GetPayments(ctx context.Context, userID, groupID int) ([]*usecase.GroupPayment, error) {
+	ret := m.Called(userID, groupID)
+	return ret.Get(0).([]*usecase.GroupPayment), ret.Error(1)
+}
+
+func (m *mockGroupUseCase) CreatePayment(ctx context.Context, param *usecase.CreateGroupPaymentParam, userID, groupID int) (*usecase.GroupPayment, error) {
+	ret := m.Called(param, userID, groupID)
+	return ret.Get(0).(*usecase.GroupPayment), ret.Error(1)
+}
+
+func (m *mockGroupUseCase) DeletePayment(ctx context.Context, userID, groupID, groupPaymentID int) error {
+	ret := m.Called(userID, groupID, groupPaymentID)
+	return ret.Error(0)
+}
+
+func (m *mockGroupUseCase) Settle(ctx context.Context, userID, groupID int) (*usecase.GroupSettlement, error) {
+	ret := m.Called(userID, groupID)
+	return ret.Get(0).(*usecase.GroupSettlement), ret.Error(1)
+}
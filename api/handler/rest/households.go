@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"github.com/warikan/api/usecase"
+)
+
+type HouseholdsHandler interface {
+	GetData(http.ResponseWriter, *http.Request)
+	Invite(http.ResponseWriter, *http.Request)
+	Accept(http.ResponseWriter, *http.Request)
+}
+
+type householdsHandler struct {
+	useCase usecase.HouseholdUseCase
+}
+
+func NewHouseholdsHandler(u usecase.HouseholdUseCase) HouseholdsHandler {
+	return &householdsHandler{
+		useCase: u,
+	}
+}
+
+func (h *householdsHandler) GetData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	res, err := h.useCase.Get(r.Context(), userID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		internalServerError(w, r, "")
+	}
+}
+
+func (h *householdsHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	res, err := h.useCase.Invite(r.Context(), userID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		internalServerError(w, r, "")
+	}
+}
+
+// Accept : パスの{user_id}は招待を承認するユーザー。承認後は家計簿のledger_user_idで支払いなどを扱う
+func (h *householdsHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	req := usecase.AcceptHouseholdParam{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	res, err := h.useCase.Accept(r.Context(), &req, userID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		internalServerError(w, r, "")
+	}
+}
@@ -0,0 +1,224 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/warikan/api/handler/rest"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_householdsHandler_GetData(t *testing.T) {
+	tests := []struct {
+		name         string
+		strUserID    string
+		household    *usecase.Household
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:      "Success",
+			strUserID: "2",
+			household: &usecase.Household{
+				ID:           1,
+				LedgerUserID: 1,
+				Members: []*usecase.HouseholdMember{
+					{UserID: 1, PayerID: 1},
+					{UserID: 2, PayerID: 2},
+				},
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"id":1,"ledger_user_id":1,"members":[{"user_id":1,"payer_id":1},{"user_id":2,"payer_id":2}]}` + "\n",
+		},
+		{
+			name:      "Bad request error userID is String",
+			strUserID: "string",
+			wantCode:  http.StatusBadRequest,
+			wantBody:  `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:         "Not found error no household",
+			strUserID:    "2",
+			household:    &usecase.Household{},
+			useCaseError: usecase.NotFoundError{},
+			wantCode:     http.StatusNotFound,
+			wantBody:     `{"code":"not_found","msg":"ページが見つかりません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockHouseholdUseCase{}
+			mock.On("Get", 2).Return(tt.household, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewHouseholdsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.strUserID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.GetData(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("GetData() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("GetData() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_householdsHandler_Invite(t *testing.T) {
+	tests := []struct {
+		name         string
+		invitation   *usecase.HouseholdInvitation
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:       "Success",
+			invitation: &usecase.HouseholdInvitation{Token: "token", ExpiresAt: time.Date(2020, time.April, 8, 0, 0, 0, 0, time.UTC)},
+			wantCode:   http.StatusCreated,
+			wantBody:   `{"token":"token","expires_at":"2020-04-08T00:00:00Z"}` + "\n",
+		},
+		{
+			name:         "Conflict error partner already joined",
+			invitation:   &usecase.HouseholdInvitation{},
+			useCaseError: usecase.ConflictError{},
+			wantCode:     http.StatusConflict,
+			wantBody:     `{"code":"conflict","msg":"競合が発生しました。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockHouseholdUseCase{}
+			mock.On("Invite", 1).Return(tt.invitation, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewHouseholdsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", "1")
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.Invite(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("Invite() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("Invite() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_householdsHandler_Accept(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		param        *usecase.AcceptHouseholdParam
+		household    *usecase.Household
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:  "Success",
+			body:  `{"token":"token"}`,
+			param: &usecase.AcceptHouseholdParam{Token: "token"},
+			household: &usecase.Household{
+				ID:           1,
+				LedgerUserID: 1,
+				Members: []*usecase.HouseholdMember{
+					{UserID: 1, PayerID: 1},
+					{UserID: 2, PayerID: 2},
+				},
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"id":1,"ledger_user_id":1,"members":[{"user_id":1,"payer_id":1},{"user_id":2,"payer_id":2}]}` + "\n",
+		},
+		{
+			name:     "Bad request error invalid json",
+			body:     `{"token":`,
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:         "Not found error expired token",
+			body:         `{"token":"expired"}`,
+			param:        &usecase.AcceptHouseholdParam{Token: "expired"},
+			household:    &usecase.Household{},
+			useCaseError: usecase.NotFoundError{},
+			wantCode:     http.StatusNotFound,
+			wantBody:     `{"code":"not_found","msg":"ページが見つかりません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockHouseholdUseCase{}
+			mock.On("Accept", tt.param, 2).Return(tt.household, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			h := rest.NewHouseholdsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", "2")
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.Accept(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("Accept() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("Accept() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type mockHouseholdUseCase struct {
+	mock.Mock
+}
+
+func (m *mockHouseholdUseCase) Get(ctx context.Context, userID int) (*usecase.Household, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).(*usecase.Household), ret.Error(1)
+}
+
+func (m *mockHouseholdUseCase) Invite(ctx context.Context, userID int) (*usecase.HouseholdInvitation, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).(*usecase.HouseholdInvitation), ret.Error(1)
+}
+
+func (m *mockHouseholdUseCase) Accept(ctx context.Context, param *usecase.AcceptHouseholdParam, userID int) (*usecase.Household, error) {
+	ret := m.Called(param, userID)
+	return ret.Get(0).(*usecase.Household), ret.Error(1)
+}
@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	languageJa = "ja"
+	languageEn = "en"
+
+	defaultLanguage = languageJa
+)
+
+const languageContextKey contextKey = "language"
+
+// Language : Accept-Languageヘッダーから応答の言語を決め、リクエストのコンテキストとContent-Languageヘッダーに設定する。
+// ログイン中のユーザーが言語を設定している場合は、Authorizeでその言語に置き換える
+func Language(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, withLanguage(w, r, negotiateLanguage(r.Header.Get("Accept-Language"))))
+	})
+}
+
+// withLanguage : 応答の言語をlangにしたリクエストを返す。対応していない言語の場合はrをそのまま返す
+func withLanguage(w http.ResponseWriter, r *http.Request, lang string) *http.Request {
+	if _, ok := catalogs[lang]; !ok {
+		return r
+	}
+	w.Header().Set("Content-Language", lang)
+	return r.WithContext(context.WithValue(r.Context(), languageContextKey, lang))
+}
+
+// languageFromContext : 応答の言語を返す。Languageを通っていない場合は日本語
+func languageFromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(languageContextKey).(string); ok {
+		return lang
+	}
+	return defaultLanguage
+}
+
+// negotiateLanguage : 対応している言語のうち品質値が最も高いものを返す。対応している言語がない場合は日本語
+func negotiateLanguage(acceptLanguage string) string {
+	lang := defaultLanguage
+	best := 0.0
+	for _, s := range strings.Split(acceptLanguage, ",") {
+		parts := strings.Split(strings.TrimSpace(s), ";")
+		tag := strings.ToLower(strings.SplitN(strings.TrimSpace(parts[0]), "-", 2)[0])
+		if _, ok := catalogs[tag]; !ok {
+			continue
+		}
+
+		q := 1.0
+		for _, p := range parts[1:] {
+			p = strings.TrimSpace(p)
+			if !strings.HasPrefix(p, "q=") {
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimPrefix(p, "q="), 64)
+			if err != nil {
+				v = 0
+			}
+			q = v
+		}
+		if q > best {
+			lang = tag
+			best = q
+		}
+	}
+	return lang
+}
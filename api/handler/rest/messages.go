@@ -0,0 +1,95 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// catalog : 1つの言語のメッセージ。errorsはエラーコードごと、rulesは検証ルールごとのメッセージで、%sにはルールの引数が入る。
+// paymentsCSVHeaderは支払いのCSVエクスポートの見出し行
+type catalog struct {
+	errors            map[string]string
+	rules             map[string]string
+	defaultRule       string
+	paymentsCSVHeader []string
+}
+
+var catalogs = map[string]*catalog{
+	languageJa: {
+		errors: map[string]string{
+			badRequestCode:          "要求の形式が正しくありません。",
+			invalidParamCode:        "入力内容に誤りがあります。",
+			unauthorizedCode:        "サーバーとの認証に失敗しました。再度ログインしてください。",
+			forbiddenCode:           "アクセス権限がありません。",
+			notFoundCode:            "ページが見つかりません。",
+			conflictCode:            "競合が発生しました。",
+			unprocessableEntityCode: "入力内容に誤りがあります。",
+			internalServerErrorCode: "システム内部エラーが発生しました。",
+		},
+		rules: map[string]string{
+			"required":      "必須項目です。",
+			"gt":            "%sより大きい値を入力してください。",
+			"min":           "%s以上の値を入力してください。",
+			"max":           "%s以下の値を入力してください。",
+			"email":         "メールアドレスの形式で入力してください。",
+			"maxfuture":     "%s日より先の日付は入力できません。",
+			"format":        "形式が正しくありません。",
+			"ltefield":      "%s以下の値を入力してください。",
+			"gtefield":      "%s以降の日付を入力してください。",
+			"excluded_with": "%sと同時に指定できません。",
+			"exists":        "存在しない値が指定されています。",
+			"oneof":         "%sのいずれかを指定してください。",
+		},
+		defaultRule:       "入力内容に誤りがあります。",
+		paymentsCSVHeader: []string{"支払日", "カテゴリー", "支払者", "金額", "内容"},
+	},
+	languageEn: {
+		errors: map[string]string{
+			badRequestCode:          "The request is malformed.",
+			invalidParamCode:        "Some of the input values are invalid.",
+			unauthorizedCode:        "Authentication failed. Please log in again.",
+			forbiddenCode:           "You do not have permission to access this resource.",
+			notFoundCode:            "The page was not found.",
+			conflictCode:            "A conflict occurred.",
+			unprocessableEntityCode: "Some of the input values are invalid.",
+			internalServerErrorCode: "An internal server error occurred.",
+		},
+		rules: map[string]string{
+			"required":      "This field is required.",
+			"gt":            "Enter a value greater than %s.",
+			"min":           "Enter a value of %s or more.",
+			"max":           "Enter a value of %s or less.",
+			"email":         "Enter a valid email address.",
+			"maxfuture":     "The date cannot be more than %s days in the future.",
+			"format":        "The format is invalid.",
+			"ltefield":      "Enter a value no greater than %s.",
+			"gtefield":      "Enter a date on or after %s.",
+			"excluded_with": "Cannot be specified together with %s.",
+			"exists":        "The specified value does not exist.",
+			"oneof":         "Specify one of: %s.",
+		},
+		defaultRule:       "The value is invalid.",
+		paymentsCSVHeader: []string{"Date", "Category", "Payer", "Amount", "Description"},
+	},
+}
+
+// responseCatalog : リクエストのコンテキストの言語のメッセージを返す
+func responseCatalog(r *http.Request) *catalog {
+	return catalogs[languageFromContext(r.Context())]
+}
+
+func (c *catalog) errorMessage(code string) string {
+	return c.errors[code]
+}
+
+func (c *catalog) ruleMessage(rule, param string) string {
+	m, ok := c.rules[rule]
+	if !ok {
+		return c.defaultRule
+	}
+	if strings.Contains(m, "%s") {
+		return fmt.Sprintf(m, param)
+	}
+	return m
+}
@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/warikan/api/usecase"
+)
+
+type contextKey string
+
+const userIDContextKey contextKey = "user_id"
+
+// UserIDFromContext : Authorizeで認証されたユーザーIDを返す
+func UserIDFromContext(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(userIDContextKey).(int)
+	return userID, ok
+}
+
+type AuthMiddleware struct {
+	useCase usecase.AuthUseCase
+}
+
+func NewAuthMiddleware(u usecase.AuthUseCase) *AuthMiddleware {
+	return &AuthMiddleware{
+		useCase: u,
+	}
+}
+
+// Authorize : Authorizationヘッダーのトークンを検証し、パスの{user_id}がログイン中のユーザーと一致しない場合は拒否する
+func (m *AuthMiddleware) Authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, r, ok := m.authenticate(w, r)
+		if !ok {
+			return
+		}
+
+		if strUserID := chi.URLParam(r, "user_id"); strUserID != "" && strUserID != strconv.Itoa(userID) {
+			forbiddenError(w, r, "")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDContextKey, userID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// AuthorizeLedger : Authorizeと同じくトークンを検証し、パスの{user_id}がログイン中のユーザーの家計簿でない場合は拒否する。
+// パートナーとして参加している家計簿は、作成したユーザーの{user_id}で扱える
+func (m *AuthMiddleware) AuthorizeLedger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, r, ok := m.authenticate(w, r)
+		if !ok {
+			return
+		}
+
+		if strUserID := chi.URLParam(r, "user_id"); strUserID != "" {
+			ledgerUserID, err := strconv.Atoi(strUserID)
+			if err != nil {
+				forbiddenError(w, r, "")
+				return
+			}
+			if err := m.useCase.AuthorizeLedger(r.Context(), userID, ledgerUserID); err != nil {
+				httpError(w, r, err, "")
+				return
+			}
+		}
+
+		ctx := context.WithValue(r.Context(), userIDContextKey, userID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authenticate : トークンを検証してユーザーIDを返す。ユーザーが言語を設定している場合は、応答の言語をその言語にしたリクエストを返す。
+// 検証に失敗した場合はエラーを書き込んでfalseを返す
+func (m *AuthMiddleware) authenticate(w http.ResponseWriter, r *http.Request) (int, *http.Request, bool) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		unauthorizedError(w, r, "")
+		return 0, r, false
+	}
+
+	user, err := m.useCase.Authenticate(token)
+	if err != nil {
+		httpError(w, r, err, "")
+		return 0, r, false
+	}
+
+	return user.ID, withLanguage(w, r, user.Language), true
+}
@@ -0,0 +1,317 @@
+package rest_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/handler/rest"
+	"github.com/warikan/api/usecase"
+)
+
+func TestAuthMiddleware_Authorize(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		userID        int
+		useCaseError  error
+		wantCode      int
+		wantBody      string
+	}{
+		{
+			name:          "Success",
+			path:          "/users/1/payments",
+			authorization: "Bearer token",
+			userID:        1,
+			wantCode:      http.StatusOK,
+			wantBody:      "user_id=1",
+		},
+		{
+			name:     "Unauthorized error no token",
+			path:     "/users/1/payments",
+			wantCode: http.StatusUnauthorized,
+			wantBody: `{"code":"unauthorized","msg":"サーバーとの認証に失敗しました。再度ログインしてください。"}` + "\n",
+		},
+		{
+			name:          "Unauthorized error token expired",
+			path:          "/users/1/payments",
+			authorization: "Bearer token",
+			useCaseError:  usecase.TokenExpiredError{},
+			wantCode:      http.StatusUnauthorized,
+			wantBody:      `{"code":"unauthorized","msg":"サーバーとの認証に失敗しました。再度ログインしてください。"}` + "\n",
+		},
+		{
+			name:          "Forbidden error other user",
+			path:          "/users/2/payments",
+			authorization: "Bearer token",
+			userID:        1,
+			wantCode:      http.StatusForbidden,
+			wantBody:      `{"code":"forbidden","msg":"アクセス権限がありません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockAuthUseCase{}
+			mock.On("Authenticate", "token").Return(&model.User{ID: tt.userID}, tt.useCaseError)
+
+			m := rest.NewAuthMiddleware(mock)
+			router := chi.NewRouter()
+			router.Route("/users/{user_id}", func(r chi.Router) {
+				r.Use(m.Authorize)
+				r.Get("/payments", func(w http.ResponseWriter, r *http.Request) {
+					userID, _ := rest.UserIDFromContext(r.Context())
+					fmt.Fprintf(w, "user_id=%d", userID)
+				})
+			})
+
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("Authorize() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("Authorize() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_AuthorizeLedger(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		userID       int
+		ledgerUserID int
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:         "Success own ledger",
+			path:         "/users/1/payments",
+			userID:       1,
+			ledgerUserID: 1,
+			wantCode:     http.StatusOK,
+			wantBody:     "user_id=1",
+		},
+		{
+			name:         "Success partner's ledger",
+			path:         "/users/1/payments",
+			userID:       2,
+			ledgerUserID: 1,
+			wantCode:     http.StatusOK,
+			wantBody:     "user_id=2",
+		},
+		{
+			name:         "Forbidden error other household",
+			path:         "/users/3/payments",
+			userID:       2,
+			ledgerUserID: 3,
+			useCaseError: usecase.ForbiddenError{},
+			wantCode:     http.StatusForbidden,
+			wantBody:     `{"code":"forbidden","msg":"アクセス権限がありません。"}` + "\n",
+		},
+		{
+			name:     "Forbidden error userID is String",
+			path:     "/users/string/payments",
+			userID:   2,
+			wantCode: http.StatusForbidden,
+			wantBody: `{"code":"forbidden","msg":"アクセス権限がありません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockAuthUseCase{}
+			mock.On("Authenticate", "token").Return(&model.User{ID: tt.userID}, nil)
+			mock.On("AuthorizeLedger", tt.userID, tt.ledgerUserID).Return(tt.useCaseError)
+
+			m := rest.NewAuthMiddleware(mock)
+			router := chi.NewRouter()
+			router.Route("/users/{user_id}", func(r chi.Router) {
+				r.Use(m.AuthorizeLedger)
+				r.Get("/payments", func(w http.ResponseWriter, r *http.Request) {
+					userID, _ := rest.UserIDFromContext(r.Context())
+					fmt.Fprintf(w, "user_id=%d", userID)
+				})
+			})
+
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			r.Header.Set("Authorization", "Bearer token")
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("AuthorizeLedger() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("AuthorizeLedger() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		name                string
+		acceptLanguage      string
+		wantContentLanguage string
+		wantBody            string
+	}{
+		{
+			name:                "Default japanese",
+			acceptLanguage:      "",
+			wantContentLanguage: "ja",
+			wantBody:            `{"code":"unauthorized","msg":"サーバーとの認証に失敗しました。再度ログインしてください。"}` + "\n",
+		},
+		{
+			name:                "English",
+			acceptLanguage:      "en-US,en;q=0.9",
+			wantContentLanguage: "en",
+			wantBody:            `{"code":"unauthorized","msg":"Authentication failed. Please log in again."}` + "\n",
+		},
+		{
+			name:                "Highest quality",
+			acceptLanguage:      "en;q=0.5, ja;q=0.8",
+			wantContentLanguage: "ja",
+			wantBody:            `{"code":"unauthorized","msg":"サーバーとの認証に失敗しました。再度ログインしてください。"}` + "\n",
+		},
+		{
+			name:                "Unsupported language falls back to japanese",
+			acceptLanguage:      "fr-FR,de;q=0.9",
+			wantContentLanguage: "ja",
+			wantBody:            `{"code":"unauthorized","msg":"サーバーとの認証に失敗しました。再度ログインしてください。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := rest.NewAuthMiddleware(&mockAuthUseCase{})
+			router := chi.NewRouter()
+			router.Use(rest.Language)
+			router.With(m.Authorize).Get("/users/1/payments", func(w http.ResponseWriter, r *http.Request) {})
+
+			r := httptest.NewRequest(http.MethodGet, "/users/1/payments", nil)
+			if tt.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, r)
+
+			if diff := cmp.Diff(tt.wantContentLanguage, rr.Header().Get("Content-Language")); diff != "" {
+				t.Errorf("Language() mismatch Content-Language (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("Language() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLanguage_UserLanguage(t *testing.T) {
+	tests := []struct {
+		name                string
+		acceptLanguage      string
+		language            string
+		wantContentLanguage string
+		wantBody            string
+	}{
+		{
+			name:                "User's language over Accept-Language",
+			acceptLanguage:      "ja",
+			language:            "en",
+			wantContentLanguage: "en",
+			wantBody:            `{"code":"forbidden","msg":"You do not have permission to access this resource."}` + "\n",
+		},
+		{
+			name:                "Accept-Language when user's language is not set",
+			acceptLanguage:      "en",
+			wantContentLanguage: "en",
+			wantBody:            `{"code":"forbidden","msg":"You do not have permission to access this resource."}` + "\n",
+		},
+		{
+			name:                "Japanese user with English Accept-Language",
+			acceptLanguage:      "en",
+			language:            "ja",
+			wantContentLanguage: "ja",
+			wantBody:            `{"code":"forbidden","msg":"アクセス権限がありません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockAuthUseCase{}
+			mock.On("Authenticate", "token").Return(&model.User{ID: 1, Language: tt.language}, nil)
+
+			m := rest.NewAuthMiddleware(mock)
+			router := chi.NewRouter()
+			router.Use(rest.Language)
+			router.With(m.Authorize).Get("/users/{user_id}/payments", func(w http.ResponseWriter, r *http.Request) {})
+
+			r := httptest.NewRequest(http.MethodGet, "/users/2/payments", nil)
+			r.Header.Set("Authorization", "Bearer token")
+			r.Header.Set("Accept-Language", tt.acceptLanguage)
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, r)
+
+			if diff := cmp.Diff(tt.wantContentLanguage, rr.Header().Get("Content-Language")); diff != "" {
+				t.Errorf("Authorize() mismatch Content-Language (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("Authorize() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLanguage_InvalidParam(t *testing.T) {
+	mock := &mockPaymentUseCase{}
+	mock.On("GetByID", 1, 1).Return(&usecase.PaymentDetail{}, usecase.InvalidParamError{Fields: []usecase.FieldError{
+		{Field: "payment", Rule: "max", Param: "10000000"},
+		{Field: "payment_date", Rule: "unknown"},
+	}})
+
+	h := rest.NewPaymentsHandler(mock)
+	router := chi.NewRouter()
+	router.Use(rest.Language)
+	router.Get("/{user_id}/payments/{payment_id}", h.GetDetail)
+
+	r := httptest.NewRequest(http.MethodGet, "/1/payments/1", nil)
+	r.Header.Set("Accept-Language", "en")
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, r)
+
+	want := `{"code":"invalid_param","msg":"Some of the input values are invalid.","fields":[{"field":"payment","rule":"max","msg":"Enter a value of 10000000 or less."},{"field":"payment_date","rule":"unknown","msg":"The value is invalid."}]}` + "\n"
+	if diff := cmp.Diff(want, rr.Body.String()); diff != "" {
+		t.Errorf("Language() mismatch body (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,49 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/usecase"
+)
+
+type PayersHandler interface {
+	GetData(http.ResponseWriter, *http.Request)
+}
+
+type payersHandler struct {
+	useCase usecase.PayerUseCase
+}
+
+func NewPayersHandler(u usecase.PayerUseCase) PayersHandler {
+	return &payersHandler{
+		useCase: u,
+	}
+}
+
+type payerHandlerResponse struct {
+	Payers []*model.Payer `json:"payers"`
+}
+
+func (h *payersHandler) GetData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	payers, err := h.useCase.GetData(userID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	res := payerHandlerResponse{Payers: payers}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		internalServerError(w, r, "")
+	}
+}
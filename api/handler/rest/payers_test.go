@@ -0,0 +1,92 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/google/go-cmp/cmp"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/handler/rest"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_payersHandler_GetData(t *testing.T) {
+	payerUserID := 1
+
+	tests := []struct {
+		name         string
+		strUserID    string
+		userID       int
+		payers       []*model.Payer
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:      "Success",
+			strUserID: "1",
+			userID:    1,
+			payers: []*model.Payer{
+				{ID: 1, Name: "たろう", UserID: &payerUserID},
+				{ID: 2, Name: "はなこ"},
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"payers":[{"id":1,"name":"たろう","user_id":1},{"id":2,"name":"はなこ","user_id":null}]}` + "\n",
+		},
+		{
+			name:      "Bad request error userID is String",
+			strUserID: "string",
+			wantCode:  http.StatusBadRequest,
+			wantBody:  `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:         "Not found error",
+			strUserID:    "1",
+			userID:       1,
+			payers:       []*model.Payer{},
+			useCaseError: usecase.NotFoundError{},
+			wantCode:     http.StatusNotFound,
+			wantBody:     `{"code":"not_found","msg":"ページが見つかりません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockPayerUseCase{}
+			mock.On("GetData", tt.userID).Return(tt.payers, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewPayersHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.strUserID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.GetData(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("GetData() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("GetData() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type mockPayerUseCase struct {
+	mock.Mock
+}
+
+func (m *mockPayerUseCase) GetData(userID int) ([]*model.Payer, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).([]*model.Payer), ret.Error(1)
+}
@@ -0,0 +1,137 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+
+	"github.com/warikan/api/usecase"
+	"github.com/warikan/api/usecase/util"
+)
+
+type PaymentDraftsHandler interface {
+	GetData(http.ResponseWriter, *http.Request)
+	Import(http.ResponseWriter, *http.Request)
+	Confirm(http.ResponseWriter, *http.Request)
+	DeleteData(http.ResponseWriter, *http.Request)
+}
+
+type paymentDraftsHandler struct {
+	useCase usecase.PaymentDraftUseCase
+}
+
+func NewPaymentDraftsHandler(u usecase.PaymentDraftUseCase) PaymentDraftsHandler {
+	return &paymentDraftsHandler{
+		useCase: u,
+	}
+}
+
+func (h *paymentDraftsHandler) GetData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	res, err := h.useCase.GetData(r.Context(), userID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		internalServerError(w, r, "")
+	}
+}
+
+// Import : 明細の形式はformatで指定する。明細はCSVの取り込みと同じく、リクエストボディかmultipart/form-dataのfileに指定する
+func (h *paymentDraftsHandler) Import(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		httpError(w, r, usecase.InvalidParamError{Fields: []usecase.FieldError{{Field: "format", Rule: "required"}}}, "")
+		return
+	}
+
+	body, err := importBody(w, r)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+	defer body.Close()
+
+	res, err := h.useCase.Import(r.Context(), userID, format, body)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		internalServerError(w, r, "")
+	}
+}
+
+func (h *paymentDraftsHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	strPaymentDraftID := chi.URLParam(r, "payment_draft_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+	paymentDraftID, err := strconv.Atoi(strPaymentDraftID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	req := usecase.ConfirmPaymentDraftParam{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+	req.CreatedBy, _ = UserIDFromContext(r.Context())
+
+	resp, err := h.useCase.Confirm(r.Context(), &req, userID, paymentDraftID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	w.Header().Set("ETag", etag(util.EncodeVersion(resp.UpdatedAt)))
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		httpError(w, r, err, "")
+	}
+}
+
+func (h *paymentDraftsHandler) DeleteData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	strPaymentDraftID := chi.URLParam(r, "payment_draft_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+	paymentDraftID, err := strconv.Atoi(strPaymentDraftID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	if err := h.useCase.DeleteByID(r.Context(), userID, paymentDraftID); err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
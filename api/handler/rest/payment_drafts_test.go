@@ -0,0 +1,331 @@
+package rest_test
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/go-cmp/cmp"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/handler/rest"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_paymentDraftsHandler_GetData(t *testing.T) {
+	tests := []struct {
+		name         string
+		strUserID    string
+		drafts       []*usecase.PaymentDraft
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:      "Success",
+			strUserID: "1",
+			drafts: []*usecase.PaymentDraft{
+				{ID: 1, Source: "ofx", Description: sql.NullString{String: "スーパー", Valid: true}, PaymentDate: "2020-04-01", Payment: 1234, CreatedAt: "2020-04-02 12:00:00"},
+			},
+			wantCode: http.StatusOK,
+			wantBody: `[{"id":1,"source":"ofx","description":{"String":"スーパー","Valid":true},"payment_date":"2020-04-01","payment":1234,"created_at":"2020-04-02 12:00:00"}]` + "\n",
+		},
+		{
+			name:      "Bad request error userID is String",
+			strUserID: "string",
+			wantCode:  http.StatusBadRequest,
+			wantBody:  `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:         "Internal server error",
+			strUserID:    "1",
+			drafts:       []*usecase.PaymentDraft{},
+			useCaseError: usecase.InternalServerError{},
+			wantCode:     http.StatusInternalServerError,
+			wantBody:     `{"code":"internal_server_error","msg":"システム内部エラーが発生しました。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockPaymentDraftUseCase{}
+			mock.On("GetData", 1).Return(tt.drafts, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewPaymentDraftsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.strUserID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.GetData(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("GetData() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("GetData() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_paymentDraftsHandler_Import(t *testing.T) {
+	const body = "!Type:CCard\nD04/01/2020\nT-1234\n^\n"
+
+	tests := []struct {
+		name         string
+		query        string
+		format       string
+		result       *usecase.PaymentDraftImportResult
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:   "Success",
+			query:  "?format=qif",
+			format: "qif",
+			result: &usecase.PaymentDraftImportResult{
+				Drafts:  []*usecase.PaymentDraft{{ID: 1, Source: "qif", PaymentDate: "2020-04-01", Payment: 1234, CreatedAt: "2020-04-02 12:00:00"}},
+				Skipped: 1,
+			},
+			wantCode: http.StatusCreated,
+			wantBody: `{"drafts":[{"id":1,"source":"qif","description":{"String":"","Valid":false},"payment_date":"2020-04-01","payment":1234,"created_at":"2020-04-02 12:00:00"}],"skipped":1,"duplicates":0}` + "\n",
+		},
+		{
+			name:     "Invalid param error format is missing",
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"code":"invalid_param","msg":"入力内容に誤りがあります。","fields":[{"field":"format","rule":"required","msg":"必須項目です。"}]}` + "\n",
+		},
+		{
+			name:         "Invalid param error unknown format",
+			query:        "?format=xlsx",
+			format:       "xlsx",
+			useCaseError: usecase.InvalidParamError{Fields: []usecase.FieldError{{Field: "format", Rule: "oneof", Param: "ofx qif"}}},
+			wantCode:     http.StatusBadRequest,
+			wantBody:     `{"code":"invalid_param","msg":"入力内容に誤りがあります。","fields":[{"field":"format","rule":"oneof","msg":"ofx qifのいずれかを指定してください。"}]}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockPaymentDraftUseCase{}
+			mock.On("Import", 1, tt.format, body).Return(tt.result, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodPost, "/1/payment_drafts/import"+tt.query, strings.NewReader(body))
+			rr := httptest.NewRecorder()
+			h := rest.NewPaymentDraftsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", "1")
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.Import(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("Import() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("Import() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_paymentDraftsHandler_Confirm(t *testing.T) {
+	updatedAt := time.Date(2020, time.April, 2, 3, 4, 5, 123456000, time.UTC)
+
+	tests := []struct {
+		name              string
+		strPaymentDraftID string
+		body              string
+		param             *usecase.ConfirmPaymentDraftParam
+		payment           *model.Payment
+		useCaseError      error
+		wantCode          int
+		wantETag          string
+		wantBody          string
+	}{
+		{
+			name:              "Success",
+			strPaymentDraftID: "1",
+			body:              `{"category_id":2,"payer_id":1}`,
+			param:             &usecase.ConfirmPaymentDraftParam{CategoryID: 2, PayerID: 1},
+			payment: &model.Payment{
+				ID:          100,
+				UserID:      1,
+				CategoryID:  2,
+				PayerID:     1,
+				Description: sql.NullString{String: "スーパー", Valid: true},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+				Status:      "approved",
+				CreatedAt:   updatedAt,
+				UpdatedAt:   updatedAt,
+			},
+			wantCode: http.StatusCreated,
+			wantETag: `"1585796645123456"`,
+			wantBody: `{"id":100,"user_id":1,"category_id":2,"payer_id":1,"description":{"String":"スーパー","Valid":true},"payment_date":"2020-04-01T00:00:00Z","payment":1234,"status":"approved","created_by":{"Int64":0,"Valid":false},"created_at":"2020-04-02T03:04:05.123456Z","updated_at":"2020-04-02T03:04:05.123456Z"}` + "\n",
+		},
+		{
+			name:              "Bad request error paymentDraftID is String",
+			strPaymentDraftID: "string",
+			body:              `{"category_id":2,"payer_id":1}`,
+			wantCode:          http.StatusBadRequest,
+			wantBody:          `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:              "Bad request error invalid json",
+			strPaymentDraftID: "1",
+			body:              `{"category_id":"2"}`,
+			wantCode:          http.StatusBadRequest,
+			wantBody:          `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:              "Not found error",
+			strPaymentDraftID: "999",
+			body:              `{"category_id":2,"payer_id":1}`,
+			param:             &usecase.ConfirmPaymentDraftParam{CategoryID: 2, PayerID: 1},
+			payment:           &model.Payment{},
+			useCaseError:      usecase.NotFoundError{},
+			wantCode:          http.StatusNotFound,
+			wantBody:          `{"code":"not_found","msg":"ページが見つかりません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockPaymentDraftUseCase{}
+			mock.On("Confirm", tt.param, 1, 1).Return(tt.payment, tt.useCaseError)
+			mock.On("Confirm", tt.param, 1, 999).Return(tt.payment, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			h := rest.NewPaymentDraftsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", "1")
+			rctx.URLParams.Add("payment_draft_id", tt.strPaymentDraftID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.Confirm(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("Confirm() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantETag, rr.Header().Get("ETag")); diff != "" {
+				t.Errorf("Confirm() mismatch ETag (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("Confirm() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_paymentDraftsHandler_DeleteData(t *testing.T) {
+	tests := []struct {
+		name              string
+		strPaymentDraftID string
+		paymentDraftID    int
+		useCaseError      error
+		wantCode          int
+		wantBody          string
+	}{
+		{
+			name:              "Success",
+			strPaymentDraftID: "1",
+			paymentDraftID:    1,
+			wantCode:          http.StatusNoContent,
+			wantBody:          "",
+		},
+		{
+			name:              "Bad request error paymentDraftID is String",
+			strPaymentDraftID: "string",
+			wantCode:          http.StatusBadRequest,
+			wantBody:          `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:              "Forbidden error",
+			strPaymentDraftID: "2",
+			paymentDraftID:    2,
+			useCaseError:      usecase.ForbiddenError{},
+			wantCode:          http.StatusForbidden,
+			wantBody:          `{"code":"forbidden","msg":"アクセス権限がありません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockPaymentDraftUseCase{}
+			mock.On("DeleteByID", 1, tt.paymentDraftID).Return(tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewPaymentDraftsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", "1")
+			rctx.URLParams.Add("payment_draft_id", tt.strPaymentDraftID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.DeleteData(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("DeleteData() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("DeleteData() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type mockPaymentDraftUseCase struct {
+	mock.Mock
+}
+
+func (m *mockPaymentDraftUseCase) GetData(ctx context.Context, userID int) ([]*usecase.PaymentDraft, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).([]*usecase.PaymentDraft), ret.Error(1)
+}
+
+func (m *mockPaymentDraftUseCase) Import(ctx context.Context, userID int, format string, r io.Reader) (*usecase.PaymentDraftImportResult, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	ret := m.Called(userID, format, string(b))
+	return ret.Get(0).(*usecase.PaymentDraftImportResult), ret.Error(1)
+}
+
+func (m *mockPaymentDraftUseCase) Confirm(ctx context.Context, param *usecase.ConfirmPaymentDraftParam, userID, paymentDraftID int) (*model.Payment, error) {
+	ret := m.Called(param, userID, paymentDraftID)
+	return ret.Get(0).(*model.Payment), ret.Error(1)
+}
+
+func (m *mockPaymentDraftUseCase) DeleteByID(ctx context.Context, userID, paymentDraftID int) error {
+	ret := m.Called(userID, paymentDraftID)
+	return ret.Error(0)
+}
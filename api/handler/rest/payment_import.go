@@ -0,0 +1,135 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+
+	"github.com/warikan/api/usecase"
+)
+
+type PaymentImportHandler interface {
+	Import(http.ResponseWriter, *http.Request)
+}
+
+type paymentImportHandler struct {
+	useCase usecase.PaymentImportUseCase
+}
+
+func NewPaymentImportHandler(u usecase.PaymentImportUseCase) PaymentImportHandler {
+	return &paymentImportHandler{
+		useCase: u,
+	}
+}
+
+// maxImportSize : 取り込めるCSVファイルのサイズの上限(10MB)
+const maxImportSize = 10 << 20
+
+type importResponse struct {
+	DryRun     bool                 `json:"dry_run"`
+	Rows       int                  `json:"rows"`
+	Imported   int                  `json:"imported"`
+	Duplicates []int                `json:"duplicates"`
+	Errors     []*importRowResponse `json:"errors"`
+}
+
+type importRowResponse struct {
+	Row    int           `json:"row"`
+	Fields []*fieldError `json:"fields"`
+}
+
+// Import : CSVはリクエストボディにそのまま指定するか、multipart/form-dataのfileに指定する。
+// 行のエラーは取り込みの結果として200で返す
+func (h *paymentImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	dryRun := false
+	if s := r.URL.Query().Get("dry_run"); s != "" {
+		if dryRun, err = strconv.ParseBool(s); err != nil {
+			badRequestError(w, r, "")
+			return
+		}
+	}
+
+	body, err := importBody(w, r)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+	defer body.Close()
+
+	createdBy, _ := UserIDFromContext(r.Context())
+	res, err := h.useCase.Import(r.Context(), userID, createdBy, body, dryRun)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	c := responseCatalog(r)
+	resp := importResponse{
+		DryRun:     res.DryRun,
+		Rows:       res.Rows,
+		Imported:   res.Imported,
+		Duplicates: res.Duplicates,
+		Errors:     make([]*importRowResponse, 0, len(res.Errors)),
+	}
+	if resp.Duplicates == nil {
+		resp.Duplicates = []int{}
+	}
+	for _, e := range res.Errors {
+		row := &importRowResponse{Row: e.Row}
+		for _, f := range e.Fields {
+			row.Fields = append(row.Fields, &fieldError{Field: f.Field, Rule: f.Rule, Message: c.ruleMessage(f.Rule, f.Param)})
+		}
+		resp.Errors = append(resp.Errors, row)
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		internalServerError(w, r, "")
+	}
+}
+
+// importBody : サイズの上限を超えた場合はfileの項目エラーを返す
+func importBody(w http.ResponseWriter, r *http.Request) (io.ReadCloser, error) {
+	tooLarge := usecase.InvalidParamError{Fields: []usecase.FieldError{{Field: "file", Rule: "max", Param: "10MB"}}}
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxImportSize); err != nil {
+			if bodyTooLarge(err) {
+				return nil, tooLarge
+			}
+			return nil, usecase.BadRequestError{}
+		}
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, usecase.InvalidParamError{Fields: []usecase.FieldError{{Field: "file", Rule: "required"}}}
+		}
+		return f, nil
+	}
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		if bodyTooLarge(err) {
+			return nil, tooLarge
+		}
+		return nil, usecase.BadRequestError{}
+	}
+	return ioutil.NopCloser(bytes.NewReader(b)), nil
+}
+
+// bodyTooLarge : go1.13のMaxBytesReaderはエラーの型を公開していないため、メッセージで判定する
+func bodyTooLarge(err error) bool {
+	return strings.Contains(err.Error(), "request body too large")
+}
@@ -0,0 +1,139 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/google/go-cmp/cmp"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/warikan/api/handler/rest"
+	"github.com/warikan/api/usecase"
+)
+
+const importCSV = "支払日,カテゴリー,支払者,金額\n2020-04-01,食費,ユーザー,1200\n"
+
+func Test_paymentImportHandler_Import(t *testing.T) {
+	multipartBody := func(field string) (io.Reader, string) {
+		var b bytes.Buffer
+		mw := multipart.NewWriter(&b)
+		fw, _ := mw.CreateFormFile(field, "payments.csv")
+		// nolint:errcheck
+		fw.Write([]byte(importCSV))
+		mw.Close()
+		return &b, mw.FormDataContentType()
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		multipart    string
+		dryRun       bool
+		result       *usecase.ImportResult
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:     "Success",
+			result:   &usecase.ImportResult{Rows: 3, Imported: 2, Duplicates: []int{3}},
+			wantCode: http.StatusOK,
+			wantBody: `{"dry_run":false,"rows":3,"imported":2,"duplicates":[3],"errors":[]}` + "\n",
+		},
+		{
+			name:      "Success multipart dry run",
+			query:     "?dry_run=true",
+			multipart: "file",
+			dryRun:    true,
+			result:    &usecase.ImportResult{DryRun: true, Rows: 1, Imported: 1},
+			wantCode:  http.StatusOK,
+			wantBody:  `{"dry_run":true,"rows":1,"imported":1,"duplicates":[],"errors":[]}` + "\n",
+		},
+		{
+			name: "Success with row errors",
+			result: &usecase.ImportResult{Rows: 2, Errors: []*usecase.ImportRowError{
+				{Row: 3, Fields: []usecase.FieldError{{Field: "category_id", Rule: "exists"}, {Field: "payment", Rule: "max", Param: "10000000"}}},
+			}},
+			wantCode: http.StatusOK,
+			wantBody: `{"dry_run":false,"rows":2,"imported":0,"duplicates":[],"errors":[{"row":3,"fields":[` +
+				`{"field":"category_id","rule":"exists","msg":"存在しない値が指定されています。"},` +
+				`{"field":"payment","rule":"max","msg":"10000000以下の値を入力してください。"}]}]}` + "\n",
+		},
+		{
+			name:     "Bad request error dry_run is not bool",
+			query:    "?dry_run=yes",
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:      "Invalid param error file is missing",
+			multipart: "csv",
+			wantCode:  http.StatusBadRequest,
+			wantBody:  `{"code":"invalid_param","msg":"入力内容に誤りがあります。","fields":[{"field":"file","rule":"required","msg":"必須項目です。"}]}` + "\n",
+		},
+		{
+			name:         "Invalid param error missing column",
+			useCaseError: usecase.InvalidParamError{Fields: []usecase.FieldError{{Field: "payer_id", Rule: "required"}}},
+			wantCode:     http.StatusBadRequest,
+			wantBody:     `{"code":"invalid_param","msg":"入力内容に誤りがあります。","fields":[{"field":"payer_id","rule":"required","msg":"必須項目です。"}]}` + "\n",
+		},
+		{
+			name:         "Internal server error",
+			useCaseError: usecase.InternalServerError{},
+			wantCode:     http.StatusInternalServerError,
+			wantBody:     `{"code":"internal_server_error","msg":"システム内部エラーが発生しました。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockPaymentImportUseCase{}
+			mock.On("Import", 1, 0, importCSV, tt.dryRun).Return(tt.result, tt.useCaseError)
+
+			body, contentType := io.Reader(strings.NewReader(importCSV)), "text/csv"
+			if tt.multipart != "" {
+				body, contentType = multipartBody(tt.multipart)
+			}
+			r := httptest.NewRequest(http.MethodPost, "/1/payments/import"+tt.query, body)
+			r.Header.Set("Content-Type", contentType)
+			rr := httptest.NewRecorder()
+			h := rest.NewPaymentImportHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", "1")
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.Import(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("Import() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("Import() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type mockPaymentImportUseCase struct {
+	mock.Mock
+}
+
+func (m *mockPaymentImportUseCase) Import(ctx context.Context, userID, createdBy int, r io.Reader, dryRun bool) (*usecase.ImportResult, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	ret := m.Called(userID, createdBy, string(b), dryRun)
+	return ret.Get(0).(*usecase.ImportResult), ret.Error(1)
+}
@@ -1,20 +1,34 @@
 package rest
 
 import (
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi"
+	"go.uber.org/zap"
+
+	"github.com/warikan/api/domain/model"
 	"github.com/warikan/api/usecase"
+	"github.com/warikan/api/usecase/util"
+	"github.com/warikan/log"
 )
 
 type PaymentsHandler interface {
 	GetData(http.ResponseWriter, *http.Request)
+	GetDetail(http.ResponseWriter, *http.Request)
 	CreateData(http.ResponseWriter, *http.Request)
 	UpdateData(http.ResponseWriter, *http.Request)
 	DeleteData(http.ResponseWriter, *http.Request)
-	FetchDate(http.ResponseWriter, *http.Request)
+	Approve(http.ResponseWriter, *http.Request)
+	Reject(http.ResponseWriter, *http.Request)
+	FetchMonthlyCost(http.ResponseWriter, *http.Request)
+	Export(http.ResponseWriter, *http.Request)
 }
 
 type paymentsHandler struct {
@@ -28,39 +42,126 @@ func NewPaymentsHandler(u usecase.PaymentUseCase) PaymentsHandler {
 }
 
 type paymentHandlerResponse struct {
-	Payments []*usecase.Payment `json:"payments"`
+	Payments   []*usecase.Payment `json:"payments"`
+	NextCursor string             `json:"next_cursor"`
+	HasMore    bool               `json:"has_more"`
 }
 
 func (h *paymentsHandler) GetData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	param, err := getPaymentsParam(r.URL.Query())
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	page, err := h.useCase.GetData(r.Context(), userID, param)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	if page.HasMore {
+		w.Header().Set("Link", nextLink(r.URL, page.NextCursor))
+	}
+
+	res := paymentHandlerResponse{
+		Payments:   page.Payments,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+	}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		internalServerError(w, r, "")
+	}
+}
+
+// getPaymentsParam : 一覧とエクスポートの絞り込み条件をクエリパラメーターから取得する。数値の項目が数値でない場合はエラーを返す
+func getPaymentsParam(query url.Values) (*usecase.GetPaymentsParam, error) {
+	param := &usecase.GetPaymentsParam{
+		Cursor:      query.Get("cursor"),
+		Month:       query.Get("month"),
+		From:        query.Get("from"),
+		To:          query.Get("to"),
+		Description: query.Get("description"),
+		Status:      query.Get("status"),
+	}
+	for key, v := range map[string]*int{
+		"limit":       &param.Limit,
+		"category_id": &param.CategoryID,
+		"payer_id":    &param.PayerID,
+		"min_payment": &param.MinPayment,
+		"max_payment": &param.MaxPayment,
+	} {
+		s := query.Get(key)
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		*v = n
+	}
+	return param, nil
+}
+
+// nextLink : リクエストURLのcursorを置き換えた次のページのLinkヘッダーを返す
+func nextLink(u *url.URL, cursor string) string {
+	next := *u
+	query := next.Query()
+	query.Set("cursor", cursor)
+	next.RawQuery = query.Encode()
+	return fmt.Sprintf(`<%s>; rel="next"`, next.String())
+}
 
-	strCursor := r.URL.Query().Get("cursor")
-	if strCursor == "" {
-		strCursor = "0"
+// etag : バージョンを強いETagとして返す
+func etag(version string) string {
+	return `"` + version + `"`
+}
+
+// ifMatch : If-MatchヘッダーのETagからバージョンを返す。ヘッダーがない場合と"*"の場合は空文字を返し、
+// 弱いETagや複数のETagなど比較できない形式の場合はfalseを返す
+func ifMatch(r *http.Request) (string, bool) {
+	v := strings.TrimSpace(r.Header.Get("If-Match"))
+	if v == "" || v == "*" {
+		return "", true
+	}
+	if len(v) < 2 || !strings.HasPrefix(v, `"`) || !strings.HasSuffix(v, `"`) || strings.Contains(v, ",") {
+		return "", false
 	}
-	cursor, err := strconv.Atoi(strCursor)
+	return v[1 : len(v)-1], true
+}
 
+func (h *paymentsHandler) GetDetail(w http.ResponseWriter, r *http.Request) {
 	strUserID := chi.URLParam(r, "user_id")
-	userID, _ := strconv.Atoi(strUserID)
+	strPaymentID := chi.URLParam(r, "payment_id")
 
-	res := paymentHandlerResponse{}
+	userID, err := strconv.Atoi(strUserID)
 	if err != nil {
-		res.Payments = make([]*usecase.Payment, 0)
-		if err := json.NewEncoder(w).Encode(res); err != nil {
-			internalServerError(w, "")
-		}
+		badRequestError(w, r, "")
 		return
 	}
-
-	payments, err := h.useCase.GetData(userID, cursor)
+	paymentID, err := strconv.Atoi(strPaymentID)
 	if err != nil {
-		httpError(w, err, "")
+		badRequestError(w, r, "")
 		return
 	}
 
-	res.Payments = payments
+	res, err := h.useCase.GetByID(r.Context(), userID, paymentID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
 
+	w.Header().Set("ETag", etag(res.Version))
 	if err := json.NewEncoder(w).Encode(res); err != nil {
-		internalServerError(w, "")
+		internalServerError(w, r, "")
 	}
 }
 
@@ -70,19 +171,21 @@ func (h *paymentsHandler) CreateData(w http.ResponseWriter, r *http.Request) {
 
 	req := usecase.CreatePaymentParam{}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		badRequestError(w, "")
+		badRequestError(w, r, "")
 		return
 	}
+	req.CreatedBy, _ = UserIDFromContext(r.Context())
 
-	resp, err := h.useCase.Create(&req, userID)
+	resp, err := h.useCase.Create(r.Context(), &req, userID)
 	if err != nil {
-		httpError(w, err, "")
+		httpError(w, r, err, "")
 		return
 	}
 
+	w.Header().Set("ETag", etag(util.EncodeVersion(resp.UpdatedAt)))
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		httpError(w, err, "")
+		httpError(w, r, err, "")
 	}
 }
 
@@ -94,19 +197,28 @@ func (h *paymentsHandler) UpdateData(w http.ResponseWriter, r *http.Request) {
 
 	req := usecase.UpdatePaymentParam{}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		badRequestError(w, "")
+		badRequestError(w, r, "")
+		return
+	}
+
+	version, ok := ifMatch(r)
+	if !ok {
+		badRequestError(w, r, "")
 		return
 	}
+	req.Version = version
+	req.UpdatedBy, _ = UserIDFromContext(r.Context())
 
-	resp, err := h.useCase.Update(&req, userID, payemntID)
+	resp, err := h.useCase.Update(r.Context(), &req, userID, payemntID)
 	if err != nil {
-		httpError(w, err, "")
+		httpError(w, r, err, "")
 		return
 	}
 
+	w.Header().Set("ETag", etag(util.EncodeVersion(resp.UpdatedAt)))
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		httpError(w, err, "")
+		httpError(w, r, err, "")
 	}
 }
 
@@ -116,38 +228,167 @@ func (h *paymentsHandler) DeleteData(w http.ResponseWriter, r *http.Request) {
 
 	userID, err := strconv.Atoi(strUserID)
 	if err != nil {
-		badRequestError(w, "")
+		badRequestError(w, r, "")
 		return
 	}
 	payemntID, err := strconv.Atoi(strPayemntID)
 	if err != nil {
-		badRequestError(w, "")
+		badRequestError(w, r, "")
+		return
+	}
+
+	version, ok := ifMatch(r)
+	if !ok {
+		badRequestError(w, r, "")
 		return
 	}
 
-	if err := h.useCase.DeleteByID(userID, payemntID); err != nil {
-		httpError(w, err, "")
+	if err := h.useCase.DeleteByID(r.Context(), userID, payemntID, version); err != nil {
+		httpError(w, r, err, "")
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *paymentsHandler) FetchDate(w http.ResponseWriter, r *http.Request) {
+func (h *paymentsHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, h.useCase.Approve)
+}
+
+func (h *paymentsHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, h.useCase.Reject)
+}
+
+// review : 承認と却下で共通の処理。更新後の支払いをETagとともに返す
+func (h *paymentsHandler) review(w http.ResponseWriter, r *http.Request, fn func(ctx context.Context, userID, reviewerID, paymentID int) (*model.Payment, error)) {
+	strUserID := chi.URLParam(r, "user_id")
+	strPaymentID := chi.URLParam(r, "payment_id")
+
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+	paymentID, err := strconv.Atoi(strPaymentID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	reviewerID, _ := UserIDFromContext(r.Context())
+
+	resp, err := fn(r.Context(), userID, reviewerID, paymentID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	w.Header().Set("ETag", etag(util.EncodeVersion(resp.UpdatedAt)))
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		httpError(w, r, err, "")
+	}
+}
+
+// utf8BOM : Excelで文字化けせずに開けるようCSVの先頭に付けるBOM
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Export : 絞り込み条件は一覧と同じ。bom=trueの場合はBOM付きで返す。
+// 書き込みを始めた後にエラーが発生した場合はステータスを変更できないため、ログに出力して途中で終了する
+func (h *paymentsHandler) Export(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	query := r.URL.Query()
+	if format := query.Get("format"); format != "" && format != "csv" {
+		httpError(w, r, usecase.InvalidParamError{Fields: []usecase.FieldError{{Field: "format", Rule: "oneof", Param: "csv"}}}, "")
+		return
+	}
+	bom := false
+	if s := query.Get("bom"); s != "" {
+		if bom, err = strconv.ParseBool(s); err != nil {
+			badRequestError(w, r, "")
+			return
+		}
+	}
+	param, err := getPaymentsParam(query)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	cw := csv.NewWriter(w)
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="payments.csv"`)
+		if bom {
+			if _, err := w.Write(utf8BOM); err != nil {
+				return err
+			}
+		}
+		return cw.Write(responseCatalog(r).paymentsCSVHeader)
+	}
+
+	err = h.useCase.Export(r.Context(), userID, param, func(p *usecase.ExportPayment) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return cw.Write([]string{
+			p.PaymentDate,
+			csvText(p.CategoryName),
+			csvText(p.PayerName),
+			strconv.Itoa(p.Payment),
+			csvText(p.Description),
+		})
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err != nil {
+		if !started {
+			httpError(w, r, err, "")
+			return
+		}
+		log.Logger.Error("failed to export payments", zap.Error(err))
+		return
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		log.Logger.Error("failed to export payments", zap.Error(err))
+	}
+}
+
+// csvText : 表計算ソフトで数式として解釈されないよう、数式の開始文字で始まる文字列の先頭に'を付ける
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func (h *paymentsHandler) FetchMonthlyCost(w http.ResponseWriter, r *http.Request) {
 
 	strUserID := chi.URLParam(r, "user_id")
 	userID, err := strconv.Atoi(strUserID)
 	if err != nil {
-		badRequestError(w, "")
+		badRequestError(w, r, "")
 		return
 	}
 
-	res, err := h.useCase.FetchDate(userID)
+	res, err := h.useCase.FetchMonthlyCost(r.Context(), userID)
 	if err != nil {
-		httpError(w, err, "")
+		httpError(w, r, err, "")
 		return
 	}
 
 	if err := json.NewEncoder(w).Encode(res); err != nil {
-		internalServerError(w, "")
+		internalServerError(w, r, "")
 	}
 }
@@ -22,54 +22,106 @@ func Test_paymentsHandler_GetData(t *testing.T) {
 	tests := []struct {
 		name         string
 		strUserID    string
-		strCursor    string
-		payments     []*usecase.Payment
+		query        string
 		userID       int
-		cursor       int
+		param        *usecase.GetPaymentsParam
+		page         *usecase.PaymentPage
 		useCaseError error
 		wantCode     int
+		wantLink     string
 		wantBody     string
 	}{
 		{
 			name:      "Success",
 			strUserID: "1",
-			strCursor: "1",
-			payments: []*usecase.Payment{
-				{
-					ID:           1,
-					CategoryName: "カテゴリー名",
-					PayerName:    "パートナー",
-					PaymentDate:  "2020-04-01",
-					Payment:      1234,
-					CreatedAt:    "2020-04-01 09:00:00",
+			query:     "",
+			userID:    1,
+			param:     &usecase.GetPaymentsParam{},
+			page: &usecase.PaymentPage{
+				Payments: []*usecase.Payment{
+					{
+						ID:           1,
+						CategoryName: "カテゴリー名",
+						PayerName:    "パートナー",
+						PaymentDate:  "2020-04-01",
+						Payment:      1234,
+						Status:       "approved",
+						CreatedAt:    "2020-04-01 09:00:00",
+					},
 				},
 			},
-			userID:       1,
-			cursor:       1,
-			useCaseError: nil,
-			wantCode:     200,
-			wantBody:     "{\"payments\":[{\"id\":1,\"category_name\":\"カテゴリー名\",\"payer_name\":\"パートナー\",\"payment_date\":\"2020-04-01\",\"payment\":1234,\"created_at\":\"2020-04-01 09:00:00\"}]}\n",
+			wantCode: http.StatusOK,
+			wantBody: `{"payments":[{"id":1,"category_name":"カテゴリー名","payer_name":"パートナー","payment_date":"2020-04-01","payment":1234,"status":"approved","created_at":"2020-04-01 09:00:00"}],"next_cursor":"","has_more":false}` + "\n",
+		},
+		{
+			name:      "Success has more",
+			strUserID: "1",
+			query:     "?cursor=abc&limit=1",
+			userID:    1,
+			param:     &usecase.GetPaymentsParam{Cursor: "abc", Limit: 1},
+			page: &usecase.PaymentPage{
+				Payments:   []*usecase.Payment{},
+				NextCursor: "def",
+				HasMore:    true,
+			},
+			wantCode: http.StatusOK,
+			wantLink: `</1/payments?cursor=def&limit=1>; rel="next"`,
+			wantBody: `{"payments":[],"next_cursor":"def","has_more":true}` + "\n",
+		},
+		{
+			name:      "Success with filters",
+			strUserID: "1",
+			query:     "?month=2020-03&category_id=2&payer_id=2&min_payment=100&max_payment=5000&description=%E3%82%B9%E3%83%BC%E3%83%91%E3%83%BC&status=pending",
+			userID:    1,
+			param: &usecase.GetPaymentsParam{
+				Month:       "2020-03",
+				CategoryID:  2,
+				PayerID:     2,
+				MinPayment:  100,
+				MaxPayment:  5000,
+				Description: "スーパー",
+				Status:      "pending",
+			},
+			page: &usecase.PaymentPage{
+				Payments: []*usecase.Payment{},
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"payments":[],"next_cursor":"","has_more":false}` + "\n",
 		},
 		{
-			name:      "ConversionError",
+			name:      "Bad request error category_id is String",
 			strUserID: "1",
-			strCursor: "string",
-			payments:  []*usecase.Payment{},
-			userID:    999,
-			cursor:    999,
-			wantCode:  200,
-			wantBody:  "{\"payments\":[]}\n",
+			query:     "?category_id=string",
+			wantCode:  http.StatusBadRequest,
+			wantBody:  `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:      "Bad request error limit is String",
+			strUserID: "1",
+			query:     "?limit=string",
+			wantCode:  http.StatusBadRequest,
+			wantBody:  `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:         "Invalid param error cursor",
+			strUserID:    "1",
+			query:        "?cursor=invalid",
+			userID:       1,
+			param:        &usecase.GetPaymentsParam{Cursor: "invalid"},
+			page:         &usecase.PaymentPage{},
+			useCaseError: usecase.InvalidParamError{},
+			wantCode:     http.StatusBadRequest,
+			wantBody:     `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
 		},
 		{
 			name:         "InternalServerError",
 			strUserID:    "999",
-			strCursor:    "999",
-			payments:     []*usecase.Payment{},
 			userID:       999,
-			cursor:       999,
+			param:        &usecase.GetPaymentsParam{},
+			page:         &usecase.PaymentPage{},
 			useCaseError: usecase.InternalServerError{},
-			wantCode:     500,
-			wantBody:     `{"msg":"システム内部エラーが発生しました。"}` + "\n",
+			wantCode:     http.StatusInternalServerError,
+			wantBody:     `{"code":"internal_server_error","msg":"システム内部エラーが発生しました。"}` + "\n",
 		},
 	}
 
@@ -79,9 +131,9 @@ func Test_paymentsHandler_GetData(t *testing.T) {
 			t.Parallel()
 
 			mock := &mockPaymentUseCase{}
-			mock.On("GetData", tt.userID, tt.cursor).Return(tt.payments, tt.useCaseError)
+			mock.On("GetData", tt.userID, tt.param).Return(tt.page, tt.useCaseError)
 
-			r := httptest.NewRequest(http.MethodGet, "/"+tt.strUserID+"/payments?cursor="+tt.strCursor, nil)
+			r := httptest.NewRequest(http.MethodGet, "/"+tt.strUserID+"/payments"+tt.query, nil)
 			rr := httptest.NewRecorder()
 			h := rest.NewPaymentsHandler(mock)
 
@@ -94,6 +146,9 @@ func Test_paymentsHandler_GetData(t *testing.T) {
 			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
 				t.Errorf("GetData() mismatch status code (-want +got):\n%s", diff)
 			}
+			if diff := cmp.Diff(tt.wantLink, rr.Header().Get("Link")); diff != "" {
+				t.Errorf("GetData() mismatch Link header (-want +got):\n%s", diff)
+			}
 			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
 				t.Errorf("GetData() mismatch body (-want +got):\n%s", diff)
 			}
@@ -125,6 +180,7 @@ func Test_paymentsHandler_CreateData(t *testing.T) {
 				Description: sql.NullString{String: "", Valid: false},
 				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
 				Payment:     1234,
+				Status:      "approved",
 				CreatedAt:   time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
 				UpdatedAt:   time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
 			},
@@ -138,7 +194,7 @@ func Test_paymentsHandler_CreateData(t *testing.T) {
 			body:         `{"category_id":1,"payer_id":1,"payment_date":"2020-04-01T00:00:00+09:00","payment":1234}`,
 			useCaseError: nil,
 			wantCode:     http.StatusCreated,
-			wantBody:     "{\"id\":1,\"user_id\":1,\"category_id\":1,\"payer_id\":1,\"description\":{\"String\":\"\",\"Valid\":false},\"payment_date\":\"2020-04-01T00:00:00Z\",\"payment\":1234,\"created_at\":\"2020-04-01T00:00:00Z\",\"updated_at\":\"2020-04-01T00:00:00Z\"}\n",
+			wantBody:     "{\"id\":1,\"user_id\":1,\"category_id\":1,\"payer_id\":1,\"description\":{\"String\":\"\",\"Valid\":false},\"payment_date\":\"2020-04-01T00:00:00Z\",\"payment\":1234,\"status\":\"approved\",\"created_by\":{\"Int64\":0,\"Valid\":false},\"created_at\":\"2020-04-01T00:00:00Z\",\"updated_at\":\"2020-04-01T00:00:00Z\"}\n",
 		},
 		{
 			name:   "Internal server error",
@@ -155,7 +211,44 @@ func Test_paymentsHandler_CreateData(t *testing.T) {
 			body:         `{"category_id":1,"payer_id":1,"payment_date":"2020-04-01T00:00:00+09:00","payment":1234}`,
 			useCaseError: errors.New("usecase error"),
 			wantCode:     http.StatusInternalServerError,
-			wantBody:     `{"msg":"システム内部エラーが発生しました。"}` + "\n",
+			wantBody:     `{"code":"internal_server_error","msg":"システム内部エラーが発生しました。"}` + "\n",
+		},
+		{
+			name:   "Unprocessable entity error unknown category",
+			id:     1,
+			userID: "1",
+			want:   &model.Payment{},
+			req: &usecase.CreatePaymentParam{
+				CategoryID:  999,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.Now().Location()),
+				Payment:     1234,
+			},
+			body:         `{"category_id":999,"payer_id":1,"payment_date":"2020-04-01T00:00:00+09:00","payment":1234}`,
+			useCaseError: usecase.UnprocessableEntityError{Field: "category_id"},
+			wantCode:     http.StatusUnprocessableEntity,
+			wantBody:     `{"code":"unprocessable_entity","msg":"入力内容に誤りがあります。","fields":[{"field":"category_id","rule":"exists","msg":"存在しない値が指定されています。"}]}` + "\n",
+		},
+		{
+			name:   "Invalid param error",
+			id:     1,
+			userID: "1",
+			want:   &model.Payment{},
+			req: &usecase.CreatePaymentParam{
+				CategoryID:  1,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.Now().Location()),
+				Payment:     -1,
+			},
+			body: `{"category_id":1,"payer_id":1,"payment_date":"2020-04-01T00:00:00+09:00","payment":-1}`,
+			useCaseError: usecase.InvalidParamError{Fields: []usecase.FieldError{
+				{Field: "payment", Rule: "gt", Param: "0"},
+				{Field: "payment_date", Rule: "maxfuture", Param: "365"},
+			}},
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"code":"invalid_param","msg":"入力内容に誤りがあります。","fields":[{"field":"payment","rule":"gt","msg":"0より大きい値を入力してください。"},{"field":"payment_date","rule":"maxfuture","msg":"365日より先の日付は入力できません。"}]}` + "\n",
 		},
 	}
 	for _, tt := range tests {
@@ -196,8 +289,10 @@ func Test_paymentsHandler_UpdateData(t *testing.T) {
 		want         *model.Payment
 		req          *usecase.UpdatePaymentParam
 		body         string
+		ifMatch      string
 		useCaseError error
 		wantCode     int
+		wantETag     string
 		wantBody     string
 	}{
 		{
@@ -214,6 +309,7 @@ func Test_paymentsHandler_UpdateData(t *testing.T) {
 				Description: sql.NullString{String: "", Valid: false},
 				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
 				Payment:     1234,
+				Status:      "approved",
 				CreatedAt:   time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
 				UpdatedAt:   time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
 			},
@@ -227,7 +323,8 @@ func Test_paymentsHandler_UpdateData(t *testing.T) {
 			body:         `{"category_id":1,"payer_id":1,"payment_date":"2020-04-01T00:00:00+09:00","payment":1234}`,
 			useCaseError: nil,
 			wantCode:     http.StatusOK,
-			wantBody:     "{\"id\":1,\"user_id\":1,\"category_id\":1,\"payer_id\":1,\"description\":{\"String\":\"\",\"Valid\":false},\"payment_date\":\"2020-04-01T00:00:00Z\",\"payment\":1234,\"created_at\":\"2020-04-01T00:00:00Z\",\"updated_at\":\"2020-04-01T00:00:00Z\"}\n",
+			wantETag:     `"1585699200000000"`,
+			wantBody:     "{\"id\":1,\"user_id\":1,\"category_id\":1,\"payer_id\":1,\"description\":{\"String\":\"\",\"Valid\":false},\"payment_date\":\"2020-04-01T00:00:00Z\",\"payment\":1234,\"status\":\"approved\",\"created_by\":{\"Int64\":0,\"Valid\":false},\"created_at\":\"2020-04-01T00:00:00Z\",\"updated_at\":\"2020-04-01T00:00:00Z\"}\n",
 		},
 		{
 			name:      "Internal server error",
@@ -246,7 +343,69 @@ func Test_paymentsHandler_UpdateData(t *testing.T) {
 			body:         `{"category_id":1,"payer_id":1,"payment_date":"2020-04-01T00:00:00+09:00","payment":1234}`,
 			useCaseError: errors.New("usecase error"),
 			wantCode:     http.StatusInternalServerError,
-			wantBody:     `{"msg":"システム内部エラーが発生しました。"}` + "\n",
+			wantBody:     `{"code":"internal_server_error","msg":"システム内部エラーが発生しました。"}` + "\n",
+		},
+		{
+			name:      "Success with If-Match",
+			id:        1,
+			pID:       1,
+			paymentID: "1",
+			userID:    "1",
+			want: &model.Payment{
+				ID:          1,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+				Status:      "approved",
+				CreatedAt:   time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt:   time.Date(2020, time.April, 2, 0, 0, 0, 0, time.UTC),
+			},
+			req: &usecase.UpdatePaymentParam{
+				CategoryID:  1,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.Now().Location()),
+				Payment:     1234,
+				Version:     "1585699200000000",
+			},
+			body:     `{"category_id":1,"payer_id":1,"payment_date":"2020-04-01T00:00:00+09:00","payment":1234}`,
+			ifMatch:  `"1585699200000000"`,
+			wantCode: http.StatusOK,
+			wantETag: `"1585785600000000"`,
+			wantBody: "{\"id\":1,\"user_id\":1,\"category_id\":1,\"payer_id\":1,\"description\":{\"String\":\"\",\"Valid\":false},\"payment_date\":\"2020-04-01T00:00:00Z\",\"payment\":1234,\"status\":\"approved\",\"created_by\":{\"Int64\":0,\"Valid\":false},\"created_at\":\"2020-04-01T00:00:00Z\",\"updated_at\":\"2020-04-02T00:00:00Z\"}\n",
+		},
+		{
+			name:      "Conflict error updated by partner",
+			id:        1,
+			pID:       1,
+			paymentID: "1",
+			userID:    "1",
+			want:      &model.Payment{},
+			req: &usecase.UpdatePaymentParam{
+				CategoryID:  1,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.Now().Location()),
+				Payment:     1234,
+				Version:     "1585699200000000",
+			},
+			body:         `{"category_id":1,"payer_id":1,"payment_date":"2020-04-01T00:00:00+09:00","payment":1234}`,
+			ifMatch:      `"1585699200000000"`,
+			useCaseError: usecase.ConflictError{},
+			wantCode:     http.StatusConflict,
+			wantBody:     `{"code":"conflict","msg":"競合が発生しました。"}` + "\n",
+		},
+		{
+			name:      "Bad request error weak ETag",
+			paymentID: "1",
+			userID:    "1",
+			body:      `{"category_id":1,"payer_id":1,"payment_date":"2020-04-01T00:00:00+09:00","payment":1234}`,
+			ifMatch:   `W/"1585699200000000"`,
+			wantCode:  http.StatusBadRequest,
+			wantBody:  `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
 		},
 	}
 	for _, tt := range tests {
@@ -258,6 +417,9 @@ func Test_paymentsHandler_UpdateData(t *testing.T) {
 			mock.On("Update", tt.req, tt.id, tt.pID).Return(tt.want, tt.useCaseError)
 
 			r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tt.body))
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
 			rr := httptest.NewRecorder()
 			h := rest.NewPaymentsHandler(mock)
 
@@ -271,6 +433,9 @@ func Test_paymentsHandler_UpdateData(t *testing.T) {
 			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
 				t.Errorf("CreateData() mismatch status code (-want +got):\n%s", diff)
 			}
+			if diff := cmp.Diff(tt.wantETag, rr.Header().Get("ETag")); diff != "" {
+				t.Errorf("UpdateData() mismatch ETag (-want +got):\n%s", diff)
+			}
 			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
 				t.Errorf("CreateData() mismatch body (-want +got):\n%s", diff)
 			}
@@ -285,6 +450,8 @@ func Test_paymentsHandler_DeleteData(t *testing.T) {
 		userID       int
 		strPaymentID string
 		paymentID    int
+		ifMatch      string
+		version      string
 		useCaseError error
 		wantCode     int
 		wantBody     string
@@ -299,6 +466,38 @@ func Test_paymentsHandler_DeleteData(t *testing.T) {
 			wantCode:     http.StatusNoContent,
 			wantBody:     "",
 		},
+		{
+			name:         "Success with If-Match",
+			strUserID:    "1",
+			userID:       1,
+			strPaymentID: "1",
+			paymentID:    1,
+			ifMatch:      `"1585699200000000"`,
+			version:      "1585699200000000",
+			wantCode:     http.StatusNoContent,
+		},
+		{
+			name:         "Conflict error updated by partner",
+			strUserID:    "1",
+			userID:       1,
+			strPaymentID: "1",
+			paymentID:    1,
+			ifMatch:      `"1585699200000000"`,
+			version:      "1585699200000000",
+			useCaseError: usecase.ConflictError{},
+			wantCode:     http.StatusConflict,
+			wantBody:     `{"code":"conflict","msg":"競合が発生しました。"}` + "\n",
+		},
+		{
+			name:         "Bad request error weak ETag",
+			strUserID:    "1",
+			userID:       1,
+			strPaymentID: "1",
+			paymentID:    1,
+			ifMatch:      `W/"1585699200000000"`,
+			wantCode:     http.StatusBadRequest,
+			wantBody:     `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
 		{
 			name:         "Bad request error paymentID is String",
 			strUserID:    "1",
@@ -307,7 +506,7 @@ func Test_paymentsHandler_DeleteData(t *testing.T) {
 			paymentID:    1,
 			useCaseError: errors.New("usecase error"),
 			wantCode:     http.StatusBadRequest,
-			wantBody:     `{"msg":"要求の形式が正しくありません。"}` + "\n",
+			wantBody:     `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
 		},
 		{
 			name:         "Bad request error userID is String",
@@ -317,7 +516,7 @@ func Test_paymentsHandler_DeleteData(t *testing.T) {
 			paymentID:    1,
 			useCaseError: errors.New("usecase error"),
 			wantCode:     http.StatusBadRequest,
-			wantBody:     `{"msg":"要求の形式が正しくありません。"}` + "\n",
+			wantBody:     `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
 		},
 		{
 			name:         "Internal server error",
@@ -327,7 +526,7 @@ func Test_paymentsHandler_DeleteData(t *testing.T) {
 			paymentID:    1,
 			useCaseError: errors.New("usecase error"),
 			wantCode:     http.StatusInternalServerError,
-			wantBody:     `{"msg":"システム内部エラーが発生しました。"}` + "\n",
+			wantBody:     `{"code":"internal_server_error","msg":"システム内部エラーが発生しました。"}` + "\n",
 		},
 	}
 	for _, tt := range tests {
@@ -336,9 +535,12 @@ func Test_paymentsHandler_DeleteData(t *testing.T) {
 			t.Parallel()
 
 			mock := &mockPaymentUseCase{}
-			mock.On("DeleteByID", tt.userID, tt.paymentID).Return(tt.useCaseError)
+			mock.On("DeleteByID", tt.userID, tt.paymentID, tt.version).Return(tt.useCaseError)
 
 			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
 			rr := httptest.NewRecorder()
 			h := rest.NewPaymentsHandler(mock)
 
@@ -359,27 +561,440 @@ func Test_paymentsHandler_DeleteData(t *testing.T) {
 	}
 }
 
+func Test_paymentsHandler_Approve(t *testing.T) {
+	tests := []struct {
+		name         string
+		strUserID    string
+		userID       int
+		strPaymentID string
+		paymentID    int
+		payment      *model.Payment
+		useCaseError error
+		wantCode     int
+		wantETag     string
+		wantBody     string
+	}{
+		{
+			name:         "Success",
+			strUserID:    "1",
+			userID:       1,
+			strPaymentID: "1",
+			paymentID:    1,
+			payment: &model.Payment{
+				ID:          1,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     2,
+				Description: sql.NullString{String: "冷蔵庫", Valid: true},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     150000,
+				Status:      "approved",
+				CreatedAt:   time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt:   time.Date(2020, time.April, 2, 0, 0, 0, 0, time.UTC),
+			},
+			wantCode: http.StatusOK,
+			wantETag: `"1585785600000000"`,
+			wantBody: `{"id":1,"user_id":1,"category_id":1,"payer_id":2,"description":{"String":"冷蔵庫","Valid":true},"payment_date":"2020-04-01T00:00:00Z","payment":150000,"status":"approved","created_by":{"Int64":0,"Valid":false},"created_at":"2020-04-01T00:00:00Z","updated_at":"2020-04-02T00:00:00Z"}` + "\n",
+		},
+		{
+			name:         "Bad request error paymentID is String",
+			strUserID:    "1",
+			userID:       1,
+			strPaymentID: "string",
+			paymentID:    1,
+			payment:      &model.Payment{},
+			wantCode:     http.StatusBadRequest,
+			wantBody:     `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:         "Not found error",
+			strUserID:    "1",
+			userID:       1,
+			strPaymentID: "999",
+			paymentID:    999,
+			payment:      &model.Payment{},
+			useCaseError: usecase.NotFoundError{},
+			wantCode:     http.StatusNotFound,
+			wantBody:     `{"code":"not_found","msg":"ページが見つかりません。"}` + "\n",
+		},
+		{
+			name:         "Conflict error already reviewed",
+			strUserID:    "1",
+			userID:       1,
+			strPaymentID: "1",
+			paymentID:    1,
+			payment:      &model.Payment{},
+			useCaseError: usecase.ConflictError{},
+			wantCode:     http.StatusConflict,
+			wantBody:     `{"code":"conflict","msg":"競合が発生しました。"}` + "\n",
+		},
+		{
+			name:         "Forbidden error own payment",
+			strUserID:    "1",
+			userID:       1,
+			strPaymentID: "1",
+			paymentID:    1,
+			payment:      &model.Payment{},
+			useCaseError: usecase.ForbiddenError{},
+			wantCode:     http.StatusForbidden,
+			wantBody:     `{"code":"forbidden","msg":"アクセス権限がありません。"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockPaymentUseCase{}
+			mock.On("Approve", tt.userID, 0, tt.paymentID).Return(tt.payment, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewPaymentsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.strUserID)
+			rctx.URLParams.Add("payment_id", tt.strPaymentID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.Approve(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("Approve() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantETag, rr.Header().Get("ETag")); diff != "" {
+				t.Errorf("Approve() mismatch ETag (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("Approve() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_paymentsHandler_Reject(t *testing.T) {
+	// 認証したユーザー(パートナー)を却下したユーザーとして渡す
+	auth := &mockAuthUseCase{}
+	auth.On("Authenticate", "token").Return(&model.User{ID: 2}, nil)
+	auth.On("AuthorizeLedger", 2, 1).Return(nil)
+
+	mock := &mockPaymentUseCase{}
+	mock.On("Reject", 1, 2, 1).Return(&model.Payment{ID: 1, UserID: 1, Status: "rejected"}, nil)
+
+	m := rest.NewAuthMiddleware(auth)
+	h := rest.NewPaymentsHandler(mock)
+	router := chi.NewRouter()
+	router.With(m.AuthorizeLedger).Post("/users/{user_id}/payments/{payment_id}/reject", h.Reject)
+
+	r := httptest.NewRequest(http.MethodPost, "/users/1/payments/1/reject", nil)
+	r.Header.Set("Authorization", "Bearer token")
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, r)
+
+	if diff := cmp.Diff(http.StatusOK, rr.Code); diff != "" {
+		t.Errorf("Reject() mismatch status code (-want +got):\n%s", diff)
+	}
+	if !strings.Contains(rr.Body.String(), `"status":"rejected"`) {
+		t.Errorf("Reject() body should contain rejected status, but got %s", rr.Body.String())
+	}
+}
+
+func Test_paymentsHandler_FetchMonthlyCost(t *testing.T) {
+	tests := []struct {
+		name         string
+		strUserID    string
+		userID       int
+		monthlyCosts *usecase.MonthlyCosts
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:      "Success",
+			strUserID: "1",
+			userID:    1,
+			monthlyCosts: &usecase.MonthlyCosts{
+				MonthlyCosts: []*usecase.MonthlyCost{
+					{
+						YearMonth:  "2020-04",
+						Total:      1234,
+						Count:      1,
+						Payers:     []*usecase.PayerCost{{PayerID: 1, Total: 1234, Count: 1}},
+						Categories: []*usecase.CategoryCost{{CategoryID: 1, CategoryName: "家賃", Total: 1234, Count: 1}},
+					},
+				},
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"monthly_costs":[{"year_month":"2020-04","total":1234,"count":1,"payers":[{"payer_id":1,"total":1234,"count":1}],"categories":[{"category_id":1,"category_name":"家賃","total":1234,"count":1}]}]}` + "\n",
+		},
+		{
+			name:      "Bad request error userID is String",
+			strUserID: "string",
+			wantCode:  http.StatusBadRequest,
+			wantBody:  `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:         "Internal server error",
+			strUserID:    "1",
+			userID:       1,
+			useCaseError: usecase.InternalServerError{},
+			wantCode:     http.StatusInternalServerError,
+			wantBody:     `{"code":"internal_server_error","msg":"システム内部エラーが発生しました。"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockPaymentUseCase{}
+			mock.On("FetchMonthlyCost", tt.userID).Return(tt.monthlyCosts, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewPaymentsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.strUserID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.FetchMonthlyCost(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("FetchMonthlyCost() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("FetchMonthlyCost() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_paymentsHandler_GetDetail(t *testing.T) {
+	tests := []struct {
+		name         string
+		strPaymentID string
+		paymentID    int
+		payment      *usecase.PaymentDetail
+		useCaseError error
+		wantCode     int
+		wantETag     string
+		wantBody     string
+	}{
+		{
+			name:         "Success",
+			strPaymentID: "1",
+			paymentID:    1,
+			payment: &usecase.PaymentDetail{
+				ID:           1,
+				CategoryID:   2,
+				CategoryName: "食費",
+				PayerID:      2,
+				PayerName:    "パートナー",
+				Description:  sql.NullString{String: "スーパー", Valid: true},
+				PaymentDate:  "2020-04-01",
+				Payment:      1234,
+				Status:       "approved",
+				CreatedAt:    "2020-04-01 09:00:00",
+				UpdatedAt:    "2020-04-02 09:00:00",
+				Version:      "1585785600000000",
+			},
+			wantCode: http.StatusOK,
+			wantETag: `"1585785600000000"`,
+			wantBody: `{"id":1,"category_id":2,"category_name":"食費","payer_id":2,"payer_name":"パートナー","description":{"String":"スーパー","Valid":true},"payment_date":"2020-04-01","payment":1234,"status":"approved","created_at":"2020-04-01 09:00:00","updated_at":"2020-04-02 09:00:00"}` + "\n",
+		},
+		{
+			name:         "Bad request error paymentID is String",
+			strPaymentID: "string",
+			wantCode:     http.StatusBadRequest,
+			wantBody:     `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:         "Not found error",
+			strPaymentID: "999",
+			paymentID:    999,
+			payment:      &usecase.PaymentDetail{},
+			useCaseError: usecase.NotFoundError{},
+			wantCode:     http.StatusNotFound,
+			wantBody:     `{"code":"not_found","msg":"ページが見つかりません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockPaymentUseCase{}
+			mock.On("GetByID", 1, tt.paymentID).Return(tt.payment, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewPaymentsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", "1")
+			rctx.URLParams.Add("payment_id", tt.strPaymentID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.GetDetail(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("GetDetail() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantETag, rr.Header().Get("ETag")); diff != "" {
+				t.Errorf("GetDetail() mismatch ETag (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("GetDetail() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 type mockPaymentUseCase struct {
 	mock.Mock
 	usecase.PaymentUseCase
 }
 
-func (m *mockPaymentUseCase) GetData(userID, cursor int) ([]*usecase.Payment, error) {
-	ret := m.Called(userID, cursor)
-	return ret.Get(0).([]*usecase.Payment), ret.Error(1)
+func (m *mockPaymentUseCase) GetData(ctx context.Context, userID int, param *usecase.GetPaymentsParam) (*usecase.PaymentPage, error) {
+	ret := m.Called(userID, param)
+	return ret.Get(0).(*usecase.PaymentPage), ret.Error(1)
 }
 
-func (m *mockPaymentUseCase) Create(param *usecase.CreatePaymentParam, userID int) (*model.Payment, error) {
+func (m *mockPaymentUseCase) GetByID(ctx context.Context, userID, paymentID int) (*usecase.PaymentDetail, error) {
+	ret := m.Called(userID, paymentID)
+	return ret.Get(0).(*usecase.PaymentDetail), ret.Error(1)
+}
+
+func (m *mockPaymentUseCase) Create(ctx context.Context, param *usecase.CreatePaymentParam, userID int) (*model.Payment, error) {
 	ret := m.Called(param, userID)
 	return ret.Get(0).(*model.Payment), ret.Error(1)
 }
 
-func (m *mockPaymentUseCase) Update(param *usecase.UpdatePaymentParam, userID, paymentID int) (*model.Payment, error) {
+func (m *mockPaymentUseCase) Update(ctx context.Context, param *usecase.UpdatePaymentParam, userID, paymentID int) (*model.Payment, error) {
 	ret := m.Called(param, userID, paymentID)
 	return ret.Get(0).(*model.Payment), ret.Error(1)
 }
 
-func (m *mockPaymentUseCase) DeleteByID(userID, paymentID int) error {
-	ret := m.Called(userID, paymentID)
+func (m *mockPaymentUseCase) DeleteByID(ctx context.Context, userID, paymentID int, version string) error {
+	ret := m.Called(userID, paymentID, version)
 	return ret.Error(0)
 }
+
+func (m *mockPaymentUseCase) Approve(ctx context.Context, userID, reviewerID, paymentID int) (*model.Payment, error) {
+	ret := m.Called(userID, reviewerID, paymentID)
+	return ret.Get(0).(*model.Payment), ret.Error(1)
+}
+
+func (m *mockPaymentUseCase) Reject(ctx context.Context, userID, reviewerID, paymentID int) (*model.Payment, error) {
+	ret := m.Called(userID, reviewerID, paymentID)
+	return ret.Get(0).(*model.Payment), ret.Error(1)
+}
+
+func (m *mockPaymentUseCase) FetchMonthlyCost(ctx context.Context, userID int) (*usecase.MonthlyCosts, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).(*usecase.MonthlyCosts), ret.Error(1)
+}
+
+func (m *mockPaymentUseCase) Export(ctx context.Context, userID int, param *usecase.GetPaymentsParam, fn func(*usecase.ExportPayment) error) error {
+	ret := m.Called(userID, param)
+	for _, p := range ret.Get(0).([]*usecase.ExportPayment) {
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return ret.Error(1)
+}
+
+func Test_paymentsHandler_Export(t *testing.T) {
+	tests := []struct {
+		name            string
+		query           string
+		param           *usecase.GetPaymentsParam
+		payments        []*usecase.ExportPayment
+		useCaseError    error
+		wantCode        int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:  "Success",
+			query: "?format=csv&from=2020-04-01&to=2020-04-30",
+			param: &usecase.GetPaymentsParam{From: "2020-04-01", To: "2020-04-30"},
+			payments: []*usecase.ExportPayment{
+				{PaymentDate: "2020-04-02", CategoryName: "食費", PayerName: "パートナー", Payment: 1234, Description: `スーパー, "特売"`},
+				{PaymentDate: "2020-04-01", CategoryName: "日用品", PayerName: "ユーザー", Payment: 500, Description: "=SUM(A1:A2)"},
+			},
+			wantCode:        http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantBody: "支払日,カテゴリー,支払者,金額,内容\n" +
+				"2020-04-02,食費,パートナー,1234,\"スーパー, \"\"特売\"\"\"\n" +
+				"2020-04-01,日用品,ユーザー,500,'=SUM(A1:A2)\n",
+		},
+		{
+			name:            "Success with BOM and no payments",
+			query:           "?month=2020-04&bom=true",
+			param:           &usecase.GetPaymentsParam{Month: "2020-04"},
+			payments:        []*usecase.ExportPayment{},
+			wantCode:        http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantBody:        "\xEF\xBB\xBF支払日,カテゴリー,支払者,金額,内容\n",
+		},
+		{
+			name:            "Invalid param error format",
+			query:           "?format=xlsx",
+			wantCode:        http.StatusBadRequest,
+			wantContentType: "",
+			wantBody:        `{"code":"invalid_param","msg":"入力内容に誤りがあります。","fields":[{"field":"format","rule":"oneof","msg":"csvのいずれかを指定してください。"}]}` + "\n",
+		},
+		{
+			name:            "Bad request error bom is not bool",
+			query:           "?bom=yes",
+			wantCode:        http.StatusBadRequest,
+			wantContentType: "",
+			wantBody:        `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:            "Internal server error",
+			query:           "",
+			param:           &usecase.GetPaymentsParam{},
+			payments:        []*usecase.ExportPayment{},
+			useCaseError:    usecase.InternalServerError{},
+			wantCode:        http.StatusInternalServerError,
+			wantContentType: "",
+			wantBody:        `{"code":"internal_server_error","msg":"システム内部エラーが発生しました。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockPaymentUseCase{}
+			mock.On("Export", 1, tt.param).Return(tt.payments, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodGet, "/1/payments/export"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewPaymentsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", "1")
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.Export(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("Export() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantContentType, rr.Header().Get("Content-Type")); diff != "" {
+				t.Errorf("Export() mismatch Content-Type (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("Export() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/warikan/api/usecase"
+)
+
+type SettlementsHandler interface {
+	GetData(http.ResponseWriter, *http.Request)
+}
+
+type settlementsHandler struct {
+	useCase usecase.SettlementUseCase
+}
+
+func NewSettlementsHandler(u usecase.SettlementUseCase) SettlementsHandler {
+	return &settlementsHandler{
+		useCase: u,
+	}
+}
+
+func (h *settlementsHandler) GetData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	yearMonth := chi.URLParam(r, "year_month")
+
+	res, err := h.useCase.GetData(userID, yearMonth)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		internalServerError(w, r, "")
+	}
+}
@@ -0,0 +1,113 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/google/go-cmp/cmp"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/warikan/api/handler/rest"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_settlementsHandler_GetData(t *testing.T) {
+	tests := []struct {
+		name         string
+		strUserID    string
+		userID       int
+		yearMonth    string
+		settlement   *usecase.Settlement
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:      "Success",
+			strUserID: "1",
+			userID:    1,
+			yearMonth: "2020-04",
+			settlement: &usecase.Settlement{
+				YearMonth:       "2020-04",
+				Proportion:      50,
+				Total:           2000,
+				UserPayment:     2000,
+				PartnerPayment:  0,
+				UserBurden:      1000,
+				PartnerBurden:   1000,
+				DebtorPayerID:   2,
+				CreditorPayerID: 1,
+				Amount:          1000,
+				Categories: []*usecase.CategorySettlement{
+					{CategoryID: 1, CategoryName: "家賃", Total: 2000, UserPayment: 2000, PartnerPayment: 0, UserBurden: 1000, PartnerBurden: 1000},
+				},
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"year_month":"2020-04","proportion":50,"total":2000,"user_payment":2000,"partner_payment":0,"user_burden":1000,"partner_burden":1000,"debtor_payer_id":2,"creditor_payer_id":1,"amount":1000,"categories":[{"category_id":1,"category_name":"家賃","total":2000,"user_payment":2000,"partner_payment":0,"user_burden":1000,"partner_burden":1000}]}` + "\n",
+		},
+		{
+			name:      "Bad request error userID is String",
+			strUserID: "string",
+			yearMonth: "2020-04",
+			wantCode:  http.StatusBadRequest,
+			wantBody:  `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:         "Bad request error invalid year month",
+			strUserID:    "1",
+			userID:       1,
+			yearMonth:    "202004",
+			useCaseError: usecase.InvalidParamError{},
+			wantCode:     http.StatusBadRequest,
+			wantBody:     `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+		{
+			name:         "Not found error",
+			strUserID:    "999",
+			userID:       999,
+			yearMonth:    "2020-04",
+			useCaseError: usecase.NotFoundError{},
+			wantCode:     http.StatusNotFound,
+			wantBody:     `{"code":"not_found","msg":"ページが見つかりません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockSettlementUseCase{}
+			mock.On("GetData", tt.userID, tt.yearMonth).Return(tt.settlement, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewSettlementsHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.strUserID)
+			rctx.URLParams.Add("year_month", tt.yearMonth)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.GetData(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("GetData() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("GetData() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type mockSettlementUseCase struct {
+	mock.Mock
+}
+
+func (m *mockSettlementUseCase) GetData(userID int, yearMonth string) (*usecase.Settlement, error) {
+	ret := m.Called(userID, yearMonth)
+	return ret.Get(0).(*usecase.Settlement), ret.Error(1)
+}
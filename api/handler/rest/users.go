@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/warikan/api/usecase"
+)
+
+type UsersHandler interface {
+	GetData(http.ResponseWriter, *http.Request)
+	UpdateData(http.ResponseWriter, *http.Request)
+	DeleteData(http.ResponseWriter, *http.Request)
+}
+
+type usersHandler struct {
+	useCase usecase.UserUseCase
+}
+
+func NewUsersHandler(u usecase.UserUseCase) UsersHandler {
+	return &usersHandler{
+		useCase: u,
+	}
+}
+
+func (h *usersHandler) GetData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	resp, err := h.useCase.GetData(userID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		internalServerError(w, r, "")
+	}
+}
+
+func (h *usersHandler) UpdateData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	req := usecase.UpdateUserParam{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	resp, err := h.useCase.Update(&req, userID)
+	if err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		httpError(w, r, err, "")
+	}
+}
+
+func (h *usersHandler) DeleteData(w http.ResponseWriter, r *http.Request) {
+	strUserID := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(strUserID)
+	if err != nil {
+		badRequestError(w, r, "")
+		return
+	}
+
+	if err := h.useCase.DeleteByID(r.Context(), userID); err != nil {
+		httpError(w, r, err, "")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
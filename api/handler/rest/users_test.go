@@ -0,0 +1,231 @@
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/google/go-cmp/cmp"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/handler/rest"
+	"github.com/warikan/api/usecase"
+)
+
+var testUser = &model.User{
+	ID:           1,
+	UserName:     "ユーザー",
+	PartnerName:  "パートナー",
+	Email:        "test@example.com",
+	Password:     "hash",
+	UserImage:    "user_image",
+	PartnerImage: "partner_image",
+	Proportion:   50,
+	CreatedAt:    time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+	UpdatedAt:    time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+}
+
+const testUserBody = `{"id":1,"user_name":"ユーザー","partner_name":"パートナー","email":"test@example.com","user_image":"user_image","partner_image":"partner_image","proportion":50,"language":"","created_at":"2020-04-01T00:00:00Z","updated_at":"2020-04-01T00:00:00Z"}` + "\n"
+
+func Test_usersHandler_GetData(t *testing.T) {
+	tests := []struct {
+		name         string
+		strUserID    string
+		userID       int
+		user         *model.User
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:      "Success",
+			strUserID: "1",
+			userID:    1,
+			user:      testUser,
+			wantCode:  http.StatusOK,
+			wantBody:  testUserBody,
+		},
+		{
+			name:         "Not found error",
+			strUserID:    "1",
+			userID:       1,
+			useCaseError: usecase.NotFoundError{},
+			wantCode:     http.StatusNotFound,
+			wantBody:     `{"code":"not_found","msg":"ページが見つかりません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockUserUseCase{}
+			mock.On("GetData", tt.userID).Return(tt.user, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewUsersHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.strUserID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.GetData(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("GetData() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("GetData() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_usersHandler_UpdateData(t *testing.T) {
+	proportion := 50
+	outOfRange := 101
+
+	tests := []struct {
+		name         string
+		strUserID    string
+		userID       int
+		body         string
+		req          *usecase.UpdateUserParam
+		user         *model.User
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:      "Success",
+			strUserID: "1",
+			userID:    1,
+			body:      `{"proportion":50}`,
+			req:       &usecase.UpdateUserParam{Proportion: &proportion},
+			user:      testUser,
+			wantCode:  http.StatusOK,
+			wantBody:  testUserBody,
+		},
+		{
+			name:         "Bad request error invalid proportion",
+			strUserID:    "1",
+			userID:       1,
+			body:         `{"proportion":101}`,
+			req:          &usecase.UpdateUserParam{Proportion: &outOfRange},
+			useCaseError: usecase.InvalidParamError{},
+			wantCode:     http.StatusBadRequest,
+			wantBody:     `{"code":"bad_request","msg":"要求の形式が正しくありません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockUserUseCase{}
+			mock.On("Update", tt.req, tt.userID).Return(tt.user, tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tt.body))
+			rr := httptest.NewRecorder()
+			h := rest.NewUsersHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.strUserID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.UpdateData(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("UpdateData() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("UpdateData() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_usersHandler_DeleteData(t *testing.T) {
+	tests := []struct {
+		name         string
+		strUserID    string
+		userID       int
+		useCaseError error
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			name:      "Success",
+			strUserID: "1",
+			userID:    1,
+			wantCode:  http.StatusNoContent,
+			wantBody:  "",
+		},
+		{
+			name:         "Not found error",
+			strUserID:    "1",
+			userID:       1,
+			useCaseError: usecase.NotFoundError{},
+			wantCode:     http.StatusNotFound,
+			wantBody:     `{"code":"not_found","msg":"ページが見つかりません。"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := &mockUserUseCase{}
+			mock.On("DeleteByID", tt.userID).Return(tt.useCaseError)
+
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			rr := httptest.NewRecorder()
+			h := rest.NewUsersHandler(mock)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("user_id", tt.strUserID)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			h.DeleteData(rr, r)
+
+			if diff := cmp.Diff(tt.wantCode, rr.Code); diff != "" {
+				t.Errorf("DeleteData() mismatch status code (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantBody, rr.Body.String()); diff != "" {
+				t.Errorf("DeleteData() mismatch body (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type mockUserUseCase struct {
+	mock.Mock
+}
+
+func (m *mockUserUseCase) GetData(userID int) (*model.User, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).(*model.User), ret.Error(1)
+}
+
+func (m *mockUserUseCase) Update(param *usecase.UpdateUserParam, userID int) (*model.User, error) {
+	ret := m.Called(param, userID)
+	return ret.Get(0).(*model.User), ret.Error(1)
+}
+
+func (m *mockUserUseCase) DeleteByID(ctx context.Context, userID int) error {
+	ret := m.Called(userID)
+	return ret.Error(0)
+}
+
+func (m *mockUserUseCase) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	ret := m.Called(retention)
+	return ret.Int(0), ret.Error(1)
+}
@@ -0,0 +1,146 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra/persistence"
+)
+
+func NewCategoryRepository(db *sql.DB) *categoryPersistencePostgres {
+	return &categoryPersistencePostgres{
+		db: db,
+	}
+}
+
+var _ repository.CategoryRepository = &categoryPersistencePostgres{}
+
+type categoryPersistencePostgres struct {
+	db *sql.DB
+}
+
+func (r *categoryPersistencePostgres) GetData(userID int) ([]*model.Category, error) {
+	categories, err := persistence.SelectCategories(r.db, userID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return categories, nil
+}
+
+func (r *categoryPersistencePostgres) FindByID(userID, categoryID int) (*model.Category, error) {
+	c, err := r.findByID(userID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.toModel(c), nil
+}
+
+func (r *categoryPersistencePostgres) Create(mc *model.Category) (*model.Category, error) {
+	now := time.Now()
+
+	c := &persistence.Category{
+		Name:      mc.Name,
+		UserID:    mc.UserID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := c.Save(r.db); err != nil {
+		return nil, translateError(err)
+	}
+
+	return r.toModel(c), nil
+}
+
+func (r *categoryPersistencePostgres) Update(mc *model.Category) (*model.Category, error) {
+	now := time.Now()
+
+	c, err := r.findByID(int(mc.UserID.Int64), mc.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Name = mc.Name
+	switch {
+	case mc.Archived && !c.ArchivedAt.Valid:
+		c.ArchivedAt = pq.NullTime{Time: now, Valid: true}
+	case !mc.Archived:
+		c.ArchivedAt = pq.NullTime{}
+	}
+	c.UpdatedAt = now
+
+	if err := c.Save(r.db); err != nil {
+		return nil, translateError(err)
+	}
+
+	return r.toModel(c), nil
+}
+
+func (r *categoryPersistencePostgres) DeleteByID(userID, categoryID int) error {
+	c, err := r.findByID(userID, categoryID)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Delete(r.db); err != nil {
+		return translateDeleteError(err)
+	}
+	return nil
+}
+
+func (r *categoryPersistencePostgres) IsReferenced(userID, categoryID int) (bool, error) {
+	exists, err := persistence.ExistsCategoryReference(r.db, userID, categoryID)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return exists, nil
+}
+
+// findByID : 共通カテゴリと他のユーザーのカテゴリは存在しないものとしてrepository.ErrNotFoundを返す
+func (r *categoryPersistencePostgres) findByID(userID, categoryID int) (*persistence.Category, error) {
+	c, err := persistence.CategoryByID(r.db, categoryID)
+	if err == sql.ErrNoRows {
+		return nil, errors.WithStack(repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if !c.UserID.Valid || int(c.UserID.Int64) != userID {
+		return nil, errors.WithStack(repository.ErrNotFound)
+	}
+
+	return c, nil
+}
+
+func (*categoryPersistencePostgres) toModel(c *persistence.Category) *model.Category {
+	return &model.Category{
+		ID:        c.ID,
+		UserID:    c.UserID,
+		Name:      c.Name,
+		Archived:  c.ArchivedAt.Valid,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// checkCategory : 共通カテゴリとuserIDのユーザーのカテゴリ以外はcategory_idのrepository.InvalidFieldErrorを返す
+func checkCategory(ctx context.Context, db persistence.XODB, userID, categoryID int) error {
+	available, err := persistence.IsCategoryAvailable(ctx, db, userID, categoryID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !available {
+		return errors.WithStack(repository.InvalidFieldError{Field: "category_id"})
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package infra_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra"
+	"github.com/warikan/db"
+	"github.com/warikan/test"
+)
+
+func TestCategoryPersistencePostgres_GetData(t *testing.T) {
+	r := infra.NewCategoryRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		userID int
+		want   []*model.Category
+	}{
+		{
+			name:   "Common and own categories",
+			userID: 10001,
+			want: []*model.Category{
+				{ID: 1, Name: "家賃"},
+				{ID: 100, UserID: sql.NullInt64{Int64: 10001, Valid: true}, Name: "ペット"},
+			},
+		},
+		{
+			name:   "Other user's categories are excluded",
+			userID: 99999,
+			want: []*model.Category{
+				{ID: 1, Name: "家賃"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.GetData(tt.userID)
+			if err != nil {
+				t.Fatalf("err should be nil, but got %q", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(model.Category{}, "CreatedAt", "UpdatedAt")); diff != "" {
+				t.Errorf("GetData() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCategoryPersistencePostgres_Update(t *testing.T) {
+	r := infra.NewCategoryRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	arg := &model.Category{
+		ID:       100,
+		UserID:   sql.NullInt64{Int64: 10001, Valid: true},
+		Name:     "ペット用品",
+		Archived: true,
+	}
+
+	got, err := r.Update(arg)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+	if diff := cmp.Diff(arg, got, cmpopts.IgnoreFields(model.Category{}, "CreatedAt", "UpdatedAt")); diff != "" {
+		t.Errorf("Update() mismatch (-want +got):\n%s", diff)
+	}
+
+	// 共通カテゴリは変更できない
+	common := &model.Category{ID: 1, UserID: sql.NullInt64{Int64: 10001, Valid: true}, Name: "家賃"}
+	if _, err := r.Update(common); errors.Cause(err) != repository.ErrNotFound {
+		t.Errorf("Update() unexpected error:\nwant: %v\ngot : %v", repository.ErrNotFound, err)
+	}
+}
+
+func TestCategoryPersistencePostgres_IsReferenced(t *testing.T) {
+	r := infra.NewCategoryRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		userID     int
+		categoryID int
+		want       bool
+	}{
+		{
+			name:       "Referenced by payments",
+			userID:     10001,
+			categoryID: 1,
+			want:       true,
+		},
+		{
+			name:       "Not referenced",
+			userID:     10001,
+			categoryID: 100,
+			want:       false,
+		},
+		{
+			name:       "Not referenced by the user's payments",
+			userID:     10003,
+			categoryID: 1,
+			want:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.IsReferenced(tt.userID, tt.categoryID)
+			if err != nil {
+				t.Fatalf("err should be nil, but got %q", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsReferenced() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
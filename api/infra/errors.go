@@ -0,0 +1,55 @@
+package infra
+
+import (
+	"strings"
+
+	"github.com/jackc/pgconn"
+	"github.com/pkg/errors"
+
+	"github.com/warikan/api/domain/repository"
+)
+
+// PostgreSQLのエラーコード
+const (
+	notNullViolation          = "23502"
+	foreignKeyViolation       = "23503"
+	uniqueViolation           = "23505"
+	invalidTextRepresentation = "22P02"
+	numericValueOutOfRange    = "22003"
+)
+
+// translateError : PostgreSQLの制約違反などをリポジトリのエラーに変換する。それ以外のエラーはそのまま返す
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return errors.WithStack(err)
+	}
+
+	switch pgErr.Code {
+	case foreignKeyViolation:
+		return errors.WithStack(repository.InvalidFieldError{Field: constraintField(pgErr)})
+	case notNullViolation:
+		return errors.WithStack(repository.InvalidFieldError{Field: pgErr.ColumnName})
+	case invalidTextRepresentation, numericValueOutOfRange:
+		return errors.WithStack(repository.InvalidFieldError{Field: pgErr.ColumnName})
+	case uniqueViolation:
+		return errors.WithStack(repository.ErrConflict)
+	default:
+		return errors.WithStack(err)
+	}
+}
+
+// translateDeleteError : 削除時の外部キー制約違反は他の行から参照されているため、repository.ErrConflictを返す
+func translateDeleteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return errors.WithStack(repository.ErrConflict)
+	}
+	return translateError(err)
+}
+
+// constraintField : payments_category_id_fkeyのような制約名から列名を取り出す
+func constraintField(pgErr *pgconn.PgError) string {
+	name := strings.TrimPrefix(pgErr.ConstraintName, pgErr.TableName+"_")
+	return strings.TrimSuffix(name, "_fkey")
+}
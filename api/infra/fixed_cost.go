@@ -0,0 +1,188 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra/persistence"
+)
+
+func NewFixedCostRepository(db *sql.DB) *fixedCostPersistencePostgres {
+	return &fixedCostPersistencePostgres{
+		db: db,
+	}
+}
+
+var _ repository.FixedCostRepository = &fixedCostPersistencePostgres{}
+
+type fixedCostPersistencePostgres struct {
+	db *sql.DB
+}
+
+func (r *fixedCostPersistencePostgres) GetData(ctx context.Context, userID int) ([]*model.FixedCost, error) {
+	fixedCosts, err := persistence.SelectFixedCosts(ctx, conn(ctx, r.db), userID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return fixedCosts, nil
+}
+
+func (r *fixedCostPersistencePostgres) GetAll(ctx context.Context) ([]*model.FixedCost, error) {
+	fixedCosts, err := persistence.SelectAllFixedCosts(ctx, conn(ctx, r.db))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return fixedCosts, nil
+}
+
+func (r *fixedCostPersistencePostgres) Create(ctx context.Context, mf *model.FixedCost) (*model.FixedCost, error) {
+	now := time.Now()
+
+	if err := checkCategory(ctx, conn(ctx, r.db), mf.UserID, mf.CategoryID); err != nil {
+		return nil, err
+	}
+
+	f := &persistence.FixedCost{
+		UserID:      mf.UserID,
+		CategoryID:  mf.CategoryID,
+		PayerID:     mf.PayerID,
+		Description: mf.Description,
+		PaymentDate: mf.PaymentDate,
+		Payment:     mf.Payment,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := f.Save(ctx, conn(ctx, r.db)); err != nil {
+		return nil, translateError(err)
+	}
+
+	return r.toModel(f), nil
+}
+
+func (*fixedCostPersistencePostgres) toModel(f *persistence.FixedCost) *model.FixedCost {
+	return &model.FixedCost{
+		ID:          f.ID,
+		UserID:      f.UserID,
+		CategoryID:  f.CategoryID,
+		PayerID:     f.PayerID,
+		Description: f.Description,
+		PaymentDate: f.PaymentDate,
+		Payment:     f.Payment,
+		CreatedAt:   f.CreatedAt,
+		UpdatedAt:   f.UpdatedAt,
+	}
+}
+
+func (r *fixedCostPersistencePostgres) Update(ctx context.Context, mf *model.FixedCost) (*model.FixedCost, error) {
+	now := time.Now()
+
+	f, err := r.findByID(ctx, mf.UserID, mf.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkCategory(ctx, conn(ctx, r.db), mf.UserID, mf.CategoryID); err != nil {
+		return nil, err
+	}
+
+	f.CategoryID = mf.CategoryID
+	f.PayerID = mf.PayerID
+	f.Description = mf.Description
+	f.PaymentDate = mf.PaymentDate
+	f.Payment = mf.Payment
+	f.UpdatedAt = now
+
+	if err := f.Save(ctx, conn(ctx, r.db)); err != nil {
+		return nil, translateError(err)
+	}
+
+	return r.toModel(f), nil
+}
+
+func (r *fixedCostPersistencePostgres) DeleteByID(ctx context.Context, userID, fixedCostID int) error {
+	f, err := r.findByID(ctx, userID, fixedCostID)
+	if err != nil {
+		return err
+	}
+
+	if err := f.Delete(ctx, conn(ctx, r.db)); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (r *fixedCostPersistencePostgres) CreatePayment(ctx context.Context, mf *model.FixedCost, paymentDate time.Time) (bool, error) {
+	now := time.Now()
+
+	// 月の区切りはpaymentDateのタイムゾーンで判定する
+	from := time.Date(paymentDate.Year(), paymentDate.Month(), 1, 0, 0, 0, 0, paymentDate.Location())
+	to := from.AddDate(0, 1, 0)
+
+	p := &persistence.Payment{
+		UserID:      mf.UserID,
+		CategoryID:  mf.CategoryID,
+		PayerID:     mf.PayerID,
+		Description: mf.Description,
+		PaymentDate: paymentDate,
+		Payment:     mf.Payment,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		FixedCostID: sql.NullInt64{Int64: int64(mf.ID), Valid: true},
+	}
+
+	created, err := persistence.InsertPaymentFromFixedCost(ctx, conn(ctx, r.db), p, from, to)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return created, nil
+}
+
+func (r *fixedCostPersistencePostgres) PropagateToPayments(ctx context.Context, mf *model.FixedCost, from time.Time, updatedBy int) (int, error) {
+	f := &persistence.FixedCost{
+		ID:          mf.ID,
+		CategoryID:  mf.CategoryID,
+		PayerID:     mf.PayerID,
+		Description: mf.Description,
+		PaymentDate: mf.PaymentDate,
+		Payment:     mf.Payment,
+		UpdatedAt:   mf.UpdatedAt,
+	}
+
+	var createdBy sql.NullInt64
+	if updatedBy != 0 {
+		createdBy = sql.NullInt64{Int64: int64(updatedBy), Valid: true}
+	}
+
+	n, err := persistence.UpdatePaymentsByFixedCostID(ctx, conn(ctx, r.db), f, from, createdBy)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return n, nil
+}
+
+// findByID : 他のユーザーの固定費は存在しないものとしてrepository.ErrNotFoundを返す
+func (r *fixedCostPersistencePostgres) findByID(ctx context.Context, userID, fixedCostID int) (*persistence.FixedCost, error) {
+	f, err := persistence.FixedCostByID(ctx, conn(ctx, r.db), fixedCostID)
+	if err == sql.ErrNoRows {
+		return nil, errors.WithStack(repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if f.UserID != userID {
+		return nil, errors.WithStack(repository.ErrNotFound)
+	}
+
+	return f, nil
+}
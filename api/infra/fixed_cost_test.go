@@ -0,0 +1,272 @@
+package infra_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra"
+	"github.com/warikan/db"
+	"github.com/warikan/test"
+)
+
+func TestFixedCostPersistencePostgres_Create(t *testing.T) {
+	r := infra.NewFixedCostRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	arg := &model.FixedCost{
+		UserID:      10001,
+		CategoryID:  1,
+		PayerID:     2,
+		Description: sql.NullString{String: "光熱費", Valid: true},
+		PaymentDate: time.Date(2020, time.April, 10, 0, 0, 0, 0, time.UTC),
+		Payment:     12000,
+	}
+
+	got, err := r.Create(context.Background(), arg)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+
+	if diff := cmp.Diff(arg, got, cmpopts.IgnoreFields(model.FixedCost{}, "ID", "CreatedAt", "UpdatedAt")); diff != "" {
+		t.Errorf("Create() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFixedCostPersistencePostgres_CreateOtherUsersCategory(t *testing.T) {
+	r := infra.NewFixedCostRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	arg := &model.FixedCost{
+		UserID:      10002,
+		CategoryID:  100,
+		PayerID:     1,
+		PaymentDate: time.Date(2020, time.April, 10, 0, 0, 0, 0, time.UTC),
+		Payment:     12000,
+	}
+
+	want := repository.InvalidFieldError{Field: "category_id"}
+	if _, err := r.Create(context.Background(), arg); errors.Cause(err) != want {
+		t.Errorf("Create() unexpected error:\nwant: %v\ngot : %v", want, err)
+	}
+}
+
+func TestFixedCostPersistencePostgres_Update(t *testing.T) {
+	r := infra.NewFixedCostRepository(db.Pool)
+
+	tests := []struct {
+		name    string
+		arg     *model.FixedCost
+		want    *model.FixedCost
+		wantErr error
+	}{
+		{
+			name: "Success",
+			arg: &model.FixedCost{
+				ID:          29999,
+				UserID:      10001,
+				CategoryID:  1,
+				PayerID:     2,
+				Description: sql.NullString{String: "家賃(更新後)", Valid: true},
+				PaymentDate: time.Date(2020, time.April, 27, 0, 0, 0, 0, time.UTC),
+				Payment:     85000,
+			},
+			want: &model.FixedCost{
+				ID:          29999,
+				UserID:      10001,
+				CategoryID:  1,
+				PayerID:     2,
+				Description: sql.NullString{String: "家賃(更新後)", Valid: true},
+				PaymentDate: time.Date(2020, time.April, 27, 0, 0, 0, 0, time.UTC),
+				Payment:     85000,
+			},
+		},
+		{
+			name: "Other user's fixed cost",
+			arg: &model.FixedCost{
+				ID:          29999,
+				UserID:      99999,
+				CategoryID:  1,
+				PayerID:     2,
+				PaymentDate: time.Date(2020, time.April, 27, 0, 0, 0, 0, time.UTC),
+				Payment:     85000,
+			},
+			wantErr: repository.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := r.Update(context.Background(), tt.arg)
+			if tt.wantErr != nil {
+				if errors.Cause(err) != tt.wantErr {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(model.FixedCost{}, "CreatedAt", "UpdatedAt")); diff != "" {
+				t.Errorf("Update() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFixedCostPersistencePostgres_CreatePayment(t *testing.T) {
+	r := infra.NewFixedCostRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &model.FixedCost{
+		ID:          29999,
+		UserID:      10001,
+		CategoryID:  1,
+		PayerID:     1,
+		Description: sql.NullString{String: "家賃", Valid: true},
+		Payment:     80000,
+	}
+
+	// 同じ月に2回実行しても支払いは1件しか作成されない
+	paymentDates := []time.Time{
+		time.Date(2020, time.May, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, time.May, 26, 0, 0, 0, 0, time.UTC),
+	}
+	wants := []bool{true, false}
+
+	for i, d := range paymentDates {
+		got, err := r.CreatePayment(context.Background(), f, d)
+		if err != nil {
+			t.Fatalf("err should be nil, but got %q", err)
+		}
+		if got != wants[i] {
+			t.Errorf("CreatePayment(%v) = %v, want %v", d, got, wants[i])
+		}
+	}
+
+	// 支払日を31日に変更すると、5月の支払いは5月31日(JST)になる
+	n, err := r.PropagateToPayments(context.Background(), &model.FixedCost{
+		ID:          29999,
+		CategoryID:  1,
+		PayerID:     2,
+		PaymentDate: time.Date(2020, time.March, 30, 15, 0, 0, 0, time.UTC),
+		Payment:     85000,
+		UpdatedAt:   time.Now(),
+	}, time.Date(2020, time.May, 1, 0, 0, 0, 0, time.UTC), 0)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+	if n != 1 {
+		t.Errorf("PropagateToPayments() = %d, want 1", n)
+	}
+
+	var paymentDate time.Time
+	if err := db.Pool.QueryRowContext(context.Background(), "SELECT payment_date FROM payments WHERE fixed_cost_id = 29999").Scan(&paymentDate); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2020, time.May, 30, 15, 0, 0, 0, time.UTC); !paymentDate.Equal(want) {
+		t.Errorf("PropagateToPayments() payment_date = %v, want %v", paymentDate, want)
+	}
+}
+
+func TestFixedCostPersistencePostgres_PropagateToEditedPayments(t *testing.T) {
+	r := infra.NewFixedCostRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &model.FixedCost{
+		ID:          29999,
+		UserID:      10001,
+		CategoryID:  1,
+		PayerID:     1,
+		Description: sql.NullString{String: "家賃", Valid: true},
+		Payment:     80000,
+	}
+	for _, d := range []time.Time{
+		time.Date(2020, time.May, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, time.June, 25, 0, 0, 0, 0, time.UTC),
+	} {
+		if _, err := r.CreatePayment(context.Background(), f, d); err != nil {
+			t.Fatalf("err should be nil, but got %q", err)
+		}
+	}
+
+	// 5月の支払いは承認済みのまま手で金額を変更し、6月の支払いはユーザーが登録した承認待ちにする
+	if _, err := db.Pool.ExecContext(context.Background(),
+		"UPDATE payments SET payment = 70000, updated_at = NOW() WHERE fixed_cost_id = 29999 AND payment_date < '2020-06-01'"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Pool.ExecContext(context.Background(),
+		"UPDATE payments SET status = 'pending', created_by = 10001 WHERE fixed_cost_id = 29999 AND payment_date >= '2020-06-01'"); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := r.PropagateToPayments(context.Background(), &model.FixedCost{
+		ID:          29999,
+		CategoryID:  1,
+		PayerID:     1,
+		Description: sql.NullString{String: "家賃", Valid: true},
+		PaymentDate: time.Date(2020, time.April, 24, 15, 0, 0, 0, time.UTC),
+		Payment:     85000,
+		UpdatedAt:   time.Now(),
+	}, time.Date(2020, time.May, 1, 0, 0, 0, 0, time.UTC), 10002)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+	if n != 2 {
+		t.Errorf("PropagateToPayments() = %d, want 2", n)
+	}
+
+	type row struct {
+		Payment   int
+		Status    string
+		CreatedBy sql.NullInt64
+	}
+	// 手で変更した支払いも上書きし、承認済みだった支払いは変更したユーザーの登録として承認待ちに戻す
+	want := []row{
+		{Payment: 85000, Status: model.PaymentStatusPending, CreatedBy: sql.NullInt64{Int64: 10002, Valid: true}},
+		{Payment: 85000, Status: model.PaymentStatusPending, CreatedBy: sql.NullInt64{Int64: 10001, Valid: true}},
+	}
+
+	q, err := db.Pool.QueryContext(context.Background(), "SELECT payment, status, created_by FROM payments WHERE fixed_cost_id = 29999 ORDER BY payment_date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+
+	got := make([]row, 0)
+	for q.Next() {
+		var r row
+		if err := q.Scan(&r.Payment, &r.Status, &r.CreatedBy); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, r)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("PropagateToPayments() mismatch (-want +got):\n%s", diff)
+	}
+}
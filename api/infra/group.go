@@ -0,0 +1,229 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra/persistence"
+)
+
+func NewGroupRepository(db *sql.DB) *groupPersistencePostgres {
+	return &groupPersistencePostgres{
+		db: db,
+	}
+}
+
+var _ repository.GroupRepository = &groupPersistencePostgres{}
+
+type groupPersistencePostgres struct {
+	db *sql.DB
+}
+
+func (r *groupPersistencePostgres) GetData(ctx context.Context, userID int) ([]*model.Group, error) {
+	groups, err := persistence.SelectGroups(ctx, conn(ctx, r.db), userID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return groups, nil
+}
+
+func (r *groupPersistencePostgres) FindByID(ctx context.Context, userID, groupID int) (*model.Group, error) {
+	g, err := r.findByID(ctx, userID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Group{
+		ID:        g.ID,
+		UserID:    g.UserID,
+		Name:      g.Name,
+		CreatedAt: g.CreatedAt,
+		UpdatedAt: g.UpdatedAt,
+	}, nil
+}
+
+func (r *groupPersistencePostgres) Create(ctx context.Context, mg *model.Group) (*model.Group, error) {
+	now := time.Now()
+
+	g := &persistence.Group{
+		UserID:    mg.UserID,
+		Name:      mg.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := g.Save(ctx, conn(ctx, r.db)); err != nil {
+		return nil, translateError(err)
+	}
+
+	return &model.Group{
+		ID:        g.ID,
+		UserID:    g.UserID,
+		Name:      g.Name,
+		CreatedAt: g.CreatedAt,
+		UpdatedAt: g.UpdatedAt,
+	}, nil
+}
+
+func (r *groupPersistencePostgres) DeleteByID(ctx context.Context, userID, groupID int) error {
+	g, err := r.findByID(ctx, userID, groupID)
+	if err != nil {
+		return err
+	}
+
+	if err := g.Delete(ctx, conn(ctx, r.db)); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// findByID : 存在しないグループと他のユーザーのグループはrepository.ErrNotFoundを返す
+func (r *groupPersistencePostgres) findByID(ctx context.Context, userID, groupID int) (*persistence.Group, error) {
+	g, err := persistence.GroupByID(ctx, conn(ctx, r.db), groupID)
+	if err == sql.ErrNoRows {
+		return nil, errors.WithStack(repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// 他のユーザーのグループは存在を知らせない
+	if g.UserID != userID {
+		return nil, errors.WithStack(repository.ErrNotFound)
+	}
+
+	return g, nil
+}
+
+func (r *groupPersistencePostgres) GetMembers(ctx context.Context, groupID int) ([]*model.GroupMember, error) {
+	members, err := persistence.SelectGroupMembers(ctx, conn(ctx, r.db), groupID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return members, nil
+}
+
+func (r *groupPersistencePostgres) CreateMember(ctx context.Context, mm *model.GroupMember) (*model.GroupMember, error) {
+	now := time.Now()
+
+	m := &persistence.GroupMember{
+		GroupID:   mm.GroupID,
+		Name:      mm.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// 同じ名前のメンバーはgroup_id, nameの一意制約違反になる
+	if err := m.Save(ctx, conn(ctx, r.db)); err != nil {
+		return nil, translateError(err)
+	}
+
+	return &model.GroupMember{
+		ID:        m.ID,
+		GroupID:   m.GroupID,
+		Name:      m.Name,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}, nil
+}
+
+func (r *groupPersistencePostgres) GetPayments(ctx context.Context, groupID int) ([]*model.GroupPayment, error) {
+	payments, err := persistence.SelectGroupPayments(ctx, conn(ctx, r.db), groupID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	splits, err := persistence.SelectGroupPaymentSplits(ctx, conn(ctx, r.db), groupID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	byPayment := map[int][]*model.GroupPaymentSplit{}
+	for _, s := range splits {
+		byPayment[s.GroupPaymentID] = append(byPayment[s.GroupPaymentID], &model.GroupPaymentSplit{
+			MemberID: s.MemberID,
+			Value:    s.Value,
+		})
+	}
+	for _, p := range payments {
+		p.Splits = byPayment[p.ID]
+	}
+
+	return payments, nil
+}
+
+func (r *groupPersistencePostgres) CreatePayment(ctx context.Context, mp *model.GroupPayment) (*model.GroupPayment, error) {
+	now := time.Now()
+
+	p := &persistence.GroupPayment{
+		GroupID:       mp.GroupID,
+		PayerMemberID: mp.PayerMemberID,
+		Description:   mp.Description,
+		PaymentDate:   mp.PaymentDate,
+		Payment:       mp.Payment,
+		SplitType:     mp.SplitType,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := p.Save(ctx, conn(ctx, r.db)); err != nil {
+		return nil, translateError(err)
+	}
+
+	payment := &model.GroupPayment{
+		ID:            p.ID,
+		GroupID:       p.GroupID,
+		PayerMemberID: p.PayerMemberID,
+		Description:   p.Description,
+		PaymentDate:   p.PaymentDate,
+		Payment:       p.Payment,
+		SplitType:     p.SplitType,
+		Splits:        make([]*model.GroupPaymentSplit, 0, len(mp.Splits)),
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+
+	for _, ms := range mp.Splits {
+		s := &persistence.GroupPaymentSplit{
+			GroupPaymentID: p.ID,
+			MemberID:       ms.MemberID,
+			Value:          ms.Value,
+		}
+		if err := s.Insert(ctx, conn(ctx, r.db)); err != nil {
+			return nil, translateError(err)
+		}
+		payment.Splits = append(payment.Splits, &model.GroupPaymentSplit{
+			MemberID: s.MemberID,
+			Value:    s.Value,
+		})
+	}
+
+	return payment, nil
+}
+
+func (r *groupPersistencePostgres) DeletePayment(ctx context.Context, groupID, groupPaymentID int) error {
+	p, err := persistence.GroupPaymentByID(ctx, conn(ctx, r.db), groupPaymentID)
+	if err == sql.ErrNoRows {
+		return errors.WithStack(repository.ErrNotFound)
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// 他のグループの支払いは存在しないものとして扱う
+	if p.GroupID != groupID {
+		return errors.WithStack(repository.ErrNotFound)
+	}
+
+	if err := p.Delete(ctx, conn(ctx, r.db)); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
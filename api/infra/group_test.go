@@ -0,0 +1,240 @@
+package infra_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra"
+	"github.com/warikan/db"
+	"github.com/warikan/test"
+)
+
+func TestGroupPersistencePostgres_FindByID(t *testing.T) {
+	r := infra.NewGroupRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		userID  int
+		groupID int
+		want    *model.Group
+		wantErr error
+	}{
+		{
+			name:    "Success",
+			userID:  10001,
+			groupID: 59999,
+			want:    &model.Group{ID: 59999, UserID: 10001, Name: "旅行"},
+		},
+		{
+			name:    "NotFound other user's group",
+			userID:  10002,
+			groupID: 59999,
+			wantErr: repository.ErrNotFound,
+		},
+		{
+			name:    "NotFound",
+			userID:  10001,
+			groupID: 1,
+			wantErr: repository.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.FindByID(context.Background(), tt.userID, tt.groupID)
+			if errors.Cause(err) != tt.wantErr {
+				t.Fatalf("FindByID() unexpected error:\nwant: %v\ngot : %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(model.Group{}, "CreatedAt", "UpdatedAt")); diff != "" {
+				t.Errorf("FindByID() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGroupPersistencePostgres_CreateMember(t *testing.T) {
+	r := infra.NewGroupRepository(db.Pool)
+
+	tests := []struct {
+		name       string
+		memberName string
+		wantErr    error
+	}{
+		{
+			name:       "Success",
+			memberName: "D",
+		},
+		{
+			name:       "Conflict same name",
+			memberName: "A",
+			wantErr:    repository.ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := r.CreateMember(context.Background(), &model.GroupMember{GroupID: 59999, Name: tt.memberName})
+			if errors.Cause(err) != tt.wantErr {
+				t.Fatalf("CreateMember() unexpected error:\nwant: %v\ngot : %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			members, err := r.GetMembers(context.Background(), 59999)
+			if err != nil {
+				t.Fatalf("err should be nil, but got %q", err)
+			}
+			names := make([]string, 0, len(members))
+			for _, m := range members {
+				names = append(names, m.Name)
+			}
+			if diff := cmp.Diff([]string{"A", "B", "C", "D"}, names); diff != "" {
+				t.Errorf("GetMembers() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGroupPersistencePostgres_GetPayments(t *testing.T) {
+	r := infra.NewGroupRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.GetPayments(context.Background(), 59999)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+
+	want := []*model.GroupPayment{
+		{
+			ID:            59101,
+			GroupID:       59999,
+			PayerMemberID: 59001,
+			PaymentDate:   time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+			Payment:       3000,
+			SplitType:     model.SplitEqual,
+			Splits: []*model.GroupPaymentSplit{
+				{MemberID: 59001},
+				{MemberID: 59002},
+				{MemberID: 59003},
+			},
+		},
+		{
+			ID:            59102,
+			GroupID:       59999,
+			PayerMemberID: 59002,
+			Description:   sql.NullString{String: "タクシー", Valid: true},
+			PaymentDate:   time.Date(2020, time.April, 2, 0, 0, 0, 0, time.UTC),
+			Payment:       1000,
+			SplitType:     model.SplitExact,
+			Splits: []*model.GroupPaymentSplit{
+				{MemberID: 59003, Value: 1000},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(model.GroupPayment{}, "CreatedAt", "UpdatedAt"), cmp.Comparer(func(x, y time.Time) bool { return x.Equal(y) })); diff != "" {
+		t.Errorf("GetPayments() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGroupPersistencePostgres_CreatePayment(t *testing.T) {
+	r := infra.NewGroupRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	arg := &model.GroupPayment{
+		GroupID:       59999,
+		PayerMemberID: 59003,
+		PaymentDate:   time.Date(2020, time.April, 3, 0, 0, 0, 0, time.UTC),
+		Payment:       900,
+		SplitType:     model.SplitShares,
+		Splits: []*model.GroupPaymentSplit{
+			{MemberID: 59001, Value: 1},
+			{MemberID: 59002, Value: 2},
+		},
+	}
+	created, err := r.CreatePayment(context.Background(), arg)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+
+	payments, err := r.GetPayments(context.Background(), 59999)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+	got := payments[len(payments)-1]
+	if diff := cmp.Diff(created, got, cmpopts.IgnoreFields(model.GroupPayment{}, "CreatedAt", "UpdatedAt"), cmp.Comparer(func(x, y time.Time) bool { return x.Equal(y) })); diff != "" {
+		t.Errorf("CreatePayment() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGroupPersistencePostgres_DeletePayment(t *testing.T) {
+	r := infra.NewGroupRepository(db.Pool)
+
+	tests := []struct {
+		name           string
+		groupID        int
+		groupPaymentID int
+		wantErr        error
+	}{
+		{
+			name:           "Success",
+			groupID:        59999,
+			groupPaymentID: 59101,
+		},
+		{
+			name:           "NotFound other group",
+			groupID:        1,
+			groupPaymentID: 59101,
+			wantErr:        repository.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+				t.Fatal(err)
+			}
+
+			err := r.DeletePayment(context.Background(), tt.groupID, tt.groupPaymentID)
+			if errors.Cause(err) != tt.wantErr {
+				t.Fatalf("DeletePayment() unexpected error:\nwant: %v\ngot : %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			payments, err := r.GetPayments(context.Background(), 59999)
+			if err != nil {
+				t.Fatalf("err should be nil, but got %q", err)
+			}
+			if len(payments) != 1 || payments[0].ID != 59102 {
+				t.Errorf("GetPayments() after delete = %+v", payments)
+			}
+		})
+	}
+}
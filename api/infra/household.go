@@ -0,0 +1,85 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra/persistence"
+)
+
+func NewHouseholdRepository(db *sql.DB) *householdPersistencePostgres {
+	return &householdPersistencePostgres{
+		db: db,
+	}
+}
+
+var _ repository.HouseholdRepository = &householdPersistencePostgres{}
+
+type householdPersistencePostgres struct {
+	db *sql.DB
+}
+
+func (r *householdPersistencePostgres) FindByUserID(ctx context.Context, userID int) (*model.Household, error) {
+	h, err := persistence.SelectHouseholdByUserID(ctx, conn(ctx, r.db), userID)
+	if err == sql.ErrNoRows {
+		return nil, errors.WithStack(repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return r.toModel(h), nil
+}
+
+func (r *householdPersistencePostgres) SaveInvitation(ctx context.Context, ownerID int, tokenHash string, expiresAt time.Time) (*model.Household, error) {
+	h, err := persistence.UpsertHouseholdInvitation(ctx, conn(ctx, r.db), ownerID, tokenHash, expiresAt, time.Now())
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return r.toModel(h), nil
+}
+
+func (r *householdPersistencePostgres) Accept(ctx context.Context, tokenHash string, partnerID int, now time.Time) (*model.Household, error) {
+	h, err := persistence.AcceptHouseholdInvitation(ctx, conn(ctx, r.db), tokenHash, partnerID, now)
+	if err == sql.ErrNoRows {
+		return nil, errors.WithStack(repository.ErrNotFound)
+	}
+	if err != nil {
+		// 他の家計簿に既に参加している場合はpartner_idの一意制約違反になる
+		return nil, translateError(err)
+	}
+
+	return r.toModel(h), nil
+}
+
+func (r *householdPersistencePostgres) DeleteByID(ctx context.Context, householdID int) error {
+	h, err := persistence.HouseholdByID(ctx, conn(ctx, r.db), householdID)
+	if err == sql.ErrNoRows {
+		return errors.WithStack(repository.ErrNotFound)
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := h.Delete(ctx, conn(ctx, r.db)); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (*householdPersistencePostgres) toModel(h *persistence.Household) *model.Household {
+	return &model.Household{
+		ID:                  h.ID,
+		OwnerID:             h.OwnerID,
+		PartnerID:           int(h.PartnerID.Int64),
+		InvitationExpiresAt: h.InvitationExpiresAt.Time,
+		CreatedAt:           h.CreatedAt,
+		UpdatedAt:           h.UpdatedAt,
+	}
+}
@@ -0,0 +1,174 @@
+package infra_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra"
+	"github.com/warikan/db"
+	"github.com/warikan/test"
+)
+
+// invitationTokenHash : フィクスチャの家計簿に設定した招待トークンのハッシュ
+func invitationTokenHash() string {
+	sum := sha256.Sum256([]byte("invitation-token"))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestHouseholdPersistencePostgres_FindByUserID(t *testing.T) {
+	r := infra.NewHouseholdRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.FindByUserID(context.Background(), 10001)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+
+	want := &model.Household{
+		ID:                  49999,
+		OwnerID:             10001,
+		InvitationExpiresAt: time.Date(2099, time.December, 31, 0, 0, 0, 0, time.UTC),
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(model.Household{}, "CreatedAt", "UpdatedAt"), cmp.Comparer(func(x, y time.Time) bool { return x.Equal(y) })); diff != "" {
+		t.Errorf("FindByUserID() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := r.FindByUserID(context.Background(), 10002); errors.Cause(err) != repository.ErrNotFound {
+		t.Errorf("FindByUserID() unexpected error:\nwant: %v\ngot : %v", repository.ErrNotFound, err)
+	}
+}
+
+func TestHouseholdPersistencePostgres_SaveInvitation(t *testing.T) {
+	r := infra.NewHouseholdRepository(db.Pool)
+	expiresAt := time.Date(2099, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		ownerID int
+		wantID  int
+	}{
+		{
+			name:    "Reissue invitation",
+			ownerID: 10001,
+			wantID:  49999,
+		},
+		{
+			name:    "Create household",
+			ownerID: 10002,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := r.SaveInvitation(context.Background(), tt.ownerID, "new-hash", expiresAt)
+			if err != nil {
+				t.Fatalf("err should be nil, but got %q", err)
+			}
+			if tt.wantID != 0 && got.ID != tt.wantID {
+				t.Errorf("SaveInvitation() id = %d, want %d", got.ID, tt.wantID)
+			}
+			if got.OwnerID != tt.ownerID || !got.InvitationExpiresAt.Equal(expiresAt) {
+				t.Errorf("SaveInvitation() = %+v", got)
+			}
+
+			// 新しいトークンでのみ承認できる
+			if _, err := r.Accept(context.Background(), invitationTokenHash(), 10003, time.Now()); errors.Cause(err) != repository.ErrNotFound {
+				t.Errorf("Accept() with old token unexpected error:\nwant: %v\ngot : %v", repository.ErrNotFound, err)
+			}
+		})
+	}
+}
+
+func TestHouseholdPersistencePostgres_Accept(t *testing.T) {
+	r := infra.NewHouseholdRepository(db.Pool)
+	payers := infra.NewPayerRepository(db.Pool)
+
+	tests := []struct {
+		name      string
+		tokenHash string
+		partnerID int
+		now       time.Time
+		wantErr   error
+	}{
+		{
+			name:      "Success",
+			tokenHash: invitationTokenHash(),
+			partnerID: 10002,
+			now:       time.Now(),
+		},
+		{
+			name:      "Unknown token",
+			tokenHash: "unknown",
+			partnerID: 10002,
+			now:       time.Now(),
+			wantErr:   repository.ErrNotFound,
+		},
+		{
+			name:      "Expired token",
+			tokenHash: invitationTokenHash(),
+			partnerID: 10002,
+			now:       time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC),
+			wantErr:   repository.ErrNotFound,
+		},
+		{
+			name:      "Own invitation",
+			tokenHash: invitationTokenHash(),
+			partnerID: 10001,
+			now:       time.Now(),
+			wantErr:   repository.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := r.Accept(context.Background(), tt.tokenHash, tt.partnerID, tt.now)
+			if errors.Cause(err) != tt.wantErr {
+				t.Fatalf("Accept() unexpected error:\nwant: %v\ngot : %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.ID != 49999 || got.PartnerID != tt.partnerID {
+				t.Errorf("Accept() = %+v", got)
+			}
+
+			// 同じトークンでは再度承認できない
+			if _, err := r.Accept(context.Background(), tt.tokenHash, 10003, tt.now); errors.Cause(err) != repository.ErrNotFound {
+				t.Errorf("Accept() twice unexpected error:\nwant: %v\ngot : %v", repository.ErrNotFound, err)
+			}
+
+			// 支払者2はパートナーのアカウントに対応する
+			p, err := payers.GetData(10001)
+			if err != nil {
+				t.Fatalf("err should be nil, but got %q", err)
+			}
+			ownerID := 10001
+			want := []*model.Payer{
+				{ID: 1, Name: "ユーザーネーム", UserID: &ownerID},
+				{ID: 2, Name: "別のユーザー", UserID: &tt.partnerID},
+			}
+			if diff := cmp.Diff(want, p); diff != "" {
+				t.Errorf("GetData() after accept mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package infra
+
+import (
+	"database/sql"
+
+	"github.com/pkg/errors"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra/persistence"
+)
+
+func NewPayerRepository(db *sql.DB) *payerPersistencePostgres {
+	return &payerPersistencePostgres{
+		db: db,
+	}
+}
+
+var _ repository.PayerRepository = &payerPersistencePostgres{}
+
+type payerPersistencePostgres struct {
+	db *sql.DB
+}
+
+func (r *payerPersistencePostgres) GetData(userID int) ([]*model.Payer, error) {
+	payers, err := persistence.SelectPayers(r.db, userID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return payers, nil
+}
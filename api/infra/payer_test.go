@@ -0,0 +1,54 @@
+package infra_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/infra"
+	"github.com/warikan/db"
+	"github.com/warikan/test"
+)
+
+func TestPayerPersistencePostgres_GetData(t *testing.T) {
+	r := infra.NewPayerRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+	payerUserID := 10001
+
+	tests := []struct {
+		name   string
+		userID int
+		want   []*model.Payer
+	}{
+		{
+			name:   "Resolve user and partner names",
+			userID: 10001,
+			want: []*model.Payer{
+				{ID: 1, Name: "ユーザーネーム", UserID: &payerUserID},
+				{ID: 2, Name: "パートナーネーム"},
+			},
+		},
+		{
+			name:   "User not found",
+			userID: 99999,
+			want:   []*model.Payer{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.GetData(tt.userID)
+			if err != nil {
+				t.Fatalf("err should be nil, but got %q", err)
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetData() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package infra
 
 import (
+	"context"
 	"database/sql"
 	"time"
 
@@ -11,25 +12,34 @@ import (
 	"github.com/warikan/api/infra/persistence"
 )
 
-func NewPaymentsRepository(db *sql.DB) *paymentPersistencePostgres {
+// NewPaymentsRepository : 1回のクエリはqueryTimeoutで中断する。0の場合は中断しない
+func NewPaymentsRepository(db *sql.DB, queryTimeout time.Duration) *paymentPersistencePostgres {
 	return &paymentPersistencePostgres{
-		db: db,
+		db:           db,
+		queryTimeout: queryTimeout,
 	}
 }
 
 var _ repository.PaymentRepository = &paymentPersistencePostgres{}
 
 type paymentPersistencePostgres struct {
-	db *sql.DB
+	db           *sql.DB
+	queryTimeout time.Duration
 }
 
-func (r *paymentPersistencePostgres) GetData(userID, cursor int) ([]*model.Payment, error) {
-	const (
-		limit = 20
-	)
+// withTimeout : 呼び出し元のctxのキャンセルに加えて、queryTimeoutが経過した場合もクエリを中断する
+func (r *paymentPersistencePostgres) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, r.queryTimeout)
+}
 
-	payments, err := persistence.SelectPayments(r.db, userID, limit, cursor)
+func (r *paymentPersistencePostgres) GetData(ctx context.Context, userID int, filter *model.PaymentFilter, cursor *model.PaymentCursor, limit int) ([]*model.Payment, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
 
+	payments, err := persistence.SelectPayments(ctx, conn(ctx, r.db), userID, filter, cursor, limit)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -37,8 +47,36 @@ func (r *paymentPersistencePostgres) GetData(userID, cursor int) ([]*model.Payme
 	return payments, nil
 }
 
-func (r *paymentPersistencePostgres) Create(mp *model.Payment) (*model.Payment, error) {
-	now := time.Now()
+func (r *paymentPersistencePostgres) FindByID(ctx context.Context, userID, paymentID int) (*model.Payment, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	payment, err := persistence.SelectPayment(ctx, conn(ctx, r.db), userID, paymentID)
+	if err == sql.ErrNoRows {
+		return nil, errors.WithStack(repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return payment, nil
+}
+
+func (r *paymentPersistencePostgres) Create(ctx context.Context, mp *model.Payment) (*model.Payment, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	// 返したupdated_atがバージョンとして保存後の値と一致するよう、データベースの精度に揃える
+	now := time.Now().Truncate(time.Microsecond)
+
+	if err := checkCategory(ctx, conn(ctx, r.db), mp.UserID, mp.CategoryID); err != nil {
+		return nil, err
+	}
+
+	status := mp.Status
+	if status == "" {
+		status = model.PaymentStatusApproved
+	}
 
 	p := &persistence.Payment{
 		UserID:      mp.UserID,
@@ -47,12 +85,14 @@ func (r *paymentPersistencePostgres) Create(mp *model.Payment) (*model.Payment,
 		Description: mp.Description,
 		PaymentDate: mp.PaymentDate,
 		Payment:     mp.Payment,
+		Status:      status,
+		CreatedBy:   mp.CreatedBy,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
 
-	if err := p.Save(r.db); err != nil {
-		return nil, errors.WithStack(err)
+	if err := p.Save(ctx, conn(ctx, r.db)); err != nil {
+		return nil, translateError(err)
 	}
 
 	payment := r.toModel(p)
@@ -69,6 +109,8 @@ func (*paymentPersistencePostgres) toModel(u *persistence.Payment) *model.Paymen
 		Description: u.Description,
 		PaymentDate: u.PaymentDate,
 		Payment:     u.Payment,
+		Status:      u.Status,
+		CreatedBy:   u.CreatedBy,
 		CreatedAt:   u.CreatedAt,
 		UpdatedAt:   u.UpdatedAt,
 	}
@@ -76,12 +118,19 @@ func (*paymentPersistencePostgres) toModel(u *persistence.Payment) *model.Paymen
 	return payment
 }
 
-func (r *paymentPersistencePostgres) Update(mp *model.Payment) (*model.Payment, error) {
-	now := time.Now()
+func (r *paymentPersistencePostgres) Update(ctx context.Context, mp *model.Payment) (*model.Payment, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	now := time.Now().Truncate(time.Microsecond)
 
-	p, err := persistence.PaymentByID(r.db, mp.ID)
+	p, err := r.findByID(ctx, mp.UserID, mp.ID)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, err
+	}
+
+	if err := checkCategory(ctx, conn(ctx, r.db), mp.UserID, mp.CategoryID); err != nil {
+		return nil, err
 	}
 
 	p.CategoryID = mp.CategoryID
@@ -89,41 +138,135 @@ func (r *paymentPersistencePostgres) Update(mp *model.Payment) (*model.Payment,
 	p.Description = mp.Description
 	p.PaymentDate = mp.PaymentDate
 	p.Payment = mp.Payment
+	p.Status = mp.Status
+	p.CreatedBy = mp.CreatedBy
 	p.UpdatedAt = now
 
-	if err := p.Save(r.db); err != nil {
-		return nil, errors.WithStack(err)
+	if mp.UpdatedAt.IsZero() {
+		if err := p.Save(ctx, conn(ctx, r.db)); err != nil {
+			return nil, translateError(err)
+		}
+		return r.toModel(p), nil
 	}
 
-	payment := r.toModel(p)
+	// 取得後に他の更新があった場合は上書きしない
+	updated, err := persistence.UpdatePaymentIfUnmodified(ctx, conn(ctx, r.db), p, mp.UpdatedAt)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	if !updated {
+		return nil, errors.WithStack(repository.ErrConflict)
+	}
 
-	return payment, nil
+	return r.toModel(p), nil
 }
 
-func (r *paymentPersistencePostgres) DeleteByID(userID, paymentID int) error {
-	p, err := persistence.PaymentByID(r.db, paymentID)
+func (r *paymentPersistencePostgres) DeleteByID(ctx context.Context, userID, paymentID int, updatedAt time.Time) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	p, err := r.findByID(ctx, userID, paymentID)
 	if err != nil {
-		return errors.WithStack(err)
+		return err
 	}
 
-	if p.UserID != userID {
-		return errors.WithStack(err)
+	if updatedAt.IsZero() {
+		if err := p.Delete(ctx, conn(ctx, r.db)); err != nil {
+			return errors.WithStack(err)
+		}
+		return nil
 	}
 
-	err = p.Delete(r.db)
+	// 取得後に他の更新があった場合は削除しない
+	deleted, err := persistence.DeletePaymentIfUnmodified(ctx, conn(ctx, r.db), p.ID, updatedAt)
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	if !deleted {
+		return errors.WithStack(repository.ErrConflict)
+	}
 	return nil
 }
 
-func (r *paymentPersistencePostgres) FetchDate(userID int) ([]*string, error) {
+func (r *paymentPersistencePostgres) UpdateStatus(ctx context.Context, userID, reviewerID, paymentID int, status string) (*model.Payment, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	now := time.Now().Truncate(time.Microsecond)
+
+	p, err := r.findByID(ctx, userID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if p.CreatedBy.Valid && int(p.CreatedBy.Int64) == reviewerID {
+		return nil, errors.WithStack(repository.ErrForbidden)
+	}
+	if p.Status != model.PaymentStatusPending {
+		return nil, errors.WithStack(repository.ErrConflict)
+	}
+
+	// 取得後に他のリクエストで承認・却下された場合は上書きしない
+	updated, err := persistence.UpdatePaymentStatus(ctx, conn(ctx, r.db), paymentID, model.PaymentStatusPending, status, now)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	if !updated {
+		return nil, errors.WithStack(repository.ErrConflict)
+	}
+
+	p.Status = status
+	p.UpdatedAt = now
+
+	return r.toModel(p), nil
+}
+
+// findByID : 存在しない支払いと他のユーザーの支払いはrepository.ErrNotFoundを返す。FindByIDと同じく他のユーザーの支払いの存在を明かさない
+func (r *paymentPersistencePostgres) findByID(ctx context.Context, userID, paymentID int) (*persistence.Payment, error) {
+	p, err := persistence.PaymentByID(ctx, conn(ctx, r.db), paymentID)
+	if err == sql.ErrNoRows {
+		return nil, errors.WithStack(repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if p.UserID != userID {
+		return nil, errors.WithStack(repository.ErrNotFound)
+	}
+
+	return p, nil
+}
+
+func (r *paymentPersistencePostgres) FetchMonthlyCosts(ctx context.Context, userID int) ([]*model.MonthlyCategoryPayment, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
 
-	paymentsDate, err := persistence.SelectPaymentDateByUserID(r.db, userID)
+	monthlyPayments, err := persistence.SelectMonthlyCategoryPayments(ctx, conn(ctx, r.db), userID)
 
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
-	return paymentsDate, nil
+	return monthlyPayments, nil
+}
+
+func (r *paymentPersistencePostgres) CountDuplicates(ctx context.Context, mp *model.Payment) (int, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	p := &persistence.Payment{
+		UserID:      mp.UserID,
+		CategoryID:  mp.CategoryID,
+		PayerID:     mp.PayerID,
+		Description: mp.Description,
+		PaymentDate: mp.PaymentDate,
+		Payment:     mp.Payment,
+	}
+
+	n, err := persistence.CountSamePayments(ctx, conn(ctx, r.db), p)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return n, nil
 }
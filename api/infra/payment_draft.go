@@ -0,0 +1,114 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra/persistence"
+)
+
+func NewPaymentDraftRepository(db *sql.DB) *paymentDraftPersistencePostgres {
+	return &paymentDraftPersistencePostgres{
+		db: db,
+	}
+}
+
+var _ repository.PaymentDraftRepository = &paymentDraftPersistencePostgres{}
+
+type paymentDraftPersistencePostgres struct {
+	db *sql.DB
+}
+
+func (r *paymentDraftPersistencePostgres) GetData(ctx context.Context, userID int) ([]*model.PaymentDraft, error) {
+	drafts, err := persistence.SelectPaymentDrafts(ctx, conn(ctx, r.db), userID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return drafts, nil
+}
+
+func (r *paymentDraftPersistencePostgres) FindByID(ctx context.Context, userID, paymentDraftID int) (*model.PaymentDraft, error) {
+	d, err := r.findByID(ctx, userID, paymentDraftID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.toModel(d), nil
+}
+
+func (r *paymentDraftPersistencePostgres) Create(ctx context.Context, md *model.PaymentDraft) (*model.PaymentDraft, error) {
+	now := time.Now()
+
+	d := &persistence.PaymentDraft{
+		UserID:      md.UserID,
+		Source:      md.Source,
+		Description: md.Description,
+		PaymentDate: md.PaymentDate,
+		Payment:     md.Payment,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := d.Save(ctx, conn(ctx, r.db)); err != nil {
+		return nil, translateError(err)
+	}
+
+	return r.toModel(d), nil
+}
+
+func (r *paymentDraftPersistencePostgres) CountDuplicates(ctx context.Context, md *model.PaymentDraft) (int, error) {
+	n, err := persistence.CountSamePaymentDrafts(ctx, conn(ctx, r.db), md.UserID, md.PaymentDate, md.Payment, md.Description)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return n, nil
+}
+
+func (r *paymentDraftPersistencePostgres) DeleteByID(ctx context.Context, userID, paymentDraftID int) error {
+	d, err := r.findByID(ctx, userID, paymentDraftID)
+	if err != nil {
+		return err
+	}
+
+	if err := d.Delete(ctx, conn(ctx, r.db)); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (*paymentDraftPersistencePostgres) toModel(d *persistence.PaymentDraft) *model.PaymentDraft {
+	return &model.PaymentDraft{
+		ID:          d.ID,
+		UserID:      d.UserID,
+		Source:      d.Source,
+		Description: d.Description,
+		PaymentDate: d.PaymentDate,
+		Payment:     d.Payment,
+		CreatedAt:   d.CreatedAt,
+		UpdatedAt:   d.UpdatedAt,
+	}
+}
+
+// findByID : 存在しない下書きはrepository.ErrNotFound、他のユーザーの下書きはrepository.ErrForbiddenを返す
+func (r *paymentDraftPersistencePostgres) findByID(ctx context.Context, userID, paymentDraftID int) (*persistence.PaymentDraft, error) {
+	d, err := persistence.PaymentDraftByID(ctx, conn(ctx, r.db), paymentDraftID)
+	if err == sql.ErrNoRows {
+		return nil, errors.WithStack(repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if d.UserID != userID {
+		return nil, errors.WithStack(repository.ErrForbidden)
+	}
+
+	return d, nil
+}
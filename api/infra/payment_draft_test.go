@@ -0,0 +1,189 @@
+package infra_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra"
+	"github.com/warikan/db"
+	"github.com/warikan/test"
+)
+
+func TestPaymentDraftPersistencePostgres_GetData(t *testing.T) {
+	r := infra.NewPaymentDraftRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		userID int
+		want   []*model.PaymentDraft
+	}{
+		{
+			name:   "Success",
+			userID: 10001,
+			want: []*model.PaymentDraft{
+				{
+					ID:          39999,
+					UserID:      10001,
+					Source:      "ofx",
+					Description: sql.NullString{String: "スーパー", Valid: true},
+					PaymentDate: time.Date(2020, time.April, 3, 0, 0, 0, 0, time.UTC),
+					Payment:     1234,
+				},
+			},
+		},
+		{
+			name:   "Success no drafts",
+			userID: 10002,
+			want:   []*model.PaymentDraft{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.GetData(context.Background(), tt.userID)
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(model.PaymentDraft{}, "CreatedAt", "UpdatedAt"), cmp.Comparer(func(x, y time.Time) bool { return x.Equal(y) })); diff != "" {
+				t.Errorf("GetData() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPaymentDraftPersistencePostgres_Create(t *testing.T) {
+	r := infra.NewPaymentDraftRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	arg := &model.PaymentDraft{
+		UserID:      10001,
+		Source:      "qif",
+		PaymentDate: time.Date(2020, time.April, 5, 0, 0, 0, 0, time.UTC),
+		Payment:     500,
+	}
+	got, err := r.Create(context.Background(), arg)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+	if diff := cmp.Diff(arg, got, cmpopts.IgnoreFields(model.PaymentDraft{}, "ID", "CreatedAt", "UpdatedAt")); diff != "" {
+		t.Errorf("Create() mismatch (-want +got):\n%s", diff)
+	}
+
+	found, err := r.FindByID(context.Background(), 10001, got.ID)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+	if diff := cmp.Diff(got, found, cmpopts.IgnoreFields(model.PaymentDraft{}, "CreatedAt", "UpdatedAt"), cmp.Comparer(func(x, y time.Time) bool { return x.Equal(y) })); diff != "" {
+		t.Errorf("FindByID() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPaymentDraftPersistencePostgres_CountDuplicates(t *testing.T) {
+	r := infra.NewPaymentDraftRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		arg  *model.PaymentDraft
+		want int
+	}{
+		{
+			name: "Same JST date",
+			arg:  &model.PaymentDraft{UserID: 10001, Description: sql.NullString{String: "スーパー", Valid: true}, PaymentDate: time.Date(2020, time.April, 3, 11, 0, 0, 0, time.UTC), Payment: 1234},
+			want: 1,
+		},
+		{
+			name: "Different JST date",
+			arg:  &model.PaymentDraft{UserID: 10001, Description: sql.NullString{String: "スーパー", Valid: true}, PaymentDate: time.Date(2020, time.April, 2, 14, 0, 0, 0, time.UTC), Payment: 1234},
+		},
+		{
+			name: "Different description",
+			arg:  &model.PaymentDraft{UserID: 10001, PaymentDate: time.Date(2020, time.April, 3, 0, 0, 0, 0, time.UTC), Payment: 1234},
+		},
+		{
+			name: "Other user's draft",
+			arg:  &model.PaymentDraft{UserID: 10002, Description: sql.NullString{String: "スーパー", Valid: true}, PaymentDate: time.Date(2020, time.April, 3, 0, 0, 0, 0, time.UTC), Payment: 1234},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.CountDuplicates(context.Background(), tt.arg)
+			if err != nil {
+				t.Fatalf("err should be nil, but got %q", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("CountDuplicates() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPaymentDraftPersistencePostgres_DeleteByID(t *testing.T) {
+	r := infra.NewPaymentDraftRepository(db.Pool)
+
+	tests := []struct {
+		name           string
+		userID         int
+		paymentDraftID int
+		wantErr        error
+	}{
+		{
+			name:           "Success",
+			userID:         10001,
+			paymentDraftID: 39999,
+		},
+		{
+			name:           "Not found",
+			userID:         10001,
+			paymentDraftID: 99999,
+			wantErr:        repository.ErrNotFound,
+		},
+		{
+			name:           "Other user's draft",
+			userID:         10002,
+			paymentDraftID: 39999,
+			wantErr:        repository.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+				t.Fatal(err)
+			}
+
+			err := r.DeleteByID(context.Background(), tt.userID, tt.paymentDraftID)
+			if errors.Cause(err) != tt.wantErr {
+				t.Errorf("DeleteByID() unexpected error:\nwant: %v\ngot : %v", tt.wantErr, err)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+			// 削除した下書きは取得できない
+			if _, err := r.FindByID(context.Background(), tt.userID, tt.paymentDraftID); errors.Cause(err) != repository.ErrNotFound {
+				t.Errorf("FindByID() after delete unexpected error:\nwant: %v\ngot : %v", repository.ErrNotFound, err)
+			}
+		})
+	}
+}
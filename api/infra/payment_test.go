@@ -1,6 +1,7 @@
 package infra_test
 
 import (
+	"context"
 	"database/sql"
 	"reflect"
 	"testing"
@@ -8,15 +9,148 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
 	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
 	"github.com/warikan/api/infra"
 	"github.com/warikan/db"
 	"github.com/warikan/test"
 )
 
+func TestPaymentsPersistencePostgres_GetData(t *testing.T) {
+	r := infra.NewPaymentsRepository(db.Pool, 5*time.Second)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		filter  *model.PaymentFilter
+		cursor  *model.PaymentCursor
+		limit   int
+		wantIDs []int
+	}{
+		{
+			name:    "First page is ordered by payment date descending",
+			limit:   1,
+			wantIDs: []int{19998},
+		},
+		{
+			name: "Next page starts after cursor",
+			cursor: &model.PaymentCursor{
+				PaymentDate: time.Date(2020, time.April, 15, 0, 0, 0, 0, time.UTC),
+				ID:          19998,
+			},
+			limit:   1,
+			wantIDs: []int{19999},
+		},
+		{
+			name: "No more payments",
+			cursor: &model.PaymentCursor{
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				ID:          19999,
+			},
+			limit:   1,
+			wantIDs: []int{},
+		},
+		{
+			name: "Filter by month and payer",
+			filter: &model.PaymentFilter{
+				From:    time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				To:      time.Date(2020, time.May, 1, 0, 0, 0, 0, time.UTC),
+				PayerID: 2,
+			},
+			limit:   20,
+			wantIDs: []int{19998},
+		},
+		{
+			name: "Filter by amount range and description",
+			filter: &model.PaymentFilter{
+				MinPayment:  5000,
+				MaxPayment:  6000,
+				Description: "更新",
+			},
+			limit:   20,
+			wantIDs: []int{19999},
+		},
+		{
+			name: "Description wildcard is escaped",
+			filter: &model.PaymentFilter{
+				Description: "%",
+			},
+			limit:   20,
+			wantIDs: []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.GetData(context.Background(), 10001, tt.filter, tt.cursor, tt.limit)
+			if err != nil {
+				t.Fatalf("err should be nil, but got %q", err)
+			}
+
+			ids := make([]int, 0, len(got))
+			for _, p := range got {
+				ids = append(ids, p.ID)
+			}
+			if diff := cmp.Diff(tt.wantIDs, ids); diff != "" {
+				t.Errorf("GetData() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPaymentsPersistencePostgres_GetDataCanceled(t *testing.T) {
+	r := infra.NewPaymentsRepository(db.Pool, 5*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := r.GetData(ctx, 10001, nil, nil, 10); errors.Cause(err) != context.Canceled {
+		t.Errorf("GetData() should return context.Canceled, but got %v", err)
+	}
+}
+
+func TestPaymentsPersistencePostgres_FindByID(t *testing.T) {
+	r := infra.NewPaymentsRepository(db.Pool, 5*time.Second)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.FindByID(context.Background(), 10001, 19998)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+
+	want := &model.Payment{
+		ID:           19998,
+		UserID:       10001,
+		CategoryID:   1,
+		CategoryName: "家賃",
+		PayerID:      2,
+		PayerName:    "パートナーネーム",
+		Description:  sql.NullString{String: "精算", Valid: true},
+		PaymentDate:  time.Date(2020, time.April, 15, 0, 0, 0, 0, time.UTC),
+		Payment:      1111,
+		Status:       model.PaymentStatusApproved,
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(model.Payment{}, "CreatedAt", "UpdatedAt"), cmp.Comparer(func(x, y time.Time) bool { return x.Equal(y) })); diff != "" {
+		t.Errorf("FindByID() mismatch (-want +got):\n%s", diff)
+	}
+
+	// 他のユーザーの支払いは取得できない
+	if _, err := r.FindByID(context.Background(), 10002, 19998); errors.Cause(err) != repository.ErrNotFound {
+		t.Errorf("FindByID() unexpected error:\nwant: %v\ngot : %v", repository.ErrNotFound, err)
+	}
+}
+
 func TestPaymentsPersistencePostgres_Create(t *testing.T) {
 
-	r := infra.NewPaymentsRepository(db.Pool)
+	r := infra.NewPaymentsRepository(db.Pool, 5*time.Second)
 
 	now := time.Now()
 
@@ -49,6 +183,7 @@ func TestPaymentsPersistencePostgres_Create(t *testing.T) {
 				Description: sql.NullString{String: "作成", Valid: true},
 				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
 				Payment:     1234,
+				Status:      model.PaymentStatusApproved,
 				CreatedAt:   now,
 				UpdatedAt:   now,
 			},
@@ -59,7 +194,7 @@ func TestPaymentsPersistencePostgres_Create(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setup(t)
-			got, err := r.Create(tt.arg)
+			got, err := r.Create(context.Background(), tt.arg)
 			if tt.wantErr != nil {
 				if err == nil {
 					t.Error("expected error, but got nil")
@@ -85,8 +220,66 @@ func TestPaymentsPersistencePostgres_Create(t *testing.T) {
 	}
 }
 
+func TestPaymentsPersistencePostgres_CreateInvalidReference(t *testing.T) {
+	r := infra.NewPaymentsRepository(db.Pool, 5*time.Second)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		arg     *model.Payment
+		wantErr error
+	}{
+		{
+			name: "Unknown category",
+			arg: &model.Payment{
+				UserID:      10001,
+				CategoryID:  9999,
+				PayerID:     1,
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+			},
+			wantErr: repository.InvalidFieldError{Field: "category_id"},
+		},
+		{
+			name: "Other user's category",
+			arg: &model.Payment{
+				UserID:      10002,
+				CategoryID:  100,
+				PayerID:     1,
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+			},
+			wantErr: repository.InvalidFieldError{Field: "category_id"},
+		},
+		{
+			name: "Unknown payer",
+			arg: &model.Payment{
+				UserID:      10001,
+				CategoryID:  1,
+				PayerID:     9,
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+			},
+			wantErr: repository.InvalidFieldError{Field: "payer_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.Create(context.Background(), tt.arg)
+			if errors.Cause(err) != tt.wantErr {
+				t.Errorf("Create() unexpected error:\nwant: %v\ngot : %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestPaymentsPersistencePostgres_Update(t *testing.T) {
-	r := infra.NewPaymentsRepository(db.Pool)
+	r := infra.NewPaymentsRepository(db.Pool, 5*time.Second)
 	now := time.Now()
 
 	tests := []struct {
@@ -111,6 +304,7 @@ func TestPaymentsPersistencePostgres_Update(t *testing.T) {
 				Description: sql.NullString{String: "更新後", Valid: true},
 				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
 				Payment:     5555,
+				Status:      model.PaymentStatusApproved,
 			},
 			want: &model.Payment{
 				ID:          19999,
@@ -120,16 +314,49 @@ func TestPaymentsPersistencePostgres_Update(t *testing.T) {
 				Description: sql.NullString{String: "更新後", Valid: true},
 				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
 				Payment:     5555,
+				Status:      model.PaymentStatusApproved,
 				CreatedAt:   now,
 				UpdatedAt:   now,
 			},
 			wantErr: nil,
 		},
+		{
+			name: "Success pending with editor as creator",
+			setup: func(t *testing.T) {
+				if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			arg: &model.Payment{
+				ID:          19999,
+				UserID:      10001,
+				CategoryID:  1,
+				PayerID:     2,
+				Description: sql.NullString{String: "更新前", Valid: true},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     5555,
+				Status:      model.PaymentStatusPending,
+				CreatedBy:   sql.NullInt64{Int64: 10002, Valid: true},
+			},
+			want: &model.Payment{
+				ID:          19999,
+				UserID:      10001,
+				CategoryID:  1,
+				PayerID:     2,
+				Description: sql.NullString{String: "更新前", Valid: true},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     5555,
+				Status:      model.PaymentStatusPending,
+				CreatedBy:   sql.NullInt64{Int64: 10002, Valid: true},
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := r.Update(tt.arg)
+			tt.setup(t)
+
+			got, err := r.Update(context.Background(), tt.arg)
 			if tt.wantErr != nil {
 				if err == nil {
 					t.Error("expected error, but got nil")
@@ -155,51 +382,390 @@ func TestPaymentsPersistencePostgres_Update(t *testing.T) {
 	}
 }
 
-// func Test_PaymentRepository_DeleteByID(t *testing.T) {
-// 	r := repository.NewPaymentsRepository(testDB)
-// 	loadDefaultFixture(testDB, t)
-
-// 	tests := []struct {
-// 		name      string
-// 		userID    int
-// 		paymentID int
-// 		wantErr   error
-// 	}{
-// 		{
-// 			name:      "Success",
-// 			userID:    1,
-// 			paymentID: 1,
-// 			wantErr:   nil,
-// 		},
-// 	}
-
-// 	for _, tt := range tests {
-// 		t.Run(tt.name, func(t *testing.T) {
-// 			err := r.DeleteByID(tt.userID, tt.paymentID)
-// 			if tt.wantErr != nil {
-// 				if err == nil {
-// 					t.Error("expected error, but got nil")
-// 					return
-// 				}
-
-// 				err = errors.Cause(err)
-// 				if g, e := err.Error(), tt.wantErr.Error(); g != e {
-// 					t.Errorf("unexpected error:\nwant: %q\ngot : %q", e, g)
-// 					return
-// 				}
-// 				return
-// 			}
-// 		})
-// 	}
-// }
-
-// func loadDefaultFixture(db *sqlx.DB, t *testing.T) {
-// 	tx := db.MustBegin()
-// 	tx.MustExec(tx.Rebind("INSERT INTO users (user_name, partner_name, email, password, user_image, partner_image) VALUES (?, ?, ?, ?, ?, ?)"), "ユーザー", "パートナー", "example@co.jp", "password", "user_image", "partner_image")
-// 	tx.MustExec(tx.Rebind("INSERT INTO categories (name) VALUES (?)"), "家賃")
-// 	tx.MustExec(tx.Rebind("INSERT INTO categories (name) VALUES (?)"), "ガス代")
-// 	tx.MustExec(tx.Rebind("INSERT INTO payers (name) VALUES (?)"), "ユーザー")
-// 	tx.MustExec(tx.Rebind("INSERT INTO payers (name) VALUES (?)"), "パートナー")
-// 	tx.MustExec(tx.Rebind("INSERT INTO payments (user_id, category_id, payer_id, payment_date, payment) VALUES (?, ?, ?, ?, ?)"), 1, 1, 1, "2020-04-01T00:00:00+09:00", 1234)
-// 	_ = tx.Commit()
-// }
+func TestPaymentsPersistencePostgres_FetchMonthlyCosts(t *testing.T) {
+	r := infra.NewPaymentsRepository(db.Pool, 5*time.Second)
+
+	tests := []struct {
+		name   string
+		userID int
+		want   []*model.MonthlyCategoryPayment
+	}{
+		{
+			name:   "Success",
+			userID: 10001,
+			want: []*model.MonthlyCategoryPayment{
+				{YearMonth: "2020-04", CategoryID: 1, CategoryName: "家賃", PayerID: 1, Payment: 5555, Count: 1},
+				{YearMonth: "2020-04", CategoryID: 1, CategoryName: "家賃", PayerID: 2, Payment: 1111, Count: 1},
+			},
+		},
+		{
+			name:   "Success no payments",
+			userID: 99999,
+			want:   []*model.MonthlyCategoryPayment{},
+		},
+		{
+			name:   "Pending payments are excluded",
+			userID: 10002,
+			want:   []*model.MonthlyCategoryPayment{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := r.FetchMonthlyCosts(context.Background(), tt.userID)
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("FetchMonthlyCosts() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPaymentsPersistencePostgres_CountDuplicates(t *testing.T) {
+	r := infra.NewPaymentsRepository(db.Pool, 5*time.Second)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	payment := func(f func(p *model.Payment)) *model.Payment {
+		p := &model.Payment{
+			UserID:      10001,
+			CategoryID:  1,
+			PayerID:     2,
+			Description: sql.NullString{String: "精算", Valid: true},
+			PaymentDate: time.Date(2020, time.April, 15, 0, 0, 0, 0, time.UTC),
+			Payment:     1111,
+		}
+		f(p)
+		return p
+	}
+
+	tests := []struct {
+		name string
+		arg  *model.Payment
+		want int
+	}{
+		{
+			name: "Same payment",
+			arg:  payment(func(p *model.Payment) {}),
+			want: 1,
+		},
+		{
+			name: "Same JST date at another time",
+			arg:  payment(func(p *model.Payment) { p.PaymentDate = time.Date(2020, time.April, 14, 15, 0, 0, 0, time.UTC) }),
+			want: 1,
+		},
+		{
+			name: "Different JST date",
+			arg:  payment(func(p *model.Payment) { p.PaymentDate = time.Date(2020, time.April, 15, 15, 0, 0, 0, time.UTC) }),
+			want: 0,
+		},
+		{
+			name: "Different amount",
+			arg:  payment(func(p *model.Payment) { p.Payment = 1112 }),
+			want: 0,
+		},
+		{
+			name: "Different description",
+			arg:  payment(func(p *model.Payment) { p.Description = sql.NullString{} }),
+			want: 0,
+		},
+		{
+			name: "Other user's payment",
+			arg:  payment(func(p *model.Payment) { p.UserID = 10002 }),
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.CountDuplicates(context.Background(), tt.arg)
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CountDuplicates() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaymentsPersistencePostgres_DeleteByID(t *testing.T) {
+	r := infra.NewPaymentsRepository(db.Pool, 5*time.Second)
+
+	tests := []struct {
+		name      string
+		userID    int
+		paymentID int
+		wantErr   error
+	}{
+		{
+			name:      "Success",
+			userID:    10001,
+			paymentID: 19999,
+		},
+		{
+			name:      "Not found",
+			userID:    10001,
+			paymentID: 99999,
+			wantErr:   repository.ErrNotFound,
+		},
+		{
+			name:      "Other user's payment",
+			userID:    10002,
+			paymentID: 19999,
+			wantErr:   repository.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+				t.Fatal(err)
+			}
+
+			err := r.DeleteByID(context.Background(), tt.userID, tt.paymentID, time.Time{})
+			if errors.Cause(err) != tt.wantErr {
+				t.Errorf("DeleteByID() unexpected error:\nwant: %v\ngot : %v", tt.wantErr, err)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+			// 削除した支払いは取得できない
+			if _, err := r.FindByID(context.Background(), tt.userID, tt.paymentID); errors.Cause(err) != repository.ErrNotFound {
+				t.Errorf("FindByID() after delete unexpected error:\nwant: %v\ngot : %v", repository.ErrNotFound, err)
+			}
+		})
+	}
+}
+
+func TestPaymentsPersistencePostgres_UpdateOtherUser(t *testing.T) {
+	r := infra.NewPaymentsRepository(db.Pool, 5*time.Second)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		arg     *model.Payment
+		wantErr error
+	}{
+		{
+			name: "Not found",
+			arg: &model.Payment{
+				ID:          99999,
+				UserID:      10001,
+				CategoryID:  1,
+				PayerID:     1,
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1,
+			},
+			wantErr: repository.ErrNotFound,
+		},
+		{
+			name: "Other user's payment",
+			arg: &model.Payment{
+				ID:          19999,
+				UserID:      10002,
+				CategoryID:  1,
+				PayerID:     1,
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1,
+			},
+			wantErr: repository.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := r.Update(context.Background(), tt.arg); errors.Cause(err) != tt.wantErr {
+				t.Errorf("Update() unexpected error:\nwant: %v\ngot : %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// 他のユーザーからの更新は反映されていない
+	got, err := r.FindByID(context.Background(), 10001, 19999)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+	if got.Payment != 5555 {
+		t.Errorf("payment was updated by other user: %d", got.Payment)
+	}
+}
+
+func TestPaymentsPersistencePostgres_UpdateConflict(t *testing.T) {
+	r := infra.NewPaymentsRepository(db.Pool, 5*time.Second)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := r.FindByID(context.Background(), 10001, 19998)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	arg := &model.Payment{
+		ID:          19998,
+		UserID:      10001,
+		CategoryID:  current.CategoryID,
+		PayerID:     current.PayerID,
+		PaymentDate: current.PaymentDate,
+		Payment:     current.Payment + 1,
+		UpdatedAt:   current.UpdatedAt,
+	}
+
+	// 取得時のupdated_atを指定した更新は成功し、返したupdated_atは保存後の値と一致する
+	updated, err := r.Update(context.Background(), arg)
+	if err != nil {
+		t.Fatalf("Update() err should be nil, but got %q", err)
+	}
+	got, err := r.FindByID(context.Background(), 10001, 19998)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.UpdatedAt.Equal(updated.UpdatedAt) {
+		t.Errorf("Update() updated_at mismatch:\nwant: %v\ngot : %v", got.UpdatedAt, updated.UpdatedAt)
+	}
+	// 金額を増やした承認済みの支払いは承認待ちに戻る
+	if diff := cmp.Diff(model.PaymentStatusPending, got.Status); diff != "" {
+		t.Errorf("Update() mismatch status (-want +got):\n%s", diff)
+	}
+
+	// 古いupdated_atでの更新は競合する
+	if _, err := r.Update(context.Background(), arg); errors.Cause(err) != repository.ErrConflict {
+		t.Errorf("Update() unexpected error:\nwant: %v\ngot : %v", repository.ErrConflict, err)
+	}
+}
+
+func TestPaymentsPersistencePostgres_UpdateStatus(t *testing.T) {
+	r := infra.NewPaymentsRepository(db.Pool, 5*time.Second)
+
+	tests := []struct {
+		name       string
+		userID     int
+		reviewerID int
+		paymentID  int
+		status     string
+		wantErr    error
+	}{
+		{
+			name:       "Approve",
+			userID:     10002,
+			reviewerID: 10001,
+			paymentID:  19997,
+			status:     model.PaymentStatusApproved,
+		},
+		{
+			name:       "Reject",
+			userID:     10002,
+			reviewerID: 10001,
+			paymentID:  19997,
+			status:     model.PaymentStatusRejected,
+		},
+		{
+			name:       "Already approved",
+			userID:     10001,
+			reviewerID: 10002,
+			paymentID:  19999,
+			status:     model.PaymentStatusRejected,
+			wantErr:    repository.ErrConflict,
+		},
+		{
+			name:       "Not found",
+			userID:     10002,
+			reviewerID: 10001,
+			paymentID:  99999,
+			status:     model.PaymentStatusApproved,
+			wantErr:    repository.ErrNotFound,
+		},
+		{
+			name:       "Other user's payment",
+			userID:     10001,
+			reviewerID: 10001,
+			paymentID:  19997,
+			status:     model.PaymentStatusApproved,
+			wantErr:    repository.ErrNotFound,
+		},
+		{
+			name:       "Own payment",
+			userID:     10002,
+			reviewerID: 10002,
+			paymentID:  19997,
+			status:     model.PaymentStatusApproved,
+			wantErr:    repository.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := r.UpdateStatus(context.Background(), tt.userID, tt.reviewerID, tt.paymentID, tt.status)
+			if errors.Cause(err) != tt.wantErr {
+				t.Fatalf("UpdateStatus() unexpected error:\nwant: %v\ngot : %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			// 返したupdated_atは保存後の値と一致する
+			saved, err := r.FindByID(context.Background(), tt.userID, tt.paymentID)
+			if err != nil {
+				t.Fatalf("err should be nil, but got %q", err)
+			}
+			if diff := cmp.Diff(tt.status, saved.Status); diff != "" {
+				t.Errorf("UpdateStatus() mismatch status (-want +got):\n%s", diff)
+			}
+			if !got.UpdatedAt.Equal(saved.UpdatedAt) {
+				t.Errorf("UpdateStatus() mismatch updated_at:\nwant: %v\ngot : %v", saved.UpdatedAt, got.UpdatedAt)
+			}
+		})
+	}
+}
+
+func TestPaymentsPersistencePostgres_DeleteByIDConflict(t *testing.T) {
+	r := infra.NewPaymentsRepository(db.Pool, 5*time.Second)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := r.FindByID(context.Background(), 10001, 19998)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 古いupdated_atでの削除は競合し、支払いは残る
+	stale := current.UpdatedAt.Add(-time.Second)
+	if err := r.DeleteByID(context.Background(), 10001, 19998, stale); errors.Cause(err) != repository.ErrConflict {
+		t.Errorf("DeleteByID() unexpected error:\nwant: %v\ngot : %v", repository.ErrConflict, err)
+	}
+	if _, err := r.FindByID(context.Background(), 10001, 19998); err != nil {
+		t.Fatalf("FindByID() after conflict err should be nil, but got %q", err)
+	}
+
+	// 取得時のupdated_atを指定した削除は成功する
+	if err := r.DeleteByID(context.Background(), 10001, 19998, current.UpdatedAt); err != nil {
+		t.Fatalf("DeleteByID() err should be nil, but got %q", err)
+	}
+	if _, err := r.FindByID(context.Background(), 10001, 19998); errors.Cause(err) != repository.ErrNotFound {
+		t.Errorf("FindByID() after delete unexpected error:\nwant: %v\ngot : %v", repository.ErrNotFound, err)
+	}
+}
@@ -0,0 +1,92 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/warikan/api/domain/model"
+)
+
+// SelectCategories : 共通カテゴリとユーザーのカテゴリを共通カテゴリから順に返す
+func SelectCategories(db XODB, userID int) ([]*model.Category, error) {
+	var err error
+
+	// sql query
+	var sqlstr = `SELECT c.id
+		, c.user_id
+		, c.name
+		, c.archived_at IS NOT NULL AS archived
+		, c.created_at
+		, c.updated_at
+		FROM categories c
+		WHERE c.user_id IS NULL
+		OR c.user_id = $1
+		ORDER BY c.user_id NULLS FIRST, c.id`
+
+	// run query
+	XOLog(sqlstr, userID)
+	q, err := db.Query(sqlstr, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer q.Close()
+
+	categories := make([]*model.Category, 0)
+	for q.Next() {
+		var c model.Category
+		err := q.Scan(
+			&c.ID,
+			&c.UserID,
+			&c.Name,
+			&c.Archived,
+			&c.CreatedAt,
+			&c.UpdatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, &c)
+	}
+
+	return categories, nil
+}
+
+// ExistsCategoryReference : カテゴリがuserIDのユーザーの支払いまたは固定費から参照されているかどうかを返す
+func ExistsCategoryReference(db XODB, userID, categoryID int) (bool, error) {
+	var err error
+
+	// sql query
+	const sqlstr = `SELECT EXISTS (SELECT 1 FROM payments WHERE category_id = $1 AND user_id = $2)
+		OR EXISTS (SELECT 1 FROM fixed_costs WHERE category_id = $1 AND user_id = $2)`
+
+	// run query
+	XOLog(sqlstr, categoryID, userID)
+	var exists bool
+	err = db.QueryRow(sqlstr, categoryID, userID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// IsCategoryAvailable : カテゴリが共通カテゴリかuserIDのユーザーのカテゴリの場合にtrueを返す
+func IsCategoryAvailable(ctx context.Context, db XODB, userID, categoryID int) (bool, error) {
+	var err error
+
+	// sql query
+	const sqlstr = `SELECT EXISTS (SELECT 1 FROM categories
+		WHERE id = $1
+		AND (user_id IS NULL OR user_id = $2))`
+
+	// run query
+	XOLog(sqlstr, categoryID, userID)
+	var available bool
+	err = db.QueryRowContext(ctx, sqlstr, categoryID, userID).Scan(&available)
+	if err != nil {
+		return false, err
+	}
+
+	return available, nil
+}
@@ -4,16 +4,21 @@ package persistence
 // Code generated by xo. DO NOT EDIT.
 
 import (
+	"database/sql"
 	"errors"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // Category represents a row from 'public.categories'.
 type Category struct {
-	ID        int       `json:"id"`         // id
-	Name      string    `json:"name"`       // name
-	CreatedAt time.Time `json:"created_at"` // created_at
-	UpdatedAt time.Time `json:"updated_at"` // updated_at
+	ID         int           `json:"id"`          // id
+	Name       string        `json:"name"`        // name
+	CreatedAt  time.Time     `json:"created_at"`  // created_at
+	UpdatedAt  time.Time     `json:"updated_at"`  // updated_at
+	UserID     sql.NullInt64 `json:"user_id"`     // user_id
+	ArchivedAt pq.NullTime   `json:"archived_at"` // archived_at
 
 	// xo fields
 	_exists, _deleted bool
@@ -40,14 +45,14 @@ func (c *Category) Insert(db XODB) error {
 
 	// sql insert query, primary key provided by sequence
 	const sqlstr = `INSERT INTO public.categories (` +
-		`name, created_at, updated_at` +
+		`name, created_at, updated_at, user_id, archived_at` +
 		`) VALUES (` +
-		`$1, $2, $3` +
+		`$1, $2, $3, $4, $5` +
 		`) RETURNING id`
 
 	// run query
-	XOLog(sqlstr, c.Name, c.CreatedAt, c.UpdatedAt)
-	err = db.QueryRow(sqlstr, c.Name, c.CreatedAt, c.UpdatedAt).Scan(&c.ID)
+	XOLog(sqlstr, c.Name, c.CreatedAt, c.UpdatedAt, c.UserID, c.ArchivedAt)
+	err = db.QueryRow(sqlstr, c.Name, c.CreatedAt, c.UpdatedAt, c.UserID, c.ArchivedAt).Scan(&c.ID)
 	if err != nil {
 		return err
 	}
@@ -74,14 +79,14 @@ func (c *Category) Update(db XODB) error {
 
 	// sql query
 	const sqlstr = `UPDATE public.categories SET (` +
-		`name, created_at, updated_at` +
+		`name, created_at, updated_at, user_id, archived_at` +
 		`) = ( ` +
-		`$1, $2, $3` +
-		`) WHERE id = $4`
+		`$1, $2, $3, $4, $5` +
+		`) WHERE id = $6`
 
 	// run query
-	XOLog(sqlstr, c.Name, c.CreatedAt, c.UpdatedAt, c.ID)
-	_, err = db.Exec(sqlstr, c.Name, c.CreatedAt, c.UpdatedAt, c.ID)
+	XOLog(sqlstr, c.Name, c.CreatedAt, c.UpdatedAt, c.UserID, c.ArchivedAt, c.ID)
+	_, err = db.Exec(sqlstr, c.Name, c.CreatedAt, c.UpdatedAt, c.UserID, c.ArchivedAt, c.ID)
 	return err
 }
 
@@ -107,18 +112,18 @@ func (c *Category) Upsert(db XODB) error {
 
 	// sql query
 	const sqlstr = `INSERT INTO public.categories (` +
-		`id, name, created_at, updated_at` +
+		`id, name, created_at, updated_at, user_id, archived_at` +
 		`) VALUES (` +
-		`$1, $2, $3, $4` +
+		`$1, $2, $3, $4, $5, $6` +
 		`) ON CONFLICT (id) DO UPDATE SET (` +
-		`id, name, created_at, updated_at` +
+		`id, name, created_at, updated_at, user_id, archived_at` +
 		`) = (` +
-		`EXCLUDED.id, EXCLUDED.name, EXCLUDED.created_at, EXCLUDED.updated_at` +
+		`EXCLUDED.id, EXCLUDED.name, EXCLUDED.created_at, EXCLUDED.updated_at, EXCLUDED.user_id, EXCLUDED.archived_at` +
 		`)`
 
 	// run query
-	XOLog(sqlstr, c.ID, c.Name, c.CreatedAt, c.UpdatedAt)
-	_, err = db.Exec(sqlstr, c.ID, c.Name, c.CreatedAt, c.UpdatedAt)
+	XOLog(sqlstr, c.ID, c.Name, c.CreatedAt, c.UpdatedAt, c.UserID, c.ArchivedAt)
+	_, err = db.Exec(sqlstr, c.ID, c.Name, c.CreatedAt, c.UpdatedAt, c.UserID, c.ArchivedAt)
 	if err != nil {
 		return err
 	}
@@ -159,6 +164,13 @@ func (c *Category) Delete(db XODB) error {
 	return nil
 }
 
+// User returns the User associated with the Category's UserID (user_id).
+//
+// Generated from foreign key 'categories_user_id_fkey'.
+func (c *Category) User(db XODB) (*User, error) {
+	return UserByID(db, int(c.UserID.Int64))
+}
+
 // CategoryByID retrieves a row from 'public.categories' as a Category.
 //
 // Generated from index 'categories_pkey'.
@@ -167,7 +179,7 @@ func CategoryByID(db XODB, id int) (*Category, error) {
 
 	// sql query
 	const sqlstr = `SELECT ` +
-		`id, name, created_at, updated_at ` +
+		`id, name, created_at, updated_at, user_id, archived_at ` +
 		`FROM public.categories ` +
 		`WHERE id = $1`
 
@@ -177,10 +189,49 @@ func CategoryByID(db XODB, id int) (*Category, error) {
 		_exists: true,
 	}
 
-	err = db.QueryRow(sqlstr, id).Scan(&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt)
+	err = db.QueryRow(sqlstr, id).Scan(&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt, &c.UserID, &c.ArchivedAt)
 	if err != nil {
 		return nil, err
 	}
 
 	return &c, nil
 }
+
+// CategoriesByUserID retrieves a row from 'public.categories' as a Category.
+//
+// Generated from index 'categories_user_id_idx'.
+func CategoriesByUserID(db XODB, userID sql.NullInt64) ([]*Category, error) {
+	var err error
+
+	// sql query
+	const sqlstr = `SELECT ` +
+		`id, name, created_at, updated_at, user_id, archived_at ` +
+		`FROM public.categories ` +
+		`WHERE user_id = $1`
+
+	// run query
+	XOLog(sqlstr, userID)
+	q, err := db.Query(sqlstr, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer q.Close()
+
+	// load results
+	res := []*Category{}
+	for q.Next() {
+		c := Category{
+			_exists: true,
+		}
+
+		// scan
+		err = q.Scan(&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt, &c.UserID, &c.ArchivedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, &c)
+	}
+
+	return res, nil
+}
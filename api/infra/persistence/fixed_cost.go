@@ -0,0 +1,208 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/warikan/api/domain/model"
+)
+
+func SelectFixedCosts(ctx context.Context, db XODB, userID int) ([]*model.FixedCost, error) {
+	var err error
+
+	// sql query
+	var sqlstr = `SELECT f.id
+		, f.user_id
+		, f.category_id
+		, c.name AS category_name
+		, f.payer_id
+		, ` + payerNameColumn + ` AS payer_name
+		, f.description
+		, f.payment_date
+		, f.payment
+		, f.created_at
+		, f.updated_at
+		FROM fixed_costs f
+		INNER JOIN users u
+		ON f.user_id = u.id
+		LEFT JOIN payers a
+		ON f.payer_id = a.id
+		LEFT JOIN categories c
+		ON f.category_id = c.id
+		WHERE f.user_id = $1
+		AND u.deleted_at IS NULL
+		ORDER BY f.payment_date, f.id`
+
+	// run query
+	XOLog(sqlstr, userID)
+	q, err := db.QueryContext(ctx, sqlstr, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer q.Close()
+
+	fixedCosts := make([]*model.FixedCost, 0)
+	for q.Next() {
+		var f model.FixedCost
+		err := q.Scan(
+			&f.ID,
+			&f.UserID,
+			&f.CategoryID,
+			&f.CategoryName,
+			&f.PayerID,
+			&f.PayerName,
+			&f.Description,
+			&f.PaymentDate,
+			&f.Payment,
+			&f.CreatedAt,
+			&f.UpdatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+		fixedCosts = append(fixedCosts, &f)
+	}
+
+	return fixedCosts, nil
+}
+
+func SelectAllFixedCosts(ctx context.Context, db XODB) ([]*model.FixedCost, error) {
+	var err error
+
+	// sql query
+	var sqlstr = `SELECT f.id
+		, f.user_id
+		, f.category_id
+		, f.payer_id
+		, f.description
+		, f.payment_date
+		, f.payment
+		, f.created_at
+		, f.updated_at
+		FROM fixed_costs f
+		INNER JOIN users u
+		ON f.user_id = u.id
+		WHERE u.deleted_at IS NULL
+		ORDER BY f.id`
+
+	// run query
+	XOLog(sqlstr)
+	q, err := db.QueryContext(ctx, sqlstr)
+	if err != nil {
+		return nil, err
+	}
+
+	defer q.Close()
+
+	fixedCosts := make([]*model.FixedCost, 0)
+	for q.Next() {
+		var f model.FixedCost
+		err := q.Scan(
+			&f.ID,
+			&f.UserID,
+			&f.CategoryID,
+			&f.PayerID,
+			&f.Description,
+			&f.PaymentDate,
+			&f.Payment,
+			&f.CreatedAt,
+			&f.UpdatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+		fixedCosts = append(fixedCosts, &f)
+	}
+
+	return fixedCosts, nil
+}
+
+// InsertPaymentFromFixedCost : 同じ固定費から[from, to)の期間に支払いが作成済みの場合は何もせずfalseを返す
+func InsertPaymentFromFixedCost(ctx context.Context, db XODB, p *Payment, from, to time.Time) (bool, error) {
+	var err error
+
+	// sql query
+	const sqlstr = `INSERT INTO payments (
+		user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at, fixed_cost_id
+		)
+		SELECT $1::integer, $2::integer, $3::integer, $4::text, $5::timestamptz, $6::integer, $7::timestamptz, $8::timestamptz, $9::integer
+		WHERE NOT EXISTS (
+			SELECT 1 FROM payments
+			WHERE fixed_cost_id = $9
+			AND payment_date >= $10
+			AND payment_date < $11
+		)
+		ON CONFLICT DO NOTHING
+		RETURNING id`
+
+	// run query
+	XOLog(sqlstr, p.UserID, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.CreatedAt, p.UpdatedAt, p.FixedCostID, from, to)
+	err = db.QueryRowContext(ctx, sqlstr, p.UserID, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.CreatedAt, p.UpdatedAt, p.FixedCostID, from, to).Scan(&p.ID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	p._exists = true
+
+	return true, nil
+}
+
+// UpdatePaymentsByFixedCostID : 固定費の変更をfrom以降の支払いに反映する。手で変更した支払いも上書きする。
+// 支払日は同じ月(JST)のまま、日付を固定費の支払日の日付に合わせる。月末を超える場合は月末日とする。
+// 承認待ちでない支払いのカテゴリー・支払者・支払日・金額が変わる場合は承認待ちに戻し、登録したユーザーをupdatedByにする
+func UpdatePaymentsByFixedCostID(ctx context.Context, db XODB, f *FixedCost, from time.Time, updatedBy sql.NullInt64) (int, error) {
+	// sql query
+	const sqlstr = `UPDATE payments p
+		SET category_id = $1
+		, payer_id = $2
+		, description = $3
+		, payment = $4
+		, payment_date = s.payment_date
+		, status = CASE WHEN s.repend THEN '` + model.PaymentStatusPending + `' ELSE p.status END
+		, created_by = CASE WHEN s.repend THEN $6::integer ELSE p.created_by END
+		, updated_at = $7
+		FROM (
+			SELECT t.id
+			, t.payment_date
+			, t.status <> '` + model.PaymentStatusPending + `'
+				AND (t.category_id, t.payer_id, t.payment, t.old_payment_date) IS DISTINCT FROM ($1::integer, $2::integer, $4::integer, t.payment_date) AS repend
+			FROM (
+				SELECT id
+				, category_id
+				, payer_id
+				, payment
+				, status
+				, payment_date AS old_payment_date
+				, (date_trunc('month', payment_date AT TIME ZONE 'Asia/Tokyo')
+					+ make_interval(days => LEAST(
+						EXTRACT(DAY FROM $5::timestamptz AT TIME ZONE 'Asia/Tokyo'),
+						EXTRACT(DAY FROM date_trunc('month', payment_date AT TIME ZONE 'Asia/Tokyo') + INTERVAL '1 month - 1 day')
+					)::integer - 1)) AT TIME ZONE 'Asia/Tokyo' AS payment_date
+				FROM payments
+				WHERE fixed_cost_id = $8
+				AND payment_date >= $9
+			) t
+		) s
+		WHERE p.id = s.id`
+
+	// run query
+	XOLog(sqlstr, f.CategoryID, f.PayerID, f.Description, f.Payment, f.PaymentDate, updatedBy, f.UpdatedAt, f.ID, from)
+	res, err := db.ExecContext(ctx, sqlstr, f.CategoryID, f.PayerID, f.Description, f.Payment, f.PaymentDate, updatedBy, f.UpdatedAt, f.ID, from)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
@@ -4,6 +4,7 @@ package persistence
 // Code generated by xo. DO NOT EDIT.
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"time"
@@ -36,7 +37,7 @@ func (fc *FixedCost) Deleted() bool {
 }
 
 // Insert inserts the FixedCost to the database.
-func (fc *FixedCost) Insert(db XODB) error {
+func (fc *FixedCost) Insert(ctx context.Context, db XODB) error {
 	var err error
 
 	// if already exist, bail
@@ -53,7 +54,7 @@ func (fc *FixedCost) Insert(db XODB) error {
 
 	// run query
 	XOLog(sqlstr, fc.UserID, fc.CategoryID, fc.PayerID, fc.Description, fc.PaymentDate, fc.Payment, fc.CreatedAt, fc.UpdatedAt)
-	err = db.QueryRow(sqlstr, fc.UserID, fc.CategoryID, fc.PayerID, fc.Description, fc.PaymentDate, fc.Payment, fc.CreatedAt, fc.UpdatedAt).Scan(&fc.ID)
+	err = db.QueryRowContext(ctx, sqlstr, fc.UserID, fc.CategoryID, fc.PayerID, fc.Description, fc.PaymentDate, fc.Payment, fc.CreatedAt, fc.UpdatedAt).Scan(&fc.ID)
 	if err != nil {
 		return err
 	}
@@ -65,7 +66,7 @@ func (fc *FixedCost) Insert(db XODB) error {
 }
 
 // Update updates the FixedCost in the database.
-func (fc *FixedCost) Update(db XODB) error {
+func (fc *FixedCost) Update(ctx context.Context, db XODB) error {
 	var err error
 
 	// if doesn't exist, bail
@@ -87,23 +88,23 @@ func (fc *FixedCost) Update(db XODB) error {
 
 	// run query
 	XOLog(sqlstr, fc.UserID, fc.CategoryID, fc.PayerID, fc.Description, fc.PaymentDate, fc.Payment, fc.CreatedAt, fc.UpdatedAt, fc.ID)
-	_, err = db.Exec(sqlstr, fc.UserID, fc.CategoryID, fc.PayerID, fc.Description, fc.PaymentDate, fc.Payment, fc.CreatedAt, fc.UpdatedAt, fc.ID)
+	_, err = db.ExecContext(ctx, sqlstr, fc.UserID, fc.CategoryID, fc.PayerID, fc.Description, fc.PaymentDate, fc.Payment, fc.CreatedAt, fc.UpdatedAt, fc.ID)
 	return err
 }
 
 // Save saves the FixedCost to the database.
-func (fc *FixedCost) Save(db XODB) error {
+func (fc *FixedCost) Save(ctx context.Context, db XODB) error {
 	if fc.Exists() {
-		return fc.Update(db)
+		return fc.Update(ctx, db)
 	}
 
-	return fc.Insert(db)
+	return fc.Insert(ctx, db)
 }
 
 // Upsert performs an upsert for FixedCost.
 //
 // NOTE: PostgreSQL 9.5+ only
-func (fc *FixedCost) Upsert(db XODB) error {
+func (fc *FixedCost) Upsert(ctx context.Context, db XODB) error {
 	var err error
 
 	// if already exist, bail
@@ -124,7 +125,7 @@ func (fc *FixedCost) Upsert(db XODB) error {
 
 	// run query
 	XOLog(sqlstr, fc.ID, fc.UserID, fc.CategoryID, fc.PayerID, fc.Description, fc.PaymentDate, fc.Payment, fc.CreatedAt, fc.UpdatedAt)
-	_, err = db.Exec(sqlstr, fc.ID, fc.UserID, fc.CategoryID, fc.PayerID, fc.Description, fc.PaymentDate, fc.Payment, fc.CreatedAt, fc.UpdatedAt)
+	_, err = db.ExecContext(ctx, sqlstr, fc.ID, fc.UserID, fc.CategoryID, fc.PayerID, fc.Description, fc.PaymentDate, fc.Payment, fc.CreatedAt, fc.UpdatedAt)
 	if err != nil {
 		return err
 	}
@@ -136,7 +137,7 @@ func (fc *FixedCost) Upsert(db XODB) error {
 }
 
 // Delete deletes the FixedCost from the database.
-func (fc *FixedCost) Delete(db XODB) error {
+func (fc *FixedCost) Delete(ctx context.Context, db XODB) error {
 	var err error
 
 	// if doesn't exist, bail
@@ -154,7 +155,7 @@ func (fc *FixedCost) Delete(db XODB) error {
 
 	// run query
 	XOLog(sqlstr, fc.ID)
-	_, err = db.Exec(sqlstr, fc.ID)
+	_, err = db.ExecContext(ctx, sqlstr, fc.ID)
 	if err != nil {
 		return err
 	}
@@ -189,7 +190,7 @@ func (fc *FixedCost) User(db XODB) (*User, error) {
 // FixedCostsByCategoryID retrieves a row from 'public.fixed_costs' as a FixedCost.
 //
 // Generated from index 'fixed_costs_category_id_idx'.
-func FixedCostsByCategoryID(db XODB, categoryID int) ([]*FixedCost, error) {
+func FixedCostsByCategoryID(ctx context.Context, db XODB, categoryID int) ([]*FixedCost, error) {
 	var err error
 
 	// sql query
@@ -200,7 +201,7 @@ func FixedCostsByCategoryID(db XODB, categoryID int) ([]*FixedCost, error) {
 
 	// run query
 	XOLog(sqlstr, categoryID)
-	q, err := db.Query(sqlstr, categoryID)
+	q, err := db.QueryContext(ctx, sqlstr, categoryID)
 	if err != nil {
 		return nil, err
 	}
@@ -228,7 +229,7 @@ func FixedCostsByCategoryID(db XODB, categoryID int) ([]*FixedCost, error) {
 // FixedCostsByPayerID retrieves a row from 'public.fixed_costs' as a FixedCost.
 //
 // Generated from index 'fixed_costs_payer_id_idx'.
-func FixedCostsByPayerID(db XODB, payerID int) ([]*FixedCost, error) {
+func FixedCostsByPayerID(ctx context.Context, db XODB, payerID int) ([]*FixedCost, error) {
 	var err error
 
 	// sql query
@@ -239,7 +240,7 @@ func FixedCostsByPayerID(db XODB, payerID int) ([]*FixedCost, error) {
 
 	// run query
 	XOLog(sqlstr, payerID)
-	q, err := db.Query(sqlstr, payerID)
+	q, err := db.QueryContext(ctx, sqlstr, payerID)
 	if err != nil {
 		return nil, err
 	}
@@ -267,7 +268,7 @@ func FixedCostsByPayerID(db XODB, payerID int) ([]*FixedCost, error) {
 // FixedCostByID retrieves a row from 'public.fixed_costs' as a FixedCost.
 //
 // Generated from index 'fixed_costs_pkey'.
-func FixedCostByID(db XODB, id int) (*FixedCost, error) {
+func FixedCostByID(ctx context.Context, db XODB, id int) (*FixedCost, error) {
 	var err error
 
 	// sql query
@@ -282,7 +283,7 @@ func FixedCostByID(db XODB, id int) (*FixedCost, error) {
 		_exists: true,
 	}
 
-	err = db.QueryRow(sqlstr, id).Scan(&fc.ID, &fc.UserID, &fc.CategoryID, &fc.PayerID, &fc.Description, &fc.PaymentDate, &fc.Payment, &fc.CreatedAt, &fc.UpdatedAt)
+	err = db.QueryRowContext(ctx, sqlstr, id).Scan(&fc.ID, &fc.UserID, &fc.CategoryID, &fc.PayerID, &fc.Description, &fc.PaymentDate, &fc.Payment, &fc.CreatedAt, &fc.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -293,7 +294,7 @@ func FixedCostByID(db XODB, id int) (*FixedCost, error) {
 // FixedCostsByUserID retrieves a row from 'public.fixed_costs' as a FixedCost.
 //
 // Generated from index 'fixed_costs_user_id_idx'.
-func FixedCostsByUserID(db XODB, userID int) ([]*FixedCost, error) {
+func FixedCostsByUserID(ctx context.Context, db XODB, userID int) ([]*FixedCost, error) {
 	var err error
 
 	// sql query
@@ -304,7 +305,7 @@ func FixedCostsByUserID(db XODB, userID int) ([]*FixedCost, error) {
 
 	// run query
 	XOLog(sqlstr, userID)
-	q, err := db.Query(sqlstr, userID)
+	q, err := db.QueryContext(ctx, sqlstr, userID)
 	if err != nil {
 		return nil, err
 	}
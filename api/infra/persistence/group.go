@@ -0,0 +1,200 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/warikan/api/domain/model"
+)
+
+func SelectGroups(ctx context.Context, db XODB, userID int) ([]*model.Group, error) {
+	var err error
+
+	// sql query
+	const sqlstr = `SELECT g.id
+		, g.user_id
+		, g.name
+		, g.created_at
+		, g.updated_at
+		FROM groups g
+		INNER JOIN users u
+		ON g.user_id = u.id
+		WHERE g.user_id = $1
+		AND u.deleted_at IS NULL
+		ORDER BY g.id`
+
+	// run query
+	XOLog(sqlstr, userID)
+	q, err := db.QueryContext(ctx, sqlstr, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer q.Close()
+
+	groups := make([]*model.Group, 0)
+	for q.Next() {
+		var g model.Group
+		err := q.Scan(
+			&g.ID,
+			&g.UserID,
+			&g.Name,
+			&g.CreatedAt,
+			&g.UpdatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, &g)
+	}
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func SelectGroupMembers(ctx context.Context, db XODB, groupID int) ([]*model.GroupMember, error) {
+	var err error
+
+	// sql query
+	const sqlstr = `SELECT id
+		, group_id
+		, name
+		, created_at
+		, updated_at
+		FROM group_members
+		WHERE group_id = $1
+		ORDER BY id`
+
+	// run query
+	XOLog(sqlstr, groupID)
+	q, err := db.QueryContext(ctx, sqlstr, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer q.Close()
+
+	members := make([]*model.GroupMember, 0)
+	for q.Next() {
+		var m model.GroupMember
+		err := q.Scan(
+			&m.ID,
+			&m.GroupID,
+			&m.Name,
+			&m.CreatedAt,
+			&m.UpdatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, &m)
+	}
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// SelectGroupPayments : 負担するメンバーは含めない。SelectGroupPaymentSplitsで取得する
+func SelectGroupPayments(ctx context.Context, db XODB, groupID int) ([]*model.GroupPayment, error) {
+	var err error
+
+	// sql query
+	const sqlstr = `SELECT id
+		, group_id
+		, payer_member_id
+		, description
+		, payment_date
+		, payment
+		, split_type
+		, created_at
+		, updated_at
+		FROM group_payments
+		WHERE group_id = $1
+		ORDER BY payment_date, id`
+
+	// run query
+	XOLog(sqlstr, groupID)
+	q, err := db.QueryContext(ctx, sqlstr, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer q.Close()
+
+	payments := make([]*model.GroupPayment, 0)
+	for q.Next() {
+		var p model.GroupPayment
+		err := q.Scan(
+			&p.ID,
+			&p.GroupID,
+			&p.PayerMemberID,
+			&p.Description,
+			&p.PaymentDate,
+			&p.Payment,
+			&p.SplitType,
+			&p.CreatedAt,
+			&p.UpdatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, &p)
+	}
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+// SelectGroupPaymentSplits : グループの全ての支払いの負担するメンバーを、支払いID・メンバーIDの昇順で返す
+func SelectGroupPaymentSplits(ctx context.Context, db XODB, groupID int) ([]*GroupPaymentSplit, error) {
+	var err error
+
+	// sql query
+	const sqlstr = `SELECT s.group_payment_id
+		, s.member_id
+		, s.value
+		FROM group_payment_splits s
+		INNER JOIN group_payments p
+		ON s.group_payment_id = p.id
+		WHERE p.group_id = $1
+		ORDER BY s.group_payment_id, s.member_id`
+
+	// run query
+	XOLog(sqlstr, groupID)
+	q, err := db.QueryContext(ctx, sqlstr, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer q.Close()
+
+	splits := make([]*GroupPaymentSplit, 0)
+	for q.Next() {
+		s := GroupPaymentSplit{
+			_exists: true,
+		}
+		err := q.Scan(
+			&s.GroupPaymentID,
+			&s.MemberID,
+			&s.Value,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+		splits = append(splits, &s)
+	}
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+
+	return splits, nil
+}
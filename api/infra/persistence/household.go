@@ -0,0 +1,106 @@
+package persistence
+
+import (
+	"context"
+	"time"
+)
+
+// householdColumns : Householdのスキャン順と同じ列の並び
+const householdColumns = `id, owner_id, partner_id, invitation_token, invitation_expires_at, created_at, updated_at`
+
+// SelectHouseholdByUserID : userIDのユーザーが作成または参加している家計簿を返す。
+// 招待中の自分の家計簿と参加済みの家計簿の両方がある場合は参加済みの家計簿を返す
+func SelectHouseholdByUserID(ctx context.Context, db XODB, userID int) (*Household, error) {
+	// sql query
+	const sqlstr = `SELECT ` + householdColumns + `
+		FROM households
+		WHERE owner_id = $1
+		OR partner_id = $1
+		ORDER BY partner_id IS NULL, id
+		LIMIT 1`
+
+	// run query
+	XOLog(sqlstr, userID)
+	return scanHousehold(db.QueryRowContext(ctx, sqlstr, userID))
+}
+
+// UpsertHouseholdInvitation : ownerIDのユーザーの家計簿に招待トークンを設定する。家計簿がない場合は作成する
+func UpsertHouseholdInvitation(ctx context.Context, db XODB, ownerID int, tokenHash string, expiresAt, now time.Time) (*Household, error) {
+	// sql query
+	const sqlstr = `INSERT INTO households (owner_id, invitation_token, invitation_expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (owner_id) DO UPDATE
+		SET invitation_token = EXCLUDED.invitation_token
+		, invitation_expires_at = EXCLUDED.invitation_expires_at
+		, updated_at = EXCLUDED.updated_at
+		RETURNING ` + householdColumns
+
+	// run query
+	XOLog(sqlstr, ownerID, tokenHash, expiresAt, now)
+	return scanHousehold(db.QueryRowContext(ctx, sqlstr, ownerID, tokenHash, expiresAt, now))
+}
+
+// AcceptHouseholdInvitation : 有効期限内でパートナーが未参加の招待のみ承認し、招待トークンを削除する。該当する招待がない場合はsql.ErrNoRowsを返す
+func AcceptHouseholdInvitation(ctx context.Context, db XODB, tokenHash string, partnerID int, now time.Time) (*Household, error) {
+	// sql query
+	const sqlstr = `UPDATE households
+		SET partner_id = $1
+		, invitation_token = NULL
+		, invitation_expires_at = NULL
+		, updated_at = $2
+		WHERE invitation_token = $3
+		AND invitation_expires_at > $2
+		AND partner_id IS NULL
+		AND owner_id <> $1
+		RETURNING ` + householdColumns
+
+	// run query
+	XOLog(sqlstr, partnerID, now, tokenHash)
+	return scanHousehold(db.QueryRowContext(ctx, sqlstr, partnerID, now, tokenHash))
+}
+
+// LeaveHouseholds : 退会するユーザーを家計簿から外す。作成した家計簿は削除してパートナーが新しい家計簿を作成・参加できるようにし、
+// パートナーとして参加している家計簿はpartner_idを外して作成したユーザーのみの家計簿に戻す
+func LeaveHouseholds(ctx context.Context, db XODB, userID int, now time.Time) error {
+	// sql query
+	const (
+		deleteOwned = `DELETE FROM households
+		WHERE owner_id = $1`
+
+		leavePartner = `UPDATE households
+		SET partner_id = NULL
+		, updated_at = $2
+		WHERE partner_id = $1`
+	)
+
+	// run query
+	XOLog(deleteOwned, userID)
+	if _, err := db.ExecContext(ctx, deleteOwned, userID); err != nil {
+		return err
+	}
+
+	// run query
+	XOLog(leavePartner, userID, now)
+	if _, err := db.ExecContext(ctx, leavePartner, userID, now); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanHousehold(row rowScanner) (*Household, error) {
+	h := Household{
+		_exists: true,
+	}
+
+	err := row.Scan(&h.ID, &h.OwnerID, &h.PartnerID, &h.InvitationToken, &h.InvitationExpiresAt, &h.CreatedAt, &h.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &h, nil
+}
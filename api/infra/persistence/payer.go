@@ -0,0 +1,65 @@
+package persistence
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/warikan/api/domain/model"
+)
+
+// householdPartnerQuery : users uの家計簿に参加しているパートナーのアカウントの列を返すサブクエリ。参加していない場合はNULL
+const householdPartnerQuery = `(SELECT %s FROM households h INNER JOIN users pu ON h.partner_id = pu.id WHERE h.owner_id = u.id AND pu.deleted_at IS NULL)`
+
+// payerNameColumn : 支払者名をユーザー名・パートナー名に置き換える。payers a と users u を結合したクエリで使う。
+// パートナーが家計簿に参加している場合はパートナーのアカウントのユーザー名を使う
+var payerNameColumn = fmt.Sprintf(`CASE a.id WHEN %d THEN u.user_name WHEN %d THEN COALESCE(%s, u.partner_name) ELSE a.name END`,
+	model.PayerUser, model.PayerPartner, fmt.Sprintf(householdPartnerQuery, "pu.user_name"))
+
+// payerUserIDColumn : 支払者に対応するアカウントのユーザーID。パートナーが家計簿に参加していない場合はNULL
+var payerUserIDColumn = fmt.Sprintf(`CASE a.id WHEN %d THEN u.id WHEN %d THEN %s END`,
+	model.PayerUser, model.PayerPartner, fmt.Sprintf(householdPartnerQuery, "pu.id"))
+
+func SelectPayers(db XODB, userID int) ([]*model.Payer, error) {
+	var err error
+
+	// sql query
+	var sqlstr = `SELECT a.id
+		, ` + payerNameColumn + ` AS name
+		, ` + payerUserIDColumn + ` AS user_id
+		FROM payers a
+		CROSS JOIN users u
+		WHERE u.id = $1
+		AND u.deleted_at IS NULL
+		ORDER BY a.id`
+
+	// run query
+	XOLog(sqlstr, userID)
+	q, err := db.Query(sqlstr, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer q.Close()
+
+	payers := make([]*model.Payer, 0)
+	for q.Next() {
+		var a model.Payer
+		var payerUserID sql.NullInt64
+		err := q.Scan(
+			&a.ID,
+			&a.Name,
+			&payerUserID,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+		if payerUserID.Valid {
+			id := int(payerUserID.Int64)
+			a.UserID = &id
+		}
+		payers = append(payers, &a)
+	}
+
+	return payers, nil
+}
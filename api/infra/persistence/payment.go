@@ -1,40 +1,89 @@
 package persistence
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/warikan/api/domain/model"
 )
 
-func SelectPayments(db XODB, userID, limit, cursor int) ([]*model.Payment, error) {
+// likeEscaper : LIKEの検索文字列に含まれるワイルドカードをエスケープする
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SelectPayments : filterに一致する支払いを支払日・IDの降順で、cursorより後から最大limit件返す。cursorがnilの場合は先頭から返す
+func SelectPayments(ctx context.Context, db XODB, userID int, filter *model.PaymentFilter, cursor *model.PaymentCursor, limit int) ([]*model.Payment, error) {
 	var err error
 
-	args := []interface{}{userID, limit}
+	args := []interface{}{userID}
 
 	// sql query
 	var sqlstr = `SELECT p.id
 		, c.name AS category_name
-		, a.name AS payer_name
+		, ` + payerNameColumn + ` AS payer_name
+		, p.description
 		, p.payment_date
 		, p.payment
+		, p.status
 		, p.created_at
 		FROM payments p
+		INNER JOIN users u
+		ON p.user_id = u.id
 		LEFT JOIN payers a
 		ON p.payer_id = a.id
 		LEFT JOIN categories c
 		ON p.category_id = c.id
-		WHERE p.user_id = $1`
+		WHERE p.user_id = $1
+		AND u.deleted_at IS NULL`
+
+	// cond : 条件のプレースホルダーに引数の位置を埋め込んで追加する
+	cond := func(format string, arg interface{}) {
+		args = append(args, arg)
+		sqlstr += fmt.Sprintf("\n\t\t"+format, len(args))
+	}
+
+	if filter != nil {
+		if !filter.From.IsZero() {
+			cond(`AND p.payment_date >= $%d`, filter.From)
+		}
+		if !filter.To.IsZero() {
+			cond(`AND p.payment_date < $%d`, filter.To)
+		}
+		if filter.CategoryID != 0 {
+			cond(`AND p.category_id = $%d`, filter.CategoryID)
+		}
+		if filter.PayerID != 0 {
+			cond(`AND p.payer_id = $%d`, filter.PayerID)
+		}
+		if filter.MinPayment != 0 {
+			cond(`AND p.payment >= $%d`, filter.MinPayment)
+		}
+		if filter.MaxPayment != 0 {
+			cond(`AND p.payment <= $%d`, filter.MaxPayment)
+		}
+		if filter.Description != "" {
+			cond(`AND p.description ILIKE $%d`, "%"+likeEscaper.Replace(filter.Description)+"%")
+		}
+		if filter.Status != "" {
+			cond(`AND p.status = $%d`, filter.Status)
+		}
+	}
 
-	if cursor != 0 {
-		sqlstr += `WHERE a.id < $3`
-		args = append(args, cursor)
+	if cursor != nil {
+		args = append(args, cursor.PaymentDate, cursor.ID)
+		sqlstr += fmt.Sprintf(`
+		AND (p.payment_date, p.id) < ($%d, $%d)`, len(args)-1, len(args))
 	}
 
-	sqlstr += `
-		ORDER BY p.payment_date, p.created_at DESC
-		LIMIT $2`
+	args = append(args, limit)
+	sqlstr += fmt.Sprintf(`
+		ORDER BY p.payment_date DESC, p.id DESC
+		LIMIT $%d`, len(args))
 
 	// run query
 	XOLog(sqlstr, args...)
-	q, err := db.Query(sqlstr, args...)
+	q, err := db.QueryContext(ctx, sqlstr, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -48,8 +97,10 @@ func SelectPayments(db XODB, userID, limit, cursor int) ([]*model.Payment, error
 			&p.ID,
 			&p.CategoryName,
 			&p.PayerName,
+			&p.Description,
 			&p.PaymentDate,
 			&p.Payment,
+			&p.Status,
 			&p.CreatedAt,
 		)
 
@@ -59,38 +110,217 @@ func SelectPayments(db XODB, userID, limit, cursor int) ([]*model.Payment, error
 		payments = append(payments, &p)
 	}
 
+	// 走査中にcontextがキャンセルされた場合もここで返す
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+
 	return payments, nil
 }
 
-func SelectPaymentDateByUserID(db XODB, userID int) ([]*string, error) {
+// SelectPayment : 他のユーザーの支払いはsql.ErrNoRowsを返す
+func SelectPayment(ctx context.Context, db XODB, userID, paymentID int) (*model.Payment, error) {
+	var err error
+
+	// sql query
+	var sqlstr = `SELECT p.id
+		, p.user_id
+		, p.category_id
+		, c.name AS category_name
+		, p.payer_id
+		, ` + payerNameColumn + ` AS payer_name
+		, p.description
+		, p.payment_date
+		, p.payment
+		, p.status
+		, p.created_by
+		, p.created_at
+		, p.updated_at
+		FROM payments p
+		INNER JOIN users u
+		ON p.user_id = u.id
+		LEFT JOIN payers a
+		ON p.payer_id = a.id
+		LEFT JOIN categories c
+		ON p.category_id = c.id
+		WHERE p.id = $1
+		AND p.user_id = $2
+		AND u.deleted_at IS NULL`
+
+	// run query
+	XOLog(sqlstr, paymentID, userID)
+	var p model.Payment
+	err = db.QueryRowContext(ctx, sqlstr, paymentID, userID).Scan(
+		&p.ID,
+		&p.UserID,
+		&p.CategoryID,
+		&p.CategoryName,
+		&p.PayerID,
+		&p.PayerName,
+		&p.Description,
+		&p.PaymentDate,
+		&p.Payment,
+		&p.Status,
+		&p.CreatedBy,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func SelectMonthlyCategoryPayments(ctx context.Context, db XODB, userID int) ([]*model.MonthlyCategoryPayment, error) {
 	var err error
 
 	// sql query
-	var sqlstr = `SELECT to_char(p.payment_date, 'YYYY-MM') as payment_date
-	FROM payments p
-	WHERE p.user_id = $1
-	GROUP BY payment_date
-	ORDER BY payment_date DESC`
+	var sqlstr = `SELECT to_char(p.payment_date AT TIME ZONE 'Asia/Tokyo', 'YYYY-MM') AS year_month
+		, p.category_id
+		, c.name AS category_name
+		, p.payer_id
+		, SUM(p.payment) AS payment
+		, COUNT(*) AS count
+		FROM payments p
+		INNER JOIN users u
+		ON p.user_id = u.id
+		LEFT JOIN categories c
+		ON p.category_id = c.id
+		WHERE p.user_id = $1
+		AND u.deleted_at IS NULL
+		AND p.status = '` + model.PaymentStatusApproved + `'
+		GROUP BY year_month, p.category_id, c.name, p.payer_id
+		ORDER BY year_month DESC, p.category_id, p.payer_id`
 
 	// run query
 	XOLog(sqlstr, userID)
-	q, err := db.Query(sqlstr, userID)
+	q, err := db.QueryContext(ctx, sqlstr, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	defer q.Close()
 
-	paymentsDate := []*string{}
+	monthlyPayments := make([]*model.MonthlyCategoryPayment, 0)
 	for q.Next() {
-		var d string
-		err := q.Scan(&d)
+		var mp model.MonthlyCategoryPayment
+		err := q.Scan(
+			&mp.YearMonth,
+			&mp.CategoryID,
+			&mp.CategoryName,
+			&mp.PayerID,
+			&mp.Payment,
+			&mp.Count,
+		)
 
 		if err != nil {
 			return nil, err
 		}
-		paymentsDate = append(paymentsDate, &d)
+		monthlyPayments = append(monthlyPayments, &mp)
+	}
+
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+
+	return monthlyPayments, nil
+}
+
+// UpdatePaymentIfUnmodified : 支払いのupdated_atがupdatedAtと一致する場合のみ更新し、更新したかどうかを返す
+func UpdatePaymentIfUnmodified(ctx context.Context, db XODB, p *Payment, updatedAt time.Time) (bool, error) {
+	// sql query
+	const sqlstr = `UPDATE payments
+		SET category_id = $1
+		, payer_id = $2
+		, description = $3
+		, payment_date = $4
+		, payment = $5
+		, status = $6
+		, created_by = $7
+		, updated_at = $8
+		WHERE id = $9
+		AND updated_at = $10`
+
+	// run query
+	XOLog(sqlstr, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.Status, p.CreatedBy, p.UpdatedAt, p.ID, updatedAt)
+	res, err := db.ExecContext(ctx, sqlstr, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.Status, p.CreatedBy, p.UpdatedAt, p.ID, updatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+// DeletePaymentIfUnmodified : 支払いのupdated_atがupdatedAtと一致する場合のみ削除し、削除したかどうかを返す
+func DeletePaymentIfUnmodified(ctx context.Context, db XODB, paymentID int, updatedAt time.Time) (bool, error) {
+	// sql query
+	const sqlstr = `DELETE FROM payments
+		WHERE id = $1
+		AND updated_at = $2`
+
+	// run query
+	XOLog(sqlstr, paymentID, updatedAt)
+	res, err := db.ExecContext(ctx, sqlstr, paymentID, updatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+// UpdatePaymentStatus : 支払いの承認状態がfromの場合のみtoに更新し、更新したかどうかを返す
+func UpdatePaymentStatus(ctx context.Context, db XODB, paymentID int, from, to string, updatedAt time.Time) (bool, error) {
+	// sql query
+	const sqlstr = `UPDATE payments
+		SET status = $1
+		, updated_at = $2
+		WHERE id = $3
+		AND status = $4`
+
+	// run query
+	XOLog(sqlstr, to, updatedAt, paymentID, from)
+	res, err := db.ExecContext(ctx, sqlstr, to, updatedAt, paymentID, from)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
+
+// CountSamePayments : ユーザーの支払いのうち、支払日(JSTの日付)・カテゴリー・支払者・金額・内容がpと同じものの件数を返す
+func CountSamePayments(ctx context.Context, db XODB, p *Payment) (int, error) {
+	// sql query
+	const sqlstr = `SELECT COUNT(*)
+		FROM payments
+		WHERE user_id = $1
+		AND (payment_date AT TIME ZONE 'Asia/Tokyo')::date = ($2::timestamptz AT TIME ZONE 'Asia/Tokyo')::date
+		AND category_id = $3
+		AND payer_id = $4
+		AND payment = $5
+		AND description IS NOT DISTINCT FROM $6`
+
+	// run query
+	XOLog(sqlstr, p.UserID, p.PaymentDate, p.CategoryID, p.PayerID, p.Payment, p.Description)
+	var n int
+	if err := db.QueryRowContext(ctx, sqlstr, p.UserID, p.PaymentDate, p.CategoryID, p.PayerID, p.Payment, p.Description).Scan(&n); err != nil {
+		return 0, err
 	}
 
-	return paymentsDate, nil
+	return n, nil
 }
@@ -4,6 +4,7 @@ package persistence
 // Code generated by xo. DO NOT EDIT.
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"time"
@@ -11,15 +12,18 @@ import (
 
 // Payment represents a row from 'public.payments'.
 type Payment struct {
-	ID          int            `json:"id"`           // id
-	UserID      int            `json:"user_id"`      // user_id
-	CategoryID  int            `json:"category_id"`  // category_id
-	PayerID     int            `json:"payer_id"`     // payer_id
-	Description sql.NullString `json:"description"`  // description
-	PaymentDate time.Time      `json:"payment_date"` // payment_date
-	Payment     int            `json:"payment"`      // payment
-	CreatedAt   time.Time      `json:"created_at"`   // created_at
-	UpdatedAt   time.Time      `json:"updated_at"`   // updated_at
+	ID          int            `json:"id"`            // id
+	UserID      int            `json:"user_id"`       // user_id
+	CategoryID  int            `json:"category_id"`   // category_id
+	PayerID     int            `json:"payer_id"`      // payer_id
+	Description sql.NullString `json:"description"`   // description
+	PaymentDate time.Time      `json:"payment_date"`  // payment_date
+	Payment     int            `json:"payment"`       // payment
+	CreatedAt   time.Time      `json:"created_at"`    // created_at
+	UpdatedAt   time.Time      `json:"updated_at"`    // updated_at
+	FixedCostID sql.NullInt64  `json:"fixed_cost_id"` // fixed_cost_id
+	Status      string         `json:"status"`        // status
+	CreatedBy   sql.NullInt64  `json:"created_by"`    // created_by
 
 	// xo fields
 	_exists, _deleted bool
@@ -36,7 +40,7 @@ func (p *Payment) Deleted() bool {
 }
 
 // Insert inserts the Payment to the database.
-func (p *Payment) Insert(db XODB) error {
+func (p *Payment) Insert(ctx context.Context, db XODB) error {
 	var err error
 
 	// if already exist, bail
@@ -46,14 +50,14 @@ func (p *Payment) Insert(db XODB) error {
 
 	// sql insert query, primary key provided by sequence
 	const sqlstr = `INSERT INTO public.payments (` +
-		`user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at` +
+		`user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at, fixed_cost_id, status, created_by` +
 		`) VALUES (` +
-		`$1, $2, $3, $4, $5, $6, $7, $8` +
+		`$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11` +
 		`) RETURNING id`
 
 	// run query
-	XOLog(sqlstr, p.UserID, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.CreatedAt, p.UpdatedAt)
-	err = db.QueryRow(sqlstr, p.UserID, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.CreatedAt, p.UpdatedAt).Scan(&p.ID)
+	XOLog(sqlstr, p.UserID, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.CreatedAt, p.UpdatedAt, p.FixedCostID, p.Status, p.CreatedBy)
+	err = db.QueryRowContext(ctx, sqlstr, p.UserID, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.CreatedAt, p.UpdatedAt, p.FixedCostID, p.Status, p.CreatedBy).Scan(&p.ID)
 	if err != nil {
 		return err
 	}
@@ -65,7 +69,7 @@ func (p *Payment) Insert(db XODB) error {
 }
 
 // Update updates the Payment in the database.
-func (p *Payment) Update(db XODB) error {
+func (p *Payment) Update(ctx context.Context, db XODB) error {
 	var err error
 
 	// if doesn't exist, bail
@@ -80,30 +84,30 @@ func (p *Payment) Update(db XODB) error {
 
 	// sql query
 	const sqlstr = `UPDATE public.payments SET (` +
-		`user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at` +
+		`user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at, fixed_cost_id, status, created_by` +
 		`) = ( ` +
-		`$1, $2, $3, $4, $5, $6, $7, $8` +
-		`) WHERE id = $9`
+		`$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11` +
+		`) WHERE id = $12`
 
 	// run query
-	XOLog(sqlstr, p.UserID, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.CreatedAt, p.UpdatedAt, p.ID)
-	_, err = db.Exec(sqlstr, p.UserID, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.CreatedAt, p.UpdatedAt, p.ID)
+	XOLog(sqlstr, p.UserID, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.CreatedAt, p.UpdatedAt, p.FixedCostID, p.Status, p.CreatedBy, p.ID)
+	_, err = db.ExecContext(ctx, sqlstr, p.UserID, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.CreatedAt, p.UpdatedAt, p.FixedCostID, p.Status, p.CreatedBy, p.ID)
 	return err
 }
 
 // Save saves the Payment to the database.
-func (p *Payment) Save(db XODB) error {
+func (p *Payment) Save(ctx context.Context, db XODB) error {
 	if p.Exists() {
-		return p.Update(db)
+		return p.Update(ctx, db)
 	}
 
-	return p.Insert(db)
+	return p.Insert(ctx, db)
 }
 
 // Upsert performs an upsert for Payment.
 //
 // NOTE: PostgreSQL 9.5+ only
-func (p *Payment) Upsert(db XODB) error {
+func (p *Payment) Upsert(ctx context.Context, db XODB) error {
 	var err error
 
 	// if already exist, bail
@@ -113,18 +117,18 @@ func (p *Payment) Upsert(db XODB) error {
 
 	// sql query
 	const sqlstr = `INSERT INTO public.payments (` +
-		`id, user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at` +
+		`id, user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at, fixed_cost_id, status, created_by` +
 		`) VALUES (` +
-		`$1, $2, $3, $4, $5, $6, $7, $8, $9` +
+		`$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12` +
 		`) ON CONFLICT (id) DO UPDATE SET (` +
-		`id, user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at` +
+		`id, user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at, fixed_cost_id, status, created_by` +
 		`) = (` +
-		`EXCLUDED.id, EXCLUDED.user_id, EXCLUDED.category_id, EXCLUDED.payer_id, EXCLUDED.description, EXCLUDED.payment_date, EXCLUDED.payment, EXCLUDED.created_at, EXCLUDED.updated_at` +
+		`EXCLUDED.id, EXCLUDED.user_id, EXCLUDED.category_id, EXCLUDED.payer_id, EXCLUDED.description, EXCLUDED.payment_date, EXCLUDED.payment, EXCLUDED.created_at, EXCLUDED.updated_at, EXCLUDED.fixed_cost_id, EXCLUDED.status, EXCLUDED.created_by` +
 		`)`
 
 	// run query
-	XOLog(sqlstr, p.ID, p.UserID, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.CreatedAt, p.UpdatedAt)
-	_, err = db.Exec(sqlstr, p.ID, p.UserID, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.CreatedAt, p.UpdatedAt)
+	XOLog(sqlstr, p.ID, p.UserID, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.CreatedAt, p.UpdatedAt, p.FixedCostID, p.Status, p.CreatedBy)
+	_, err = db.ExecContext(ctx, sqlstr, p.ID, p.UserID, p.CategoryID, p.PayerID, p.Description, p.PaymentDate, p.Payment, p.CreatedAt, p.UpdatedAt, p.FixedCostID, p.Status, p.CreatedBy)
 	if err != nil {
 		return err
 	}
@@ -136,7 +140,7 @@ func (p *Payment) Upsert(db XODB) error {
 }
 
 // Delete deletes the Payment from the database.
-func (p *Payment) Delete(db XODB) error {
+func (p *Payment) Delete(ctx context.Context, db XODB) error {
 	var err error
 
 	// if doesn't exist, bail
@@ -154,7 +158,7 @@ func (p *Payment) Delete(db XODB) error {
 
 	// run query
 	XOLog(sqlstr, p.ID)
-	_, err = db.Exec(sqlstr, p.ID)
+	_, err = db.ExecContext(ctx, sqlstr, p.ID)
 	if err != nil {
 		return err
 	}
@@ -172,6 +176,13 @@ func (p *Payment) Category(db XODB) (*Category, error) {
 	return CategoryByID(db, p.CategoryID)
 }
 
+// FixedCost returns the FixedCost associated with the Payment's FixedCostID (fixed_cost_id).
+//
+// Generated from foreign key 'payments_fixed_cost_id_fkey'.
+func (p *Payment) FixedCost(ctx context.Context, db XODB) (*FixedCost, error) {
+	return FixedCostByID(ctx, db, int(p.FixedCostID.Int64))
+}
+
 // Payer returns the Payer associated with the Payment's PayerID (payer_id).
 //
 // Generated from foreign key 'payments_payer_id_fkey'.
@@ -189,18 +200,18 @@ func (p *Payment) User(db XODB) (*User, error) {
 // PaymentsByCategoryID retrieves a row from 'public.payments' as a Payment.
 //
 // Generated from index 'payments_category_id_idx'.
-func PaymentsByCategoryID(db XODB, categoryID int) ([]*Payment, error) {
+func PaymentsByCategoryID(ctx context.Context, db XODB, categoryID int) ([]*Payment, error) {
 	var err error
 
 	// sql query
 	const sqlstr = `SELECT ` +
-		`id, user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at ` +
+		`id, user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at, fixed_cost_id, status, created_by ` +
 		`FROM public.payments ` +
 		`WHERE category_id = $1`
 
 	// run query
 	XOLog(sqlstr, categoryID)
-	q, err := db.Query(sqlstr, categoryID)
+	q, err := db.QueryContext(ctx, sqlstr, categoryID)
 	if err != nil {
 		return nil, err
 	}
@@ -214,7 +225,7 @@ func PaymentsByCategoryID(db XODB, categoryID int) ([]*Payment, error) {
 		}
 
 		// scan
-		err = q.Scan(&p.ID, &p.UserID, &p.CategoryID, &p.PayerID, &p.Description, &p.PaymentDate, &p.Payment, &p.CreatedAt, &p.UpdatedAt)
+		err = q.Scan(&p.ID, &p.UserID, &p.CategoryID, &p.PayerID, &p.Description, &p.PaymentDate, &p.Payment, &p.CreatedAt, &p.UpdatedAt, &p.FixedCostID, &p.Status, &p.CreatedBy)
 		if err != nil {
 			return nil, err
 		}
@@ -225,21 +236,47 @@ func PaymentsByCategoryID(db XODB, categoryID int) ([]*Payment, error) {
 	return res, nil
 }
 
+// PaymentByFixedCostIDPaymentDate retrieves a row from 'public.payments' as a Payment.
+//
+// Generated from index 'payments_fixed_cost_id_payment_date_idx'.
+func PaymentByFixedCostIDPaymentDate(ctx context.Context, db XODB, fixedCostID sql.NullInt64, paymentDate time.Time) (*Payment, error) {
+	var err error
+
+	// sql query
+	const sqlstr = `SELECT ` +
+		`id, user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at, fixed_cost_id, status, created_by ` +
+		`FROM public.payments ` +
+		`WHERE fixed_cost_id = $1 AND payment_date = $2`
+
+	// run query
+	XOLog(sqlstr, fixedCostID, paymentDate)
+	p := Payment{
+		_exists: true,
+	}
+
+	err = db.QueryRowContext(ctx, sqlstr, fixedCostID, paymentDate).Scan(&p.ID, &p.UserID, &p.CategoryID, &p.PayerID, &p.Description, &p.PaymentDate, &p.Payment, &p.CreatedAt, &p.UpdatedAt, &p.FixedCostID, &p.Status, &p.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
 // PaymentsByPayerID retrieves a row from 'public.payments' as a Payment.
 //
 // Generated from index 'payments_payer_id_idx'.
-func PaymentsByPayerID(db XODB, payerID int) ([]*Payment, error) {
+func PaymentsByPayerID(ctx context.Context, db XODB, payerID int) ([]*Payment, error) {
 	var err error
 
 	// sql query
 	const sqlstr = `SELECT ` +
-		`id, user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at ` +
+		`id, user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at, fixed_cost_id, status, created_by ` +
 		`FROM public.payments ` +
 		`WHERE payer_id = $1`
 
 	// run query
 	XOLog(sqlstr, payerID)
-	q, err := db.Query(sqlstr, payerID)
+	q, err := db.QueryContext(ctx, sqlstr, payerID)
 	if err != nil {
 		return nil, err
 	}
@@ -253,7 +290,7 @@ func PaymentsByPayerID(db XODB, payerID int) ([]*Payment, error) {
 		}
 
 		// scan
-		err = q.Scan(&p.ID, &p.UserID, &p.CategoryID, &p.PayerID, &p.Description, &p.PaymentDate, &p.Payment, &p.CreatedAt, &p.UpdatedAt)
+		err = q.Scan(&p.ID, &p.UserID, &p.CategoryID, &p.PayerID, &p.Description, &p.PaymentDate, &p.Payment, &p.CreatedAt, &p.UpdatedAt, &p.FixedCostID, &p.Status, &p.CreatedBy)
 		if err != nil {
 			return nil, err
 		}
@@ -267,12 +304,12 @@ func PaymentsByPayerID(db XODB, payerID int) ([]*Payment, error) {
 // PaymentByID retrieves a row from 'public.payments' as a Payment.
 //
 // Generated from index 'payments_pkey'.
-func PaymentByID(db XODB, id int) (*Payment, error) {
+func PaymentByID(ctx context.Context, db XODB, id int) (*Payment, error) {
 	var err error
 
 	// sql query
 	const sqlstr = `SELECT ` +
-		`id, user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at ` +
+		`id, user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at, fixed_cost_id, status, created_by ` +
 		`FROM public.payments ` +
 		`WHERE id = $1`
 
@@ -282,7 +319,7 @@ func PaymentByID(db XODB, id int) (*Payment, error) {
 		_exists: true,
 	}
 
-	err = db.QueryRow(sqlstr, id).Scan(&p.ID, &p.UserID, &p.CategoryID, &p.PayerID, &p.Description, &p.PaymentDate, &p.Payment, &p.CreatedAt, &p.UpdatedAt)
+	err = db.QueryRowContext(ctx, sqlstr, id).Scan(&p.ID, &p.UserID, &p.CategoryID, &p.PayerID, &p.Description, &p.PaymentDate, &p.Payment, &p.CreatedAt, &p.UpdatedAt, &p.FixedCostID, &p.Status, &p.CreatedBy)
 	if err != nil {
 		return nil, err
 	}
@@ -293,18 +330,18 @@ func PaymentByID(db XODB, id int) (*Payment, error) {
 // PaymentsByUserID retrieves a row from 'public.payments' as a Payment.
 //
 // Generated from index 'payments_user_id_idx'.
-func PaymentsByUserID(db XODB, userID int) ([]*Payment, error) {
+func PaymentsByUserID(ctx context.Context, db XODB, userID int) ([]*Payment, error) {
 	var err error
 
 	// sql query
 	const sqlstr = `SELECT ` +
-		`id, user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at ` +
+		`id, user_id, category_id, payer_id, description, payment_date, payment, created_at, updated_at, fixed_cost_id, status, created_by ` +
 		`FROM public.payments ` +
 		`WHERE user_id = $1`
 
 	// run query
 	XOLog(sqlstr, userID)
-	q, err := db.Query(sqlstr, userID)
+	q, err := db.QueryContext(ctx, sqlstr, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -318,7 +355,7 @@ func PaymentsByUserID(db XODB, userID int) ([]*Payment, error) {
 		}
 
 		// scan
-		err = q.Scan(&p.ID, &p.UserID, &p.CategoryID, &p.PayerID, &p.Description, &p.PaymentDate, &p.Payment, &p.CreatedAt, &p.UpdatedAt)
+		err = q.Scan(&p.ID, &p.UserID, &p.CategoryID, &p.PayerID, &p.Description, &p.PaymentDate, &p.Payment, &p.CreatedAt, &p.UpdatedAt, &p.FixedCostID, &p.Status, &p.CreatedBy)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,83 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/warikan/api/domain/model"
+)
+
+func SelectPaymentDrafts(ctx context.Context, db XODB, userID int) ([]*model.PaymentDraft, error) {
+	var err error
+
+	// sql query
+	const sqlstr = `SELECT d.id
+		, d.user_id
+		, d.source
+		, d.description
+		, d.payment_date
+		, d.payment
+		, d.created_at
+		, d.updated_at
+		FROM payment_drafts d
+		INNER JOIN users u
+		ON d.user_id = u.id
+		WHERE d.user_id = $1
+		AND u.deleted_at IS NULL
+		ORDER BY d.payment_date, d.id`
+
+	// run query
+	XOLog(sqlstr, userID)
+	q, err := db.QueryContext(ctx, sqlstr, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer q.Close()
+
+	drafts := make([]*model.PaymentDraft, 0)
+	for q.Next() {
+		var d model.PaymentDraft
+		err := q.Scan(
+			&d.ID,
+			&d.UserID,
+			&d.Source,
+			&d.Description,
+			&d.PaymentDate,
+			&d.Payment,
+			&d.CreatedAt,
+			&d.UpdatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+		drafts = append(drafts, &d)
+	}
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+
+	return drafts, nil
+}
+
+// CountSamePaymentDrafts : ユーザーの下書きのうち、支払日(JSTの日付)・金額・内容が同じものの件数を返す
+func CountSamePaymentDrafts(ctx context.Context, db XODB, userID int, paymentDate time.Time, payment int, description sql.NullString) (int, error) {
+	// sql query
+	const sqlstr = `SELECT COUNT(*)
+		FROM payment_drafts
+		WHERE user_id = $1
+		AND (payment_date AT TIME ZONE 'Asia/Tokyo')::date = ($2::timestamptz AT TIME ZONE 'Asia/Tokyo')::date
+		AND payment = $3
+		AND description IS NOT DISTINCT FROM $4`
+
+	// run query
+	XOLog(sqlstr, userID, paymentDate, payment, description)
+	var n int
+	if err := db.QueryRowContext(ctx, sqlstr, userID, paymentDate, payment, description).Scan(&n); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
@@ -0,0 +1,56 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/warikan/api/domain/model"
+)
+
+func SelectCategoryPayments(db XODB, userID int, from, to time.Time) ([]*model.CategoryPayment, error) {
+	var err error
+
+	// sql query
+	var sqlstr = `SELECT p.category_id
+		, c.name AS category_name
+		, p.payer_id
+		, SUM(p.payment) AS payment
+		FROM payments p
+		INNER JOIN users u
+		ON p.user_id = u.id
+		LEFT JOIN categories c
+		ON p.category_id = c.id
+		WHERE p.user_id = $1
+		AND u.deleted_at IS NULL
+		AND p.payment_date >= $2
+		AND p.payment_date < $3
+		AND p.status = '` + model.PaymentStatusApproved + `'
+		GROUP BY p.category_id, c.name, p.payer_id
+		ORDER BY p.category_id, p.payer_id`
+
+	// run query
+	XOLog(sqlstr, userID, from, to)
+	q, err := db.Query(sqlstr, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	defer q.Close()
+
+	categoryPayments := make([]*model.CategoryPayment, 0)
+	for q.Next() {
+		var cp model.CategoryPayment
+		err := q.Scan(
+			&cp.CategoryID,
+			&cp.CategoryName,
+			&cp.PayerID,
+			&cp.Payment,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+		categoryPayments = append(categoryPayments, &cp)
+	}
+
+	return categoryPayments, nil
+}
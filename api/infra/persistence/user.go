@@ -0,0 +1,71 @@
+package persistence
+
+import (
+	"context"
+	"time"
+)
+
+// ActiveUserByEmail : 退会していないユーザーをメールアドレスで取得する。
+// 退会済みのユーザーとはメールアドレスが重複しうるため、UserByEmailの代わりに使う
+func ActiveUserByEmail(db XODB, email string) (*User, error) {
+	var err error
+
+	// sql query
+	const sqlstr = `SELECT ` +
+		`id, user_name, partner_name, email, password, user_image, partner_image, proportion, created_at, updated_at, deleted_at, language ` +
+		`FROM public.users ` +
+		`WHERE email = $1 ` +
+		`AND deleted_at IS NULL`
+
+	// run query
+	XOLog(sqlstr, email)
+	u := User{
+		_exists: true,
+	}
+
+	err = db.QueryRow(sqlstr, email).Scan(&u.ID, &u.UserName, &u.PartnerName, &u.Email, &u.Password, &u.UserImage, &u.PartnerImage, &u.Proportion, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt, &u.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// DeleteUsersDeletedBefore : before以前に退会したユーザーを支払い・固定費とともに削除し、削除したユーザー数を返す
+func DeleteUsersDeletedBefore(ctx context.Context, db XODB, before time.Time) (int, error) {
+	var err error
+
+	// sql query
+	const (
+		deletePayments = `DELETE FROM payments
+		WHERE user_id IN (SELECT id FROM users WHERE deleted_at < $1)`
+
+		deleteFixedCosts = `DELETE FROM fixed_costs
+		WHERE user_id IN (SELECT id FROM users WHERE deleted_at < $1)`
+
+		deleteUsers = `DELETE FROM users
+		WHERE deleted_at < $1`
+	)
+
+	for _, sqlstr := range []string{deletePayments, deleteFixedCosts} {
+		// run query
+		XOLog(sqlstr, before)
+		if _, err = db.ExecContext(ctx, sqlstr, before); err != nil {
+			return 0, err
+		}
+	}
+
+	// run query
+	XOLog(deleteUsers, before)
+	res, err := db.ExecContext(ctx, deleteUsers, before)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
@@ -23,6 +23,7 @@ type User struct {
 	CreatedAt    time.Time   `json:"created_at"`    // created_at
 	UpdatedAt    time.Time   `json:"updated_at"`    // updated_at
 	DeletedAt    pq.NullTime `json:"deleted_at"`    // deleted_at
+	Language     string      `json:"language"`      // language
 
 	// xo fields
 	_exists, _deleted bool
@@ -49,14 +50,14 @@ func (u *User) Insert(db XODB) error {
 
 	// sql insert query, primary key provided by sequence
 	const sqlstr = `INSERT INTO public.users (` +
-		`user_name, partner_name, email, password, user_image, partner_image, proportion, created_at, updated_at, deleted_at` +
+		`user_name, partner_name, email, password, user_image, partner_image, proportion, created_at, updated_at, deleted_at, language` +
 		`) VALUES (` +
-		`$1, $2, $3, $4, $5, $6, $7, $8, $9, $10` +
+		`$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11` +
 		`) RETURNING id`
 
 	// run query
-	XOLog(sqlstr, u.UserName, u.PartnerName, u.Email, u.Password, u.UserImage, u.PartnerImage, u.Proportion, u.CreatedAt, u.UpdatedAt, u.DeletedAt)
-	err = db.QueryRow(sqlstr, u.UserName, u.PartnerName, u.Email, u.Password, u.UserImage, u.PartnerImage, u.Proportion, u.CreatedAt, u.UpdatedAt, u.DeletedAt).Scan(&u.ID)
+	XOLog(sqlstr, u.UserName, u.PartnerName, u.Email, u.Password, u.UserImage, u.PartnerImage, u.Proportion, u.CreatedAt, u.UpdatedAt, u.DeletedAt, u.Language)
+	err = db.QueryRow(sqlstr, u.UserName, u.PartnerName, u.Email, u.Password, u.UserImage, u.PartnerImage, u.Proportion, u.CreatedAt, u.UpdatedAt, u.DeletedAt, u.Language).Scan(&u.ID)
 	if err != nil {
 		return err
 	}
@@ -83,14 +84,14 @@ func (u *User) Update(db XODB) error {
 
 	// sql query
 	const sqlstr = `UPDATE public.users SET (` +
-		`user_name, partner_name, email, password, user_image, partner_image, proportion, created_at, updated_at, deleted_at` +
+		`user_name, partner_name, email, password, user_image, partner_image, proportion, created_at, updated_at, deleted_at, language` +
 		`) = ( ` +
-		`$1, $2, $3, $4, $5, $6, $7, $8, $9, $10` +
-		`) WHERE id = $11`
+		`$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11` +
+		`) WHERE id = $12`
 
 	// run query
-	XOLog(sqlstr, u.UserName, u.PartnerName, u.Email, u.Password, u.UserImage, u.PartnerImage, u.Proportion, u.CreatedAt, u.UpdatedAt, u.DeletedAt, u.ID)
-	_, err = db.Exec(sqlstr, u.UserName, u.PartnerName, u.Email, u.Password, u.UserImage, u.PartnerImage, u.Proportion, u.CreatedAt, u.UpdatedAt, u.DeletedAt, u.ID)
+	XOLog(sqlstr, u.UserName, u.PartnerName, u.Email, u.Password, u.UserImage, u.PartnerImage, u.Proportion, u.CreatedAt, u.UpdatedAt, u.DeletedAt, u.Language, u.ID)
+	_, err = db.Exec(sqlstr, u.UserName, u.PartnerName, u.Email, u.Password, u.UserImage, u.PartnerImage, u.Proportion, u.CreatedAt, u.UpdatedAt, u.DeletedAt, u.Language, u.ID)
 	return err
 }
 
@@ -116,18 +117,18 @@ func (u *User) Upsert(db XODB) error {
 
 	// sql query
 	const sqlstr = `INSERT INTO public.users (` +
-		`id, user_name, partner_name, email, password, user_image, partner_image, proportion, created_at, updated_at, deleted_at` +
+		`id, user_name, partner_name, email, password, user_image, partner_image, proportion, created_at, updated_at, deleted_at, language` +
 		`) VALUES (` +
-		`$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11` +
+		`$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12` +
 		`) ON CONFLICT (id) DO UPDATE SET (` +
-		`id, user_name, partner_name, email, password, user_image, partner_image, proportion, created_at, updated_at, deleted_at` +
+		`id, user_name, partner_name, email, password, user_image, partner_image, proportion, created_at, updated_at, deleted_at, language` +
 		`) = (` +
-		`EXCLUDED.id, EXCLUDED.user_name, EXCLUDED.partner_name, EXCLUDED.email, EXCLUDED.password, EXCLUDED.user_image, EXCLUDED.partner_image, EXCLUDED.proportion, EXCLUDED.created_at, EXCLUDED.updated_at, EXCLUDED.deleted_at` +
+		`EXCLUDED.id, EXCLUDED.user_name, EXCLUDED.partner_name, EXCLUDED.email, EXCLUDED.password, EXCLUDED.user_image, EXCLUDED.partner_image, EXCLUDED.proportion, EXCLUDED.created_at, EXCLUDED.updated_at, EXCLUDED.deleted_at, EXCLUDED.language` +
 		`)`
 
 	// run query
-	XOLog(sqlstr, u.ID, u.UserName, u.PartnerName, u.Email, u.Password, u.UserImage, u.PartnerImage, u.Proportion, u.CreatedAt, u.UpdatedAt, u.DeletedAt)
-	_, err = db.Exec(sqlstr, u.ID, u.UserName, u.PartnerName, u.Email, u.Password, u.UserImage, u.PartnerImage, u.Proportion, u.CreatedAt, u.UpdatedAt, u.DeletedAt)
+	XOLog(sqlstr, u.ID, u.UserName, u.PartnerName, u.Email, u.Password, u.UserImage, u.PartnerImage, u.Proportion, u.CreatedAt, u.UpdatedAt, u.DeletedAt, u.Language)
+	_, err = db.Exec(sqlstr, u.ID, u.UserName, u.PartnerName, u.Email, u.Password, u.UserImage, u.PartnerImage, u.Proportion, u.CreatedAt, u.UpdatedAt, u.DeletedAt, u.Language)
 	if err != nil {
 		return err
 	}
@@ -168,6 +169,32 @@ func (u *User) Delete(db XODB) error {
 	return nil
 }
 
+// UserByEmail retrieves a row from 'public.users' as a User.
+//
+// Generated from index 'users_email_idx'.
+func UserByEmail(db XODB, email string) (*User, error) {
+	var err error
+
+	// sql query
+	const sqlstr = `SELECT ` +
+		`id, user_name, partner_name, email, password, user_image, partner_image, proportion, created_at, updated_at, deleted_at, language ` +
+		`FROM public.users ` +
+		`WHERE email = $1`
+
+	// run query
+	XOLog(sqlstr, email)
+	u := User{
+		_exists: true,
+	}
+
+	err = db.QueryRow(sqlstr, email).Scan(&u.ID, &u.UserName, &u.PartnerName, &u.Email, &u.Password, &u.UserImage, &u.PartnerImage, &u.Proportion, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt, &u.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
 // UserByID retrieves a row from 'public.users' as a User.
 //
 // Generated from index 'users_pkey'.
@@ -176,7 +203,7 @@ func UserByID(db XODB, id int) (*User, error) {
 
 	// sql query
 	const sqlstr = `SELECT ` +
-		`id, user_name, partner_name, email, password, user_image, partner_image, proportion, created_at, updated_at, deleted_at ` +
+		`id, user_name, partner_name, email, password, user_image, partner_image, proportion, created_at, updated_at, deleted_at, language ` +
 		`FROM public.users ` +
 		`WHERE id = $1`
 
@@ -186,7 +213,7 @@ func UserByID(db XODB, id int) (*User, error) {
 		_exists: true,
 	}
 
-	err = db.QueryRow(sqlstr, id).Scan(&u.ID, &u.UserName, &u.PartnerName, &u.Email, &u.Password, &u.UserImage, &u.PartnerImage, &u.Proportion, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt)
+	err = db.QueryRow(sqlstr, id).Scan(&u.ID, &u.UserName, &u.PartnerName, &u.Email, &u.Password, &u.UserImage, &u.PartnerImage, &u.Proportion, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt, &u.Language)
 	if err != nil {
 		return nil, err
 	}
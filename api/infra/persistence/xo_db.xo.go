@@ -4,6 +4,7 @@ package persistence
 // Code generated by xo. DO NOT EDIT.
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"encoding/csv"
@@ -21,6 +22,9 @@ type XODB interface {
 	Exec(string, ...interface{}) (sql.Result, error)
 	Query(string, ...interface{}) (*sql.Rows, error)
 	QueryRow(string, ...interface{}) *sql.Row
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
 }
 
 // XOLog provides the log func used by generated queries.
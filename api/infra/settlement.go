@@ -0,0 +1,49 @@
+package infra
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra/persistence"
+)
+
+func NewSettlementRepository(db *sql.DB) *settlementPersistencePostgres {
+	return &settlementPersistencePostgres{
+		db: db,
+	}
+}
+
+var _ repository.SettlementRepository = &settlementPersistencePostgres{}
+
+type settlementPersistencePostgres struct {
+	db *sql.DB
+}
+
+func (r *settlementPersistencePostgres) FetchProportion(userID int) (int, error) {
+	u, err := persistence.UserByID(r.db, userID)
+	if err == sql.ErrNoRows {
+		return 0, errors.WithStack(repository.ErrNotFound)
+	}
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	if u.DeletedAt.Valid {
+		return 0, errors.WithStack(repository.ErrNotFound)
+	}
+
+	return int(u.Proportion), nil
+}
+
+func (r *settlementPersistencePostgres) FetchCategoryPayments(userID int, from, to time.Time) ([]*model.CategoryPayment, error) {
+	categoryPayments, err := persistence.SelectCategoryPayments(r.db, userID, from, to)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return categoryPayments, nil
+}
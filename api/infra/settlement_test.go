@@ -0,0 +1,67 @@
+package infra_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/infra"
+	"github.com/warikan/db"
+	"github.com/warikan/test"
+)
+
+func TestSettlementPersistencePostgres_FetchCategoryPayments(t *testing.T) {
+	r := infra.NewSettlementRepository(db.Pool)
+
+	tests := []struct {
+		name   string
+		userID int
+		from   time.Time
+		to     time.Time
+		want   []*model.CategoryPayment
+	}{
+		{
+			name:   "Success",
+			userID: 10001,
+			from:   time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+			to:     time.Date(2020, time.May, 1, 0, 0, 0, 0, time.UTC),
+			want: []*model.CategoryPayment{
+				{CategoryID: 1, CategoryName: "家賃", PayerID: 1, Payment: 5555},
+				{CategoryID: 1, CategoryName: "家賃", PayerID: 2, Payment: 1111},
+			},
+		},
+		{
+			name:   "Success no payments",
+			userID: 10001,
+			from:   time.Date(2020, time.May, 1, 0, 0, 0, 0, time.UTC),
+			to:     time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC),
+			want:   []*model.CategoryPayment{},
+		},
+		{
+			name:   "Pending payments are excluded",
+			userID: 10002,
+			from:   time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+			to:     time.Date(2020, time.May, 1, 0, 0, 0, 0, time.UTC),
+			want:   []*model.CategoryPayment{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := r.FetchCategoryPayments(tt.userID, tt.from, tt.to)
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("FetchCategoryPayments() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra/persistence"
+)
+
+type txContextKey struct{}
+
+func NewTxManager(db *sql.DB) *txManager {
+	return &txManager{
+		db: db,
+	}
+}
+
+var _ repository.TxManager = &txManager{}
+
+type txManager struct {
+	db *sql.DB
+}
+
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			// nolint:errcheck
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txContextKey{}, tx)); err != nil {
+		// nolint:errcheck
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// conn : ctxがWithinTxのトランザクション中の場合はそのトランザクションを、それ以外はdbを返す
+func conn(ctx context.Context, db *sql.DB) persistence.XODB {
+	if tx, ok := ctx.Value(txContextKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
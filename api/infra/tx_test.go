@@ -0,0 +1,79 @@
+package infra_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra"
+	"github.com/warikan/db"
+	"github.com/warikan/test"
+)
+
+func TestTxManager_WithinTx(t *testing.T) {
+	r := infra.NewPaymentsRepository(db.Pool, 5*time.Second)
+	m := infra.NewTxManager(db.Pool)
+
+	errRollback := errors.New("rollback")
+
+	tests := []struct {
+		name    string
+		fnErr   error
+		wantErr error
+		// wantExists : トランザクション終了後に作成した支払いが残っているかどうか
+		wantExists bool
+	}{
+		{
+			name:       "Commit",
+			wantExists: true,
+		},
+		{
+			name:       "Rollback",
+			fnErr:      errRollback,
+			wantErr:    errRollback,
+			wantExists: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+				t.Fatal(err)
+			}
+
+			var created *model.Payment
+			err := m.WithinTx(context.Background(), func(ctx context.Context) error {
+				var err error
+				created, err = r.Create(ctx, &model.Payment{
+					UserID:      10001,
+					CategoryID:  1,
+					PayerID:     1,
+					PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+					Payment:     1234,
+				})
+				if err != nil {
+					return err
+				}
+
+				// 同じトランザクション内ではコミット前でも参照できる
+				if _, err := r.FindByID(ctx, 10001, created.ID); err != nil {
+					t.Errorf("FindByID() in tx should succeed, but got %v", err)
+				}
+				return tt.fnErr
+			})
+			if err != tt.wantErr {
+				t.Errorf("WithinTx() unexpected error:\nwant: %v\ngot : %v", tt.wantErr, err)
+				return
+			}
+
+			_, err = r.FindByID(context.Background(), 10001, created.ID)
+			if exists := errors.Cause(err) != repository.ErrNotFound; exists != tt.wantExists {
+				t.Errorf("WithinTx() payment exists = %v, want %v (err: %v)", exists, tt.wantExists, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package infra
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra/persistence"
+)
+
+func NewUserRepository(db *sql.DB) *userPersistencePostgres {
+	return &userPersistencePostgres{
+		db: db,
+	}
+}
+
+var _ repository.UserRepository = &userPersistencePostgres{}
+
+type userPersistencePostgres struct {
+	db *sql.DB
+}
+
+func (r *userPersistencePostgres) Create(mu *model.User) (*model.User, error) {
+	now := time.Now()
+
+	u := &persistence.User{
+		UserName:     mu.UserName,
+		PartnerName:  mu.PartnerName,
+		Email:        mu.Email,
+		Password:     mu.Password,
+		UserImage:    mu.UserImage,
+		PartnerImage: mu.PartnerImage,
+		Proportion:   int16(mu.Proportion),
+		Language:     mu.Language,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := u.Save(r.db); err != nil {
+		return nil, translateError(err)
+	}
+
+	return r.toModel(u), nil
+}
+
+func (r *userPersistencePostgres) Update(mu *model.User) (*model.User, error) {
+	now := time.Now()
+
+	u, err := r.findByID(r.db, mu.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	u.UserName = mu.UserName
+	u.PartnerName = mu.PartnerName
+	u.Email = mu.Email
+	u.UserImage = mu.UserImage
+	u.PartnerImage = mu.PartnerImage
+	u.Proportion = int16(mu.Proportion)
+	u.Language = mu.Language
+	u.UpdatedAt = now
+
+	if err := u.Save(r.db); err != nil {
+		return nil, translateError(err)
+	}
+
+	return r.toModel(u), nil
+}
+
+func (r *userPersistencePostgres) FindByID(userID int) (*model.User, error) {
+	u, err := r.findByID(r.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.toModel(u), nil
+}
+
+func (r *userPersistencePostgres) FindByEmail(email string) (*model.User, error) {
+	u, err := persistence.ActiveUserByEmail(r.db, email)
+	if err == sql.ErrNoRows {
+		return nil, errors.WithStack(repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return r.toModel(u), nil
+}
+
+func (r *userPersistencePostgres) DeleteByID(ctx context.Context, userID int) error {
+	db := conn(ctx, r.db)
+
+	u, err := r.findByID(db, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	u.DeletedAt = pq.NullTime{Time: now, Valid: true}
+	u.UpdatedAt = now
+
+	if err := u.Save(db); err != nil {
+		return translateError(err)
+	}
+
+	// 退会したユーザーの家計簿にパートナーが残らないようにする
+	if err := persistence.LeaveHouseholds(ctx, db, u.ID, now); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func (r *userPersistencePostgres) PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error) {
+	n, err := persistence.DeleteUsersDeletedBefore(ctx, conn(ctx, r.db), before)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return n, nil
+}
+
+// findByID : 退会済みのユーザーは存在しないものとしてrepository.ErrNotFoundを返す
+func (r *userPersistencePostgres) findByID(db persistence.XODB, userID int) (*persistence.User, error) {
+	u, err := persistence.UserByID(db, userID)
+	if err == sql.ErrNoRows {
+		return nil, errors.WithStack(repository.ErrNotFound)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if u.DeletedAt.Valid {
+		return nil, errors.WithStack(repository.ErrNotFound)
+	}
+
+	return u, nil
+}
+
+func (*userPersistencePostgres) toModel(u *persistence.User) *model.User {
+	return &model.User{
+		ID:           u.ID,
+		UserName:     u.UserName,
+		PartnerName:  u.PartnerName,
+		Email:        u.Email,
+		Password:     u.Password,
+		UserImage:    u.UserImage,
+		PartnerImage: u.PartnerImage,
+		Proportion:   int(u.Proportion),
+		Language:     u.Language,
+		CreatedAt:    u.CreatedAt,
+		UpdatedAt:    u.UpdatedAt,
+	}
+}
@@ -0,0 +1,245 @@
+package infra_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/infra"
+	"github.com/warikan/db"
+	"github.com/warikan/test"
+)
+
+func TestUserPersistencePostgres_FindByEmail(t *testing.T) {
+	r := infra.NewUserRepository(db.Pool)
+
+	tests := []struct {
+		name    string
+		email   string
+		want    *model.User
+		wantErr error
+	}{
+		{
+			name:  "Success",
+			email: "test@example.com",
+			want: &model.User{
+				ID:           10001,
+				UserName:     "ユーザーネーム",
+				PartnerName:  "パートナーネーム",
+				Email:        "test@example.com",
+				Password:     "password1234",
+				UserImage:    "user_image",
+				PartnerImage: "partner_image",
+				Proportion:   50,
+			},
+		},
+		{
+			name:    "Not found",
+			email:   "unknown@example.com",
+			wantErr: repository.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := r.FindByEmail(tt.email)
+			if tt.wantErr != nil {
+				if errors.Cause(err) != tt.wantErr {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(model.User{}, "CreatedAt", "UpdatedAt")); diff != "" {
+				t.Errorf("FindByEmail() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUserPersistencePostgres_Update(t *testing.T) {
+	r := infra.NewUserRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	arg := &model.User{
+		ID:           10001,
+		UserName:     "更新後ユーザー",
+		PartnerName:  "更新後パートナー",
+		Email:        "updated@example.com",
+		UserImage:    "user_image",
+		PartnerImage: "partner_image",
+		Proportion:   60,
+		Language:     "en",
+	}
+
+	got, err := r.Update(arg)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+
+	// パスワードはプロフィール更新で変更されない
+	want := *arg
+	want.Password = "password1234"
+	if diff := cmp.Diff(&want, got, cmpopts.IgnoreFields(model.User{}, "CreatedAt", "UpdatedAt")); diff != "" {
+		t.Errorf("Update() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestUserPersistencePostgres_UpdateConflict(t *testing.T) {
+	r := infra.NewUserRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := r.FindByID(10001)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 他のユーザーのメールアドレスには変更できない
+	user.Email = "other@example.com"
+	if _, err := r.Update(user); errors.Cause(err) != repository.ErrConflict {
+		t.Errorf("Update() unexpected error:\nwant: %v\ngot : %v", repository.ErrConflict, err)
+	}
+}
+
+func TestUserPersistencePostgres_DeleteByID(t *testing.T) {
+	r := infra.NewUserRepository(db.Pool)
+
+	if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 呼び出し側のトランザクションがロールバックされた場合は退会しない
+	rollback := errors.New("rollback")
+	err := infra.NewTxManager(db.Pool).WithinTx(context.Background(), func(ctx context.Context) error {
+		if err := r.DeleteByID(ctx, 10001); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if err != rollback {
+		t.Fatalf("WithinTx() unexpected error:\nwant: %v\ngot : %v", rollback, err)
+	}
+	if _, err := r.FindByID(10001); err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+
+	if err := r.DeleteByID(context.Background(), 10001); err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+
+	// 退会済みのユーザーは取得できない
+	if _, err := r.FindByID(10001); errors.Cause(err) != repository.ErrNotFound {
+		t.Errorf("FindByID() unexpected error:\nwant: %v\ngot : %v", repository.ErrNotFound, err)
+	}
+	if err := r.DeleteByID(context.Background(), 10001); errors.Cause(err) != repository.ErrNotFound {
+		t.Errorf("DeleteByID() unexpected error:\nwant: %v\ngot : %v", repository.ErrNotFound, err)
+	}
+
+	// 退会済みのユーザーのメールアドレスで新しいユーザーを登録できる
+	created, err := r.Create(&model.User{
+		UserName:    "新しいユーザー",
+		PartnerName: "新しいパートナー",
+		Email:       "test@example.com",
+		Password:    "password1234",
+		Proportion:  50,
+	})
+	if err != nil {
+		t.Fatalf("Create() err should be nil, but got %q", err)
+	}
+	found, err := r.FindByEmail("test@example.com")
+	if err != nil {
+		t.Fatalf("FindByEmail() err should be nil, but got %q", err)
+	}
+	if diff := cmp.Diff(created.ID, found.ID); diff != "" {
+		t.Errorf("FindByEmail() mismatch id (-want +got):\n%s", diff)
+	}
+
+	// 保持期間内のユーザーは物理削除されない
+	n, err := r.PurgeDeletedBefore(context.Background(), time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+	if n != 0 {
+		t.Errorf("PurgeDeletedBefore() = %d, want 0", n)
+	}
+
+	n, err = r.PurgeDeletedBefore(context.Background(), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+	if n != 1 {
+		t.Errorf("PurgeDeletedBefore() = %d, want 1", n)
+	}
+}
+
+func TestUserPersistencePostgres_DeleteByIDHousehold(t *testing.T) {
+	r := infra.NewUserRepository(db.Pool)
+	households := infra.NewHouseholdRepository(db.Pool)
+
+	tests := []struct {
+		name          string
+		userID        int
+		wantOwner     error
+		wantPartner   error
+		wantPartnerID int
+	}{
+		{
+			// 作成したユーザーが退会した場合、パートナーはどの家計簿にも参加していない状態に戻る
+			name:        "Owner",
+			userID:      10001,
+			wantOwner:   repository.ErrNotFound,
+			wantPartner: repository.ErrNotFound,
+		},
+		{
+			// パートナーが退会した場合、作成したユーザーのみの家計簿に戻る
+			name:        "Partner",
+			userID:      10002,
+			wantPartner: repository.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := test.LoadFixturesAt(db.Pool, "_fixtures"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := households.Accept(context.Background(), invitationTokenHash(), 10002, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := r.DeleteByID(context.Background(), tt.userID); err != nil {
+				t.Fatalf("err should be nil, but got %q", err)
+			}
+
+			owned, err := households.FindByUserID(context.Background(), 10001)
+			if errors.Cause(err) != tt.wantOwner {
+				t.Fatalf("FindByUserID() owner unexpected error:\nwant: %v\ngot : %v", tt.wantOwner, err)
+			}
+			if tt.wantOwner == nil && owned.PartnerID != tt.wantPartnerID {
+				t.Errorf("FindByUserID() owner partner_id = %d, want %d", owned.PartnerID, tt.wantPartnerID)
+			}
+			if _, err := households.FindByUserID(context.Background(), 10002); errors.Cause(err) != tt.wantPartner {
+				t.Errorf("FindByUserID() partner unexpected error:\nwant: %v\ngot : %v", tt.wantPartner, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+)
+
+type AuthUseCase interface {
+	Signup(req *SignupParam) (*Token, error)
+	Login(req *LoginParam) (*Token, error)
+	// Authenticate : トークンを検証し、ログイン中のユーザーを返す
+	Authenticate(token string) (*model.User, error)
+	// AuthorizeLedger : userIDのユーザーがledgerUserIDの家計簿に参加していない場合はForbiddenErrorを返す
+	AuthorizeLedger(ctx context.Context, userID, ledgerUserID int) error
+}
+
+func NewAuthUseCase(r repository.UserRepository, households repository.HouseholdRepository, secret []byte, ttl time.Duration) *authUsecase {
+	return &authUsecase{
+		UserRepository:      r,
+		HouseholdRepository: households,
+		secret:              secret,
+		ttl:                 ttl,
+	}
+}
+
+var _ AuthUseCase = &authUsecase{}
+
+type authUsecase struct {
+	UserRepository      repository.UserRepository
+	HouseholdRepository repository.HouseholdRepository
+	secret              []byte
+	ttl                 time.Duration
+}
+
+type SignupParam struct {
+	UserName     string `json:"user_name" validate:"required"`
+	PartnerName  string `json:"partner_name"`
+	Email        string `json:"email" validate:"required,email"`
+	Password     string `json:"password" validate:"required,min=8,max=72"`
+	UserImage    string `json:"user_image"`
+	PartnerImage string `json:"partner_image"`
+}
+
+type LoginParam struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type Token struct {
+	UserID    int       `json:"user_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+const (
+	defaultPartnerName = "パートナー"
+	defaultProportion  = 50
+)
+
+func (u *authUsecase) Signup(param *SignupParam) (*Token, error) {
+	if err := validateParam(param); err != nil {
+		return nil, err
+	}
+
+	_, err := u.UserRepository.FindByEmail(param.Email)
+	if err == nil {
+		return nil, ConflictError{}
+	}
+	if errors.Cause(err) != repository.ErrNotFound {
+		return nil, repositoryError(err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(param.Password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Println("failed to hash password")
+		return nil, InternalServerError{}
+	}
+
+	user := &model.User{
+		UserName:     param.UserName,
+		PartnerName:  param.PartnerName,
+		Email:        param.Email,
+		Password:     string(hash),
+		UserImage:    param.UserImage,
+		PartnerImage: param.PartnerImage,
+		Proportion:   defaultProportion,
+	}
+	if user.PartnerName == "" {
+		user.PartnerName = defaultPartnerName
+	}
+
+	// 確認後に同じメールアドレスで登録された場合は、リポジトリがErrConflictを返す
+	user, err = u.UserRepository.Create(user)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	return u.issue(user.ID), nil
+}
+
+func (u *authUsecase) Login(param *LoginParam) (*Token, error) {
+	if err := validateParam(param); err != nil {
+		return nil, err
+	}
+
+	user, err := u.UserRepository.FindByEmail(param.Email)
+	if err != nil {
+		if errors.Cause(err) == repository.ErrNotFound {
+			return nil, UnauthorizedError{}
+		}
+		log.Println("repository error")
+		return nil, InternalServerError{}
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(param.Password)); err != nil {
+		return nil, UnauthorizedError{}
+	}
+
+	return u.issue(user.ID), nil
+}
+
+func (u *authUsecase) Authenticate(token string) (*model.User, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, UnauthorizedError{}
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sig, u.sign(parts[0])) {
+		return nil, UnauthorizedError{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, UnauthorizedError{}
+	}
+
+	var c tokenClaims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, UnauthorizedError{}
+	}
+
+	if time.Now().Unix() >= c.ExpiresAt {
+		return nil, TokenExpiredError{}
+	}
+
+	// 退会済みのユーザーのトークンは無効とする
+	user, err := u.UserRepository.FindByID(c.UserID)
+	if err != nil {
+		if errors.Cause(err) == repository.ErrNotFound {
+			return nil, UnauthorizedError{}
+		}
+		log.Println("repository error")
+		return nil, InternalServerError{}
+	}
+
+	return user, nil
+}
+
+func (u *authUsecase) AuthorizeLedger(ctx context.Context, userID, ledgerUserID int) error {
+	if userID == ledgerUserID {
+		return nil
+	}
+
+	h, err := u.HouseholdRepository.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Cause(err) == repository.ErrNotFound {
+			return ForbiddenError{}
+		}
+		log.Println("repository error")
+		return InternalServerError{}
+	}
+
+	// パートナーとして参加している家計簿のみ扱える
+	if h.OwnerID != ledgerUserID || h.PartnerID != userID {
+		return ForbiddenError{}
+	}
+
+	return nil
+}
+
+type tokenClaims struct {
+	UserID    int   `json:"uid"`
+	ExpiresAt int64 `json:"exp"`
+}
+
+// issue : ユーザーIDと有効期限をHMAC-SHA256で署名したトークンを発行する
+func (u *authUsecase) issue(userID int) *Token {
+	expiresAt := time.Now().Add(u.ttl).Truncate(time.Second)
+
+	// tokenClaimsのjson化は失敗しない
+	payload, _ := json.Marshal(tokenClaims{UserID: userID, ExpiresAt: expiresAt.Unix()})
+	p := base64.RawURLEncoding.EncodeToString(payload)
+
+	return &Token{
+		UserID:    userID,
+		Token:     p + "." + base64.RawURLEncoding.EncodeToString(u.sign(p)),
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (u *authUsecase) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, u.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,364 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/usecase"
+)
+
+var testSecret = []byte("secret")
+
+func Test_authUsecase_Signup(t *testing.T) {
+	tests := []struct {
+		name       string
+		param      *usecase.SignupParam
+		findErr    error
+		createWant *model.User
+		createErr  error
+		wantErr    error
+	}{
+		{
+			name: "Success",
+			param: &usecase.SignupParam{
+				UserName: "ユーザー",
+				Email:    "test@example.com",
+				Password: "password1234",
+			},
+			findErr:    repository.ErrNotFound,
+			createWant: &model.User{ID: 1},
+		},
+		{
+			name: "InvalidParam error",
+			param: &usecase.SignupParam{
+				UserName: "ユーザー",
+				Email:    "invalid",
+				Password: "short",
+			},
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name: "Conflict error",
+			param: &usecase.SignupParam{
+				UserName: "ユーザー",
+				Email:    "test@example.com",
+				Password: "password1234",
+			},
+			wantErr: usecase.ConflictError{},
+		},
+		{
+			name: "Conflict error email registered concurrently",
+			param: &usecase.SignupParam{
+				UserName: "ユーザー",
+				Email:    "test@example.com",
+				Password: "password1234",
+			},
+			findErr:   repository.ErrNotFound,
+			createErr: repository.ErrConflict,
+			wantErr:   usecase.ConflictError{},
+		},
+		{
+			name: "Repository error",
+			param: &usecase.SignupParam{
+				UserName: "ユーザー",
+				Email:    "test@example.com",
+				Password: "password1234",
+			},
+			findErr:   repository.ErrNotFound,
+			createErr: errors.New("repository error"),
+			wantErr:   usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockUserRepository{}
+			m.On("FindByEmail", tt.param.Email).Return(&model.User{}, tt.findErr)
+			m.On("Create", mock.MatchedBy(func(u *model.User) bool {
+				return u.Email == tt.param.Email &&
+					u.PartnerName == "パートナー" &&
+					u.Proportion == 50 &&
+					bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(tt.param.Password)) == nil
+			})).Return(tt.createWant, tt.createErr)
+			m.On("FindByID", mock.Anything).Return(tt.createWant, nil)
+
+			u := usecase.NewAuthUseCase(m, &mockHouseholdRepository{}, testSecret, time.Hour)
+			got, err := u.Signup(tt.param)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			user, err := u.Authenticate(got.Token)
+			if err != nil || user.ID != tt.createWant.ID {
+				t.Errorf("Authenticate() = %v, %v, want ID %d", user, err, tt.createWant.ID)
+			}
+		})
+	}
+}
+
+func Test_authUsecase_Login(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password1234"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		param    *usecase.LoginParam
+		mockWant *model.User
+		mockErr  error
+		wantErr  error
+	}{
+		{
+			name:     "Success",
+			param:    &usecase.LoginParam{Email: "test@example.com", Password: "password1234"},
+			mockWant: &model.User{ID: 1, Password: string(hash)},
+		},
+		{
+			name:     "Unauthorized error wrong password",
+			param:    &usecase.LoginParam{Email: "test@example.com", Password: "password"},
+			mockWant: &model.User{ID: 1, Password: string(hash)},
+			wantErr:  usecase.UnauthorizedError{},
+		},
+		{
+			name:     "Unauthorized error unknown email",
+			param:    &usecase.LoginParam{Email: "unknown@example.com", Password: "password1234"},
+			mockWant: &model.User{},
+			mockErr:  repository.ErrNotFound,
+			wantErr:  usecase.UnauthorizedError{},
+		},
+		{
+			name:     "Repository error",
+			param:    &usecase.LoginParam{Email: "test@example.com", Password: "password1234"},
+			mockWant: &model.User{},
+			mockErr:  errors.New("repository error"),
+			wantErr:  usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockUserRepository{}
+			m.On("FindByEmail", tt.param.Email).Return(tt.mockWant, tt.mockErr)
+
+			u := usecase.NewAuthUseCase(m, &mockHouseholdRepository{}, testSecret, time.Hour)
+			got, err := u.Login(tt.param)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if got.UserID != tt.mockWant.ID {
+				t.Errorf("Login() user_id = %d, want %d", got.UserID, tt.mockWant.ID)
+			}
+		})
+	}
+}
+
+func Test_authUsecase_Authenticate(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password1234"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockUserRepository{}
+	m.On("FindByEmail", "test@example.com").Return(&model.User{ID: 1, Password: string(hash)}, nil)
+	m.On("FindByID", 1).Return(&model.User{ID: 1}, nil)
+	param := &usecase.LoginParam{Email: "test@example.com", Password: "password1234"}
+
+	valid, err := usecase.NewAuthUseCase(m, &mockHouseholdRepository{}, testSecret, time.Hour).Login(param)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := usecase.NewAuthUseCase(m, &mockHouseholdRepository{}, testSecret, -time.Hour).Login(param)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSecret, err := usecase.NewAuthUseCase(m, &mockHouseholdRepository{}, []byte("other"), time.Hour).Login(param)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deleted := &mockUserRepository{}
+	deleted.On("FindByEmail", "test@example.com").Return(&model.User{ID: 2, Password: string(hash)}, nil)
+	deleted.On("FindByID", 2).Return(&model.User{}, repository.ErrNotFound)
+	deletedUser, err := usecase.NewAuthUseCase(deleted, &mockHouseholdRepository{}, testSecret, time.Hour).Login(param)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		want    int
+		wantErr error
+	}{
+		{
+			name:  "Success",
+			token: valid.Token,
+			want:  1,
+		},
+		{
+			name:    "Unauthorized error deleted user",
+			token:   deletedUser.Token,
+			wantErr: usecase.UnauthorizedError{},
+		},
+		{
+			name:    "TokenExpired error",
+			token:   expired.Token,
+			wantErr: usecase.TokenExpiredError{},
+		},
+		{
+			name:    "Unauthorized error signed with other secret",
+			token:   otherSecret.Token,
+			wantErr: usecase.UnauthorizedError{},
+		},
+		{
+			name:    "Unauthorized error malformed",
+			token:   "malformed",
+			wantErr: usecase.UnauthorizedError{},
+		},
+	}
+
+	m.On("FindByID", 2).Return(&model.User{}, repository.ErrNotFound)
+	u := usecase.NewAuthUseCase(m, &mockHouseholdRepository{}, testSecret, time.Hour)
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := u.Authenticate(tt.token)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if got.ID != tt.want {
+				t.Errorf("Authenticate() ID = %d, want %d", got.ID, tt.want)
+			}
+		})
+	}
+}
+
+func Test_authUsecase_AuthorizeLedger(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       int
+		ledgerUserID int
+		mock         *model.Household
+		mockErr      error
+		wantErr      error
+	}{
+		{
+			name:         "Success own ledger",
+			userID:       1,
+			ledgerUserID: 1,
+		},
+		{
+			name:         "Success partner's ledger",
+			userID:       2,
+			ledgerUserID: 1,
+			mock:         &model.Household{ID: 1, OwnerID: 1, PartnerID: 2},
+		},
+		{
+			name:         "Forbidden error no household",
+			userID:       2,
+			ledgerUserID: 1,
+			mock:         &model.Household{},
+			mockErr:      repository.ErrNotFound,
+			wantErr:      usecase.ForbiddenError{},
+		},
+		{
+			name:         "Forbidden error other household",
+			userID:       2,
+			ledgerUserID: 3,
+			mock:         &model.Household{ID: 1, OwnerID: 1, PartnerID: 2},
+			wantErr:      usecase.ForbiddenError{},
+		},
+		{
+			name:         "Forbidden error owner cannot use partner's own ledger",
+			userID:       1,
+			ledgerUserID: 2,
+			mock:         &model.Household{ID: 1, OwnerID: 1, PartnerID: 2},
+			wantErr:      usecase.ForbiddenError{},
+		},
+		{
+			name:         "Repository error",
+			userID:       2,
+			ledgerUserID: 1,
+			mock:         &model.Household{},
+			mockErr:      errors.New("repository error"),
+			wantErr:      usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := &mockHouseholdRepository{}
+			h.On("FindByUserID", tt.userID).Return(tt.mock, tt.mockErr)
+
+			u := usecase.NewAuthUseCase(&mockUserRepository{}, h, testSecret, time.Hour)
+			err := u.AuthorizeLedger(context.Background(), tt.userID, tt.ledgerUserID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+)
+
+type CategoryUseCase interface {
+	GetData(userID int) ([]*Category, error)
+	Create(req *CreateCategoryParam, userID int) (*Category, error)
+	Update(req *UpdateCategoryParam, userID, categoryID int) (*Category, error)
+	DeleteByID(userID, categoryID int) error
+}
+
+func NewCategoryUseCase(r repository.CategoryRepository) *categoryUsecase {
+	return &categoryUsecase{r}
+}
+
+var _ CategoryUseCase = &categoryUsecase{}
+
+type categoryUsecase struct {
+	CategoryRepository repository.CategoryRepository
+}
+
+// Category : Customがtrueのカテゴリはユーザーが作成したもので、変更・削除できる
+type Category struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Custom   bool   `json:"custom"`
+	Archived bool   `json:"archived"`
+}
+
+type CreateCategoryParam struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// UpdateCategoryParam : 指定された項目のみ更新する。Archivedがtrueのカテゴリは新しい支払いの入力候補に表示しない
+type UpdateCategoryParam struct {
+	Name     *string `json:"name" validate:"omitempty,min=1"`
+	Archived *bool   `json:"archived"`
+}
+
+func (u *categoryUsecase) GetData(userID int) ([]*Category, error) {
+	c, err := u.CategoryRepository.GetData(userID)
+	if err != nil {
+		log.Println("repository error")
+		return nil, InternalServerError{}
+	}
+
+	categories := make([]*Category, 0, len(c))
+	for _, v := range c {
+		categories = append(categories, toCategory(v))
+	}
+
+	return categories, nil
+}
+
+func (u *categoryUsecase) Create(param *CreateCategoryParam, userID int) (*Category, error) {
+	if err := validateParam(param); err != nil {
+		return nil, err
+	}
+
+	category := &model.Category{
+		UserID: sql.NullInt64{Int64: int64(userID), Valid: true},
+		Name:   param.Name,
+	}
+
+	category, err := u.CategoryRepository.Create(category)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	return toCategory(category), nil
+}
+
+func (u *categoryUsecase) Update(param *UpdateCategoryParam, userID, categoryID int) (*Category, error) {
+	if err := validateParam(param); err != nil {
+		return nil, err
+	}
+
+	category, err := u.findByID(userID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if param.Name != nil {
+		category.Name = *param.Name
+	}
+	if param.Archived != nil {
+		category.Archived = *param.Archived
+	}
+
+	category, err = u.CategoryRepository.Update(category)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	return toCategory(category), nil
+}
+
+// DeleteByID : 支払いまたは固定費から参照されているカテゴリは削除せずConflictErrorを返す
+func (u *categoryUsecase) DeleteByID(userID, categoryID int) error {
+	if _, err := u.findByID(userID, categoryID); err != nil {
+		return err
+	}
+
+	referenced, err := u.CategoryRepository.IsReferenced(userID, categoryID)
+	if err != nil {
+		log.Println("repository error")
+		return InternalServerError{}
+	}
+	if referenced {
+		return ConflictError{}
+	}
+
+	err = u.CategoryRepository.DeleteByID(userID, categoryID)
+	if err != nil {
+		return repositoryError(err)
+	}
+	return nil
+}
+
+func (u *categoryUsecase) findByID(userID, categoryID int) (*model.Category, error) {
+	category, err := u.CategoryRepository.FindByID(userID, categoryID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	return category, nil
+}
+
+func toCategory(c *model.Category) *Category {
+	return &Category{
+		ID:       c.ID,
+		Name:     c.Name,
+		Custom:   c.UserID.Valid,
+		Archived: c.Archived,
+	}
+}
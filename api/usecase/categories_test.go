@@ -0,0 +1,342 @@
+package usecase_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_categoryUsecase_GetData(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int
+		mockWant []*model.Category
+		mockErr  error
+		want     []*usecase.Category
+		wantErr  error
+	}{
+		{
+			name:   "Success",
+			userID: 1,
+			mockWant: []*model.Category{
+				{ID: 1, Name: "家賃"},
+				{ID: 100, UserID: sql.NullInt64{Int64: 1, Valid: true}, Name: "ペット", Archived: true},
+			},
+			want: []*usecase.Category{
+				{ID: 1, Name: "家賃"},
+				{ID: 100, Name: "ペット", Custom: true, Archived: true},
+			},
+		},
+		{
+			name:     "Repository error",
+			userID:   1,
+			mockWant: []*model.Category{},
+			mockErr:  errors.New("repository error"),
+			wantErr:  usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockCategoryRepository{}
+			m.On("GetData", tt.userID).Return(tt.mockWant, tt.mockErr)
+
+			u := usecase.NewCategoryUseCase(m)
+			got, err := u.GetData(tt.userID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetData() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_categoryUsecase_Create(t *testing.T) {
+	tests := []struct {
+		name     string
+		param    *usecase.CreateCategoryParam
+		userID   int
+		mockWant *model.Category
+		mockErr  error
+		want     *usecase.Category
+		wantErr  error
+	}{
+		{
+			name:     "Success",
+			param:    &usecase.CreateCategoryParam{Name: "ペット"},
+			userID:   1,
+			mockWant: &model.Category{ID: 100, UserID: sql.NullInt64{Int64: 1, Valid: true}, Name: "ペット"},
+			want:     &usecase.Category{ID: 100, Name: "ペット", Custom: true},
+		},
+		{
+			name:    "Invalid param error name is empty",
+			param:   &usecase.CreateCategoryParam{},
+			userID:  1,
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name:     "Repository error",
+			param:    &usecase.CreateCategoryParam{Name: "ペット"},
+			userID:   1,
+			mockWant: &model.Category{},
+			mockErr:  errors.New("repository error"),
+			wantErr:  usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockCategoryRepository{}
+			m.On("Create", mock.MatchedBy(func(c *model.Category) bool {
+				return c.UserID.Int64 == int64(tt.userID) && c.UserID.Valid && c.Name == tt.param.Name
+			})).Return(tt.mockWant, tt.mockErr)
+
+			u := usecase.NewCategoryUseCase(m)
+			got, err := u.Create(tt.param, tt.userID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Create() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_categoryUsecase_Update(t *testing.T) {
+	name := "ペット用品"
+	archived := true
+	empty := ""
+
+	tests := []struct {
+		name       string
+		param      *usecase.UpdateCategoryParam
+		userID     int
+		categoryID int
+		findErr    error
+		updateArg  *model.Category
+		mockErr    error
+		want       *usecase.Category
+		wantErr    error
+	}{
+		{
+			name:       "Success rename",
+			param:      &usecase.UpdateCategoryParam{Name: &name},
+			userID:     1,
+			categoryID: 100,
+			updateArg:  &model.Category{ID: 100, UserID: sql.NullInt64{Int64: 1, Valid: true}, Name: "ペット用品"},
+			want:       &usecase.Category{ID: 100, Name: "ペット用品", Custom: true},
+		},
+		{
+			name:       "Success archive",
+			param:      &usecase.UpdateCategoryParam{Archived: &archived},
+			userID:     1,
+			categoryID: 100,
+			updateArg:  &model.Category{ID: 100, UserID: sql.NullInt64{Int64: 1, Valid: true}, Name: "ペット", Archived: true},
+			want:       &usecase.Category{ID: 100, Name: "ペット", Custom: true, Archived: true},
+		},
+		{
+			name:       "Invalid param error name is empty",
+			param:      &usecase.UpdateCategoryParam{Name: &empty},
+			userID:     1,
+			categoryID: 100,
+			wantErr:    usecase.InvalidParamError{},
+		},
+		{
+			name:       "NotFound error",
+			param:      &usecase.UpdateCategoryParam{Name: &name},
+			userID:     1,
+			categoryID: 1,
+			findErr:    repository.ErrNotFound,
+			wantErr:    usecase.NotFoundError{},
+		},
+		{
+			name:       "Repository error",
+			param:      &usecase.UpdateCategoryParam{Name: &name},
+			userID:     1,
+			categoryID: 100,
+			updateArg:  &model.Category{ID: 100, UserID: sql.NullInt64{Int64: 1, Valid: true}, Name: "ペット用品"},
+			mockErr:    errors.New("repository error"),
+			wantErr:    usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockCategoryRepository{}
+			m.On("FindByID", tt.userID, tt.categoryID).Return(&model.Category{ID: 100, UserID: sql.NullInt64{Int64: 1, Valid: true}, Name: "ペット"}, tt.findErr)
+			m.On("Update", tt.updateArg).Return(tt.updateArg, tt.mockErr)
+
+			u := usecase.NewCategoryUseCase(m)
+			got, err := u.Update(tt.param, tt.userID, tt.categoryID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Update() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_categoryUsecase_DeleteByID(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     int
+		categoryID int
+		findErr    error
+		referenced bool
+		mockErr    error
+		wantErr    error
+	}{
+		{
+			name:       "Success",
+			userID:     1,
+			categoryID: 100,
+		},
+		{
+			name:       "NotFound error",
+			userID:     1,
+			categoryID: 1,
+			findErr:    repository.ErrNotFound,
+			wantErr:    usecase.NotFoundError{},
+		},
+		{
+			name:       "Conflict error category is referenced",
+			userID:     1,
+			categoryID: 100,
+			referenced: true,
+			wantErr:    usecase.ConflictError{},
+		},
+		{
+			name:       "Conflict error category referenced concurrently",
+			userID:     1,
+			categoryID: 100,
+			mockErr:    repository.ErrConflict,
+			wantErr:    usecase.ConflictError{},
+		},
+		{
+			name:       "Repository error",
+			userID:     1,
+			categoryID: 100,
+			mockErr:    errors.New("repository error"),
+			wantErr:    usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockCategoryRepository{}
+			m.On("FindByID", tt.userID, tt.categoryID).Return(&model.Category{}, tt.findErr)
+			m.On("IsReferenced", tt.userID, tt.categoryID).Return(tt.referenced, nil)
+			m.On("DeleteByID", tt.userID, tt.categoryID).Return(tt.mockErr)
+
+			u := usecase.NewCategoryUseCase(m)
+			err := u.DeleteByID(tt.userID, tt.categoryID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+			}
+		})
+	}
+}
+
+type mockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *mockCategoryRepository) GetData(userID int) ([]*model.Category, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).([]*model.Category), ret.Error(1)
+}
+
+func (m *mockCategoryRepository) FindByID(userID, categoryID int) (*model.Category, error) {
+	ret := m.Called(userID, categoryID)
+	return ret.Get(0).(*model.Category), ret.Error(1)
+}
+
+func (m *mockCategoryRepository) Create(mc *model.Category) (*model.Category, error) {
+	ret := m.Called(mc)
+	return ret.Get(0).(*model.Category), ret.Error(1)
+}
+
+func (m *mockCategoryRepository) Update(mc *model.Category) (*model.Category, error) {
+	ret := m.Called(mc)
+	return ret.Get(0).(*model.Category), ret.Error(1)
+}
+
+func (m *mockCategoryRepository) DeleteByID(userID, categoryID int) error {
+	ret := m.Called(userID, categoryID)
+	return ret.Error(0)
+}
+
+func (m *mockCategoryRepository) IsReferenced(userID, categoryID int) (bool, error) {
+	ret := m.Called(userID, categoryID)
+	return ret.Bool(0), ret.Error(1)
+}
@@ -1,5 +1,13 @@
 package usecase
 
+import (
+	"log"
+
+	"github.com/pkg/errors"
+
+	"github.com/warikan/api/domain/repository"
+)
+
 type BadRequestError struct{}
 
 func (err BadRequestError) Error() string { return "Bad Request" }
@@ -8,10 +16,20 @@ type NotFoundError struct{}
 
 func (err NotFoundError) Error() string { return "Not Found" }
 
-type InvalidParamError struct{}
+// InvalidParamError : Fieldsには検証に失敗した項目を設定する。項目を特定できない場合は空
+type InvalidParamError struct {
+	Fields []FieldError
+}
 
 func (err InvalidParamError) Error() string { return "Invalid Parameter" }
 
+// FieldError : Fieldの値がRuleの検証に失敗した。ParamはRuleの引数で、max=100の場合は100
+type FieldError struct {
+	Field string
+	Rule  string
+	Param string
+}
+
 type InternalServerError struct{}
 
 func (err InternalServerError) Error() string { return "Internal Server Error" }
@@ -20,10 +38,21 @@ type UnauthorizedError struct{}
 
 func (_ UnauthorizedError) Error() string { return "Unauthorized" }
 
+type ForbiddenError struct{}
+
+func (ForbiddenError) Error() string { return "Forbidden" }
+
 type ConflictError struct{}
 
 func (ConflictError) Error() string { return "Conflict" }
 
+// UnprocessableEntityError : Fieldの値が存在しないデータを参照しているなど、リクエストの形式は正しいが処理できない
+type UnprocessableEntityError struct {
+	Field string
+}
+
+func (UnprocessableEntityError) Error() string { return "Unprocessable Entity" }
+
 type ServiceUnavailableError struct{}
 
 func (err ServiceUnavailableError) Error() string { return "Service Unavailable" }
@@ -47,3 +76,22 @@ func (_ SessionExpiredError) Error() string { return "session expired" }
 type MailAccountLimitError struct{}
 
 func (_ MailAccountLimitError) Error() string { return "Mail accounts limit error" }
+
+// repositoryError : リポジトリのエラーをHTTPステータスに対応するエラーに変換する
+func repositoryError(err error) error {
+	if e, ok := errors.Cause(err).(repository.InvalidFieldError); ok {
+		return UnprocessableEntityError{Field: e.Field}
+	}
+
+	switch errors.Cause(err) {
+	case repository.ErrConflict:
+		return ConflictError{}
+	case repository.ErrNotFound:
+		return NotFoundError{}
+	case repository.ErrForbidden:
+		return ForbiddenError{}
+	default:
+		log.Println("repository error")
+		return InternalServerError{}
+	}
+}
@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/usecase/util"
+)
+
+type FixedCostUseCase interface {
+	GetData(ctx context.Context, userID int) ([]*FixedCost, error)
+	Create(ctx context.Context, req *CreateFixedCostParam, userID int) (*model.FixedCost, error)
+	Update(ctx context.Context, req *UpdateFixedCostParam, userID int, fixedCostID int) (*model.FixedCost, error)
+	DeleteByID(ctx context.Context, userID, fixedCostID int) error
+	Materialize(ctx context.Context, yearMonth string) (int, error)
+}
+
+func NewFixedCostUseCase(r repository.FixedCostRepository, tx repository.TxManager) *fixedCostUsecase {
+	return &fixedCostUsecase{r, tx}
+}
+
+var _ FixedCostUseCase = &fixedCostUsecase{}
+
+type fixedCostUsecase struct {
+	FixedCostRepository repository.FixedCostRepository
+	TxManager           repository.TxManager
+}
+
+type FixedCost struct {
+	ID           int            `json:"id"`
+	CategoryID   int            `json:"category_id"`
+	CategoryName string         `json:"category_name"`
+	PayerID      int            `json:"payer_id"`
+	PayerName    string         `json:"payer_name"`
+	Description  sql.NullString `json:"description"`
+	PaymentDate  string         `json:"payment_date"`
+	Payment      int            `json:"payment"`
+	CreatedAt    string         `json:"created_at"`
+}
+
+type CreateFixedCostParam struct {
+	CategoryID  int            `json:"category_id" validate:"required"`
+	PayerID     int            `json:"payer_id" validate:"required"`
+	Description sql.NullString `json:"description"`
+	PaymentDate time.Time      `json:"payment_date" validate:"required"`
+	Payment     int            `json:"payment" validate:"required,gt=0,max=10000000"`
+}
+
+type UpdateFixedCostParam struct {
+	CategoryID  int            `json:"category_id" validate:"required"`
+	PayerID     int            `json:"payer_id" validate:"required"`
+	Description sql.NullString `json:"description"`
+	PaymentDate time.Time      `json:"payment_date" validate:"required"`
+	Payment     int            `json:"payment" validate:"required,gt=0,max=10000000"`
+	// Propagate : trueの場合、作成済みの今後の支払いにも支払日の日付を含めて変更を反映する
+	Propagate bool `json:"propagate"`
+	// UpdatedBy : 更新したユーザー。反映して承認待ちに戻した支払いはこのユーザーが登録したものになる。0の場合は記録しない
+	UpdatedBy int `json:"-"`
+}
+
+func (u *fixedCostUsecase) GetData(ctx context.Context, userID int) ([]*FixedCost, error) {
+	f, err := u.FixedCostRepository.GetData(ctx, userID)
+	if err != nil {
+		log.Println("internal server error")
+		return nil, InternalServerError{}
+	}
+
+	fixedCosts := make([]*FixedCost, 0, len(f))
+	for _, v := range f {
+		res := &FixedCost{
+			ID:           v.ID,
+			CategoryID:   v.CategoryID,
+			CategoryName: v.CategoryName,
+			PayerID:      v.PayerID,
+			PayerName:    v.PayerName,
+			Description:  v.Description,
+			PaymentDate:  util.ConvertJSTStringDate(v.PaymentDate),
+			Payment:      v.Payment,
+			CreatedAt:    util.ConvertJSTStringTime(v.CreatedAt),
+		}
+		fixedCosts = append(fixedCosts, res)
+	}
+
+	return fixedCosts, nil
+}
+
+func (u *fixedCostUsecase) Create(ctx context.Context, param *CreateFixedCostParam, userID int) (*model.FixedCost, error) {
+	err := validateParam(param)
+	if err != nil {
+		return nil, err
+	}
+
+	fixedCost := &model.FixedCost{
+		UserID:      userID,
+		CategoryID:  param.CategoryID,
+		PayerID:     param.PayerID,
+		Description: param.Description,
+		PaymentDate: param.PaymentDate,
+		Payment:     param.Payment,
+	}
+
+	fixedCost, err = u.FixedCostRepository.Create(ctx, fixedCost)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	return fixedCost, nil
+}
+
+func (u *fixedCostUsecase) Update(ctx context.Context, param *UpdateFixedCostParam, userID, fixedCostID int) (*model.FixedCost, error) {
+	err := validateParam(param)
+	if err != nil {
+		return nil, err
+	}
+
+	fixedCost := &model.FixedCost{
+		ID:          fixedCostID,
+		UserID:      userID,
+		CategoryID:  param.CategoryID,
+		PayerID:     param.PayerID,
+		Description: param.Description,
+		PaymentDate: param.PaymentDate,
+		Payment:     param.Payment,
+	}
+
+	// 固定費の更新と今後の支払いへの反映は両方成功した場合のみ確定する
+	err = u.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		fixedCost, err = u.FixedCostRepository.Update(ctx, fixedCost)
+		if err != nil {
+			return err
+		}
+
+		if param.Propagate {
+			_, err = u.FixedCostRepository.PropagateToPayments(ctx, fixedCost, time.Now(), param.UpdatedBy)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	return fixedCost, nil
+}
+
+func (u *fixedCostUsecase) DeleteByID(ctx context.Context, userID, fixedCostID int) error {
+	err := u.FixedCostRepository.DeleteByID(ctx, userID, fixedCostID)
+	if err != nil {
+		return repositoryError(err)
+	}
+	return nil
+}
+
+// Materialize : 全ての固定費から指定月の支払いを1つのトランザクションで作成し、作成件数を返す。作成済みの固定費はスキップするため何度実行してもよい
+func (u *fixedCostUsecase) Materialize(ctx context.Context, yearMonth string) (int, error) {
+	month, err := util.ParseJSTYearMonth(yearMonth)
+	if err != nil {
+		log.Println("invalid year month")
+		return 0, InvalidParamError{}
+	}
+
+	count := 0
+	err = u.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		f, err := u.FixedCostRepository.GetAll(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range f {
+			paymentDate, ok := occurrence(v.PaymentDate, month)
+			if !ok {
+				continue
+			}
+
+			created, err := u.FixedCostRepository.CreatePayment(ctx, v, paymentDate)
+			if err != nil {
+				return err
+			}
+			if created {
+				count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println("repository error")
+		return 0, InternalServerError{}
+	}
+
+	return count, nil
+}
+
+// occurrence : 固定費の支払日と同じ日付の指定月の日付を返す。月末を超える場合は月末日とし、固定費の開始月より前の月はfalseを返す
+func occurrence(start, month time.Time) (time.Time, bool) {
+	start = util.JST(start)
+	if start.Year()*12+int(start.Month()) > month.Year()*12+int(month.Month()) {
+		return time.Time{}, false
+	}
+
+	day := start.Day()
+	if last := month.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+
+	return time.Date(month.Year(), month.Month(), day, 0, 0, 0, 0, month.Location()), true
+}
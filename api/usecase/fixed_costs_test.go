@@ -0,0 +1,512 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_fixedCostUsecase_GetData(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int
+		mockWant []*model.FixedCost
+		mockErr  error
+		want     []*usecase.FixedCost
+		wantErr  error
+	}{
+		{
+			name:   "Success",
+			userID: 1,
+			mockWant: []*model.FixedCost{
+				{
+					ID:           1,
+					UserID:       1,
+					CategoryID:   1,
+					CategoryName: "家賃",
+					PayerID:      1,
+					PayerName:    "ユーザー",
+					Description:  sql.NullString{String: "家賃", Valid: true},
+					PaymentDate:  time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+					Payment:      80000,
+					CreatedAt:    time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			want: []*usecase.FixedCost{
+				{
+					ID:           1,
+					CategoryID:   1,
+					CategoryName: "家賃",
+					PayerID:      1,
+					PayerName:    "ユーザー",
+					Description:  sql.NullString{String: "家賃", Valid: true},
+					PaymentDate:  "2020-04-25",
+					Payment:      80000,
+					CreatedAt:    "2020-04-01 09:00:00",
+				},
+			},
+		},
+		{
+			name:     "Repository error",
+			userID:   1,
+			mockWant: []*model.FixedCost{},
+			mockErr:  errors.New("repository error"),
+			wantErr:  usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockFixedCostRepository{}
+			m.On("GetData", tt.userID).Return(tt.mockWant, tt.mockErr)
+
+			u := usecase.NewFixedCostUseCase(m, &mockTxManager{})
+			got, err := u.GetData(context.Background(), tt.userID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetData() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_fixedCostUsecase_Create(t *testing.T) {
+	tests := []struct {
+		name    string
+		param   *usecase.CreateFixedCostParam
+		userID  int
+		mock    *model.FixedCost
+		mockErr error
+		want    *model.FixedCost
+		wantErr error
+	}{
+		{
+			name: "Success",
+			param: &usecase.CreateFixedCostParam{
+				CategoryID:  1,
+				PayerID:     1,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     80000,
+			},
+			userID: 1,
+			mock: &model.FixedCost{
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     1,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     80000,
+			},
+			want: &model.FixedCost{
+				ID:          1,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     1,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     80000,
+			},
+		},
+		{
+			name:    "InvalidParam error",
+			param:   &usecase.CreateFixedCostParam{},
+			userID:  1,
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name: "Repository error",
+			param: &usecase.CreateFixedCostParam{
+				CategoryID:  1,
+				PayerID:     1,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     80000,
+			},
+			userID: 1,
+			mock: &model.FixedCost{
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     1,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     80000,
+			},
+			mockErr: errors.New("repository error"),
+			wantErr: usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockFixedCostRepository{}
+			m.On("Create", tt.mock).Return(tt.want, tt.mockErr)
+
+			u := usecase.NewFixedCostUseCase(m, &mockTxManager{})
+			got, err := u.Create(context.Background(), tt.param, tt.userID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Create() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_fixedCostUsecase_Update(t *testing.T) {
+	tests := []struct {
+		name        string
+		param       *usecase.UpdateFixedCostParam
+		userID      int
+		fixedCostID int
+		mock        *model.FixedCost
+		mockErr     error
+		want        *model.FixedCost
+		propagated  int
+		wantErr     error
+	}{
+		{
+			name: "Success",
+			param: &usecase.UpdateFixedCostParam{
+				CategoryID:  1,
+				PayerID:     2,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     90000,
+			},
+			userID:      1,
+			fixedCostID: 1,
+			mock: &model.FixedCost{
+				ID:          1,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     2,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     90000,
+			},
+			want: &model.FixedCost{
+				ID:          1,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     2,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     90000,
+			},
+		},
+		{
+			name: "Success with propagation",
+			param: &usecase.UpdateFixedCostParam{
+				CategoryID:  1,
+				PayerID:     2,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     90000,
+				Propagate:   true,
+				UpdatedBy:   2,
+			},
+			userID:      1,
+			fixedCostID: 1,
+			mock: &model.FixedCost{
+				ID:          1,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     2,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     90000,
+			},
+			want: &model.FixedCost{
+				ID:          1,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     2,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     90000,
+			},
+			propagated: 2,
+		},
+		{
+			name:        "InvalidParam error",
+			param:       &usecase.UpdateFixedCostParam{},
+			userID:      1,
+			fixedCostID: 1,
+			wantErr:     usecase.InvalidParamError{},
+		},
+		{
+			name: "NotFound error",
+			param: &usecase.UpdateFixedCostParam{
+				CategoryID:  1,
+				PayerID:     2,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     90000,
+			},
+			userID:      1,
+			fixedCostID: 1,
+			mock: &model.FixedCost{
+				ID:          1,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     2,
+				PaymentDate: time.Date(2020, time.April, 25, 0, 0, 0, 0, time.UTC),
+				Payment:     90000,
+			},
+			mockErr: repository.ErrNotFound,
+			wantErr: usecase.NotFoundError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockFixedCostRepository{}
+			m.On("Update", tt.mock).Return(tt.want, tt.mockErr)
+			m.On("PropagateToPayments", tt.want, mock.Anything, tt.param.UpdatedBy).Return(tt.propagated, nil)
+
+			u := usecase.NewFixedCostUseCase(m, &mockTxManager{})
+			got, err := u.Update(context.Background(), tt.param, tt.userID, tt.fixedCostID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Update() mismatch (-want +got):\n%s", diff)
+			}
+			if tt.param.Propagate {
+				m.AssertCalled(t, "PropagateToPayments", tt.want, mock.Anything, tt.param.UpdatedBy)
+			} else {
+				m.AssertNotCalled(t, "PropagateToPayments", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func Test_fixedCostUsecase_DeleteByID(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      int
+		fixedCostID int
+		mockErr     error
+		wantErr     error
+	}{
+		{
+			name:        "Success",
+			userID:      1,
+			fixedCostID: 1,
+		},
+		{
+			name:        "NotFound error",
+			userID:      1,
+			fixedCostID: 1,
+			mockErr:     repository.ErrNotFound,
+			wantErr:     usecase.NotFoundError{},
+		},
+		{
+			name:        "Repository error",
+			userID:      1,
+			fixedCostID: 1,
+			mockErr:     errors.New("repository error"),
+			wantErr:     usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockFixedCostRepository{}
+			m.On("DeleteByID", tt.userID, tt.fixedCostID).Return(tt.mockErr)
+
+			u := usecase.NewFixedCostUseCase(m, &mockTxManager{})
+			err := u.DeleteByID(context.Background(), tt.userID, tt.fixedCostID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+			}
+		})
+	}
+}
+
+func Test_fixedCostUsecase_Materialize(t *testing.T) {
+	jst := time.FixedZone("Asia/Tokyo", 9*60*60)
+
+	rent := &model.FixedCost{ID: 1, UserID: 1, CategoryID: 1, PayerID: 1, PaymentDate: time.Date(2020, time.January, 31, 0, 0, 0, 0, jst), Payment: 80000}
+	gas := &model.FixedCost{ID: 2, UserID: 1, CategoryID: 2, PayerID: 2, PaymentDate: time.Date(2020, time.January, 9, 15, 0, 0, 0, time.UTC), Payment: 5000}
+	future := &model.FixedCost{ID: 3, UserID: 1, CategoryID: 2, PayerID: 2, PaymentDate: time.Date(2020, time.March, 1, 0, 0, 0, 0, jst), Payment: 3000}
+
+	tests := []struct {
+		name      string
+		yearMonth string
+		mockWant  []*model.FixedCost
+		mockErr   error
+		created   map[int]bool
+		wantDates map[int]time.Time
+		want      int
+		wantErr   error
+	}{
+		{
+			name:      "Success",
+			yearMonth: "2020-02",
+			mockWant:  []*model.FixedCost{rent, gas, future},
+			created:   map[int]bool{1: true, 2: false},
+			wantDates: map[int]time.Time{
+				// 月末を超える日付は月末日に丸める
+				1: time.Date(2020, time.February, 29, 0, 0, 0, 0, jst),
+				// 支払日はJSTで判定する
+				2: time.Date(2020, time.February, 10, 0, 0, 0, 0, jst),
+			},
+			want: 1,
+		},
+		{
+			name:      "InvalidParam error",
+			yearMonth: "2020/02",
+			wantErr:   usecase.InvalidParamError{},
+		},
+		{
+			name:      "Repository error",
+			yearMonth: "2020-02",
+			mockWant:  []*model.FixedCost{},
+			mockErr:   errors.New("repository error"),
+			wantErr:   usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockFixedCostRepository{}
+			m.On("GetAll").Return(tt.mockWant, tt.mockErr)
+			for _, f := range tt.mockWant {
+				if d, ok := tt.wantDates[f.ID]; ok {
+					m.On("CreatePayment", f, mock.MatchedBy(d.Equal)).Return(tt.created[f.ID], nil)
+				}
+			}
+
+			u := usecase.NewFixedCostUseCase(m, &mockTxManager{})
+			got, err := u.Materialize(context.Background(), tt.yearMonth)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Materialize() mismatch (-want +got):\n%s", diff)
+			}
+			m.AssertNumberOfCalls(t, "CreatePayment", len(tt.wantDates))
+		})
+	}
+}
+
+type mockFixedCostRepository struct {
+	mock.Mock
+}
+
+func (m *mockFixedCostRepository) GetData(ctx context.Context, userID int) ([]*model.FixedCost, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).([]*model.FixedCost), ret.Error(1)
+}
+
+func (m *mockFixedCostRepository) GetAll(ctx context.Context) ([]*model.FixedCost, error) {
+	ret := m.Called()
+	return ret.Get(0).([]*model.FixedCost), ret.Error(1)
+}
+
+func (m *mockFixedCostRepository) Create(ctx context.Context, mf *model.FixedCost) (*model.FixedCost, error) {
+	ret := m.Called(mf)
+	return ret.Get(0).(*model.FixedCost), ret.Error(1)
+}
+
+func (m *mockFixedCostRepository) Update(ctx context.Context, mf *model.FixedCost) (*model.FixedCost, error) {
+	ret := m.Called(mf)
+	return ret.Get(0).(*model.FixedCost), ret.Error(1)
+}
+
+func (m *mockFixedCostRepository) DeleteByID(ctx context.Context, userID, fixedCostID int) error {
+	ret := m.Called(userID, fixedCostID)
+	return ret.Error(0)
+}
+
+func (m *mockFixedCostRepository) CreatePayment(ctx context.Context, mf *model.FixedCost, paymentDate time.Time) (bool, error) {
+	ret := m.Called(mf, paymentDate)
+	return ret.Bool(0), ret.Error(1)
+}
+
+func (m *mockFixedCostRepository) PropagateToPayments(ctx context.Context, mf *model.FixedCost, from time.Time, updatedBy int) (int, error) {
+	ret := m.Called(mf, from, updatedBy)
+	return ret.Int(0), ret.Error(1)
+}
+
+// mockTxManager : トランザクションを使わずにfnを実行する
+type mockTxManager struct{}
+
+func (*mockTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,595 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/usecase/util"
+)
+
+type GroupUseCase interface {
+	GetData(ctx context.Context, userID int) ([]*Group, error)
+	// Get : メンバーを含めて返す
+	Get(ctx context.Context, userID, groupID int) (*Group, error)
+	Create(ctx context.Context, param *CreateGroupParam, userID int) (*Group, error)
+	DeleteByID(ctx context.Context, userID, groupID int) error
+	AddMember(ctx context.Context, param *CreateGroupMemberParam, userID, groupID int) (*GroupMember, error)
+	GetPayments(ctx context.Context, userID, groupID int) ([]*GroupPayment, error)
+	CreatePayment(ctx context.Context, param *CreateGroupPaymentParam, userID, groupID int) (*GroupPayment, error)
+	DeletePayment(ctx context.Context, userID, groupID, groupPaymentID int) error
+	// Settle : メンバーごとの支払額・負担額と、精算に必要な送金を返す
+	Settle(ctx context.Context, userID, groupID int) (*GroupSettlement, error)
+}
+
+func NewGroupUseCase(r repository.GroupRepository, tx repository.TxManager) *groupUsecase {
+	return &groupUsecase{r, tx}
+}
+
+var _ GroupUseCase = &groupUsecase{}
+
+type groupUsecase struct {
+	GroupRepository repository.GroupRepository
+	TxManager       repository.TxManager
+}
+
+// Group : 一覧ではMembersを返さない
+type Group struct {
+	ID        int            `json:"id"`
+	Name      string         `json:"name"`
+	Members   []*GroupMember `json:"members,omitempty"`
+	CreatedAt string         `json:"created_at"`
+}
+
+type GroupMember struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type GroupPayment struct {
+	ID            int                  `json:"id"`
+	PayerMemberID int                  `json:"payer_member_id"`
+	Description   sql.NullString       `json:"description"`
+	PaymentDate   string               `json:"payment_date"`
+	Payment       int                  `json:"payment"`
+	SplitType     string               `json:"split_type"`
+	Splits        []*GroupPaymentSplit `json:"splits"`
+}
+
+// GroupPaymentSplit : Amountは分け方とValueから計算したメンバーの負担額
+type GroupPaymentSplit struct {
+	MemberID int `json:"member_id"`
+	Value    int `json:"value"`
+	Amount   int `json:"amount"`
+}
+
+// CreateGroupParam : Membersはメンバーの名前。グループは2人以上で作成する
+type CreateGroupParam struct {
+	Name    string   `json:"name" validate:"required"`
+	Members []string `json:"members" validate:"required,min=2,unique,dive,required"`
+}
+
+type CreateGroupMemberParam struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// CreateGroupPaymentParam : SplitsのValueはSplitTypeがpercentageの場合は割合(%)で合計100、
+// sharesの場合は口数、exactの場合は負担額で合計がPaymentと一致する必要がある。equalの場合は使わない
+type CreateGroupPaymentParam struct {
+	PayerMemberID int                       `json:"payer_member_id" validate:"required"`
+	Description   sql.NullString            `json:"description"`
+	PaymentDate   time.Time                 `json:"payment_date" validate:"required,maxfuture=365"`
+	Payment       int                       `json:"payment" validate:"required,gt=0,max=10000000"`
+	SplitType     string                    `json:"split_type" validate:"required,oneof=equal percentage shares exact"`
+	Splits        []*GroupPaymentSplitParam `json:"splits" validate:"required,min=1,dive,required"`
+}
+
+type GroupPaymentSplitParam struct {
+	MemberID int `json:"member_id" validate:"required"`
+	Value    int `json:"value" validate:"min=0,max=10000000"`
+}
+
+// GroupSettlement : Balanceが正のメンバーは受け取り、負のメンバーは支払う。Transfersは精算に必要な最小の件数の送金。
+// ただし残高が0でないメンバーが多い場合は最小にせず、最大でメンバー数-1件になる
+type GroupSettlement struct {
+	GroupID   int              `json:"group_id"`
+	Total     int              `json:"total"`
+	Members   []*GroupBalance  `json:"members"`
+	Transfers []*GroupTransfer `json:"transfers"`
+}
+
+// GroupBalance : Balanceは支払額から負担額を引いた額
+type GroupBalance struct {
+	MemberID int    `json:"member_id"`
+	Name     string `json:"name"`
+	Paid     int    `json:"paid"`
+	Burden   int    `json:"burden"`
+	Balance  int    `json:"balance"`
+}
+
+// GroupTransfer : FromMemberIDのメンバーがToMemberIDのメンバーへAmountを送金する
+type GroupTransfer struct {
+	FromMemberID int `json:"from_member_id"`
+	ToMemberID   int `json:"to_member_id"`
+	Amount       int `json:"amount"`
+}
+
+func (u *groupUsecase) GetData(ctx context.Context, userID int) ([]*Group, error) {
+	g, err := u.GroupRepository.GetData(ctx, userID)
+	if err != nil {
+		log.Println("repository error")
+		return nil, InternalServerError{}
+	}
+
+	groups := make([]*Group, 0, len(g))
+	for _, v := range g {
+		groups = append(groups, toGroup(v, nil))
+	}
+
+	return groups, nil
+}
+
+func (u *groupUsecase) Get(ctx context.Context, userID, groupID int) (*Group, error) {
+	g, err := u.GroupRepository.FindByID(ctx, userID, groupID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	members, err := u.GroupRepository.GetMembers(ctx, groupID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	return toGroup(g, members), nil
+}
+
+func (u *groupUsecase) Create(ctx context.Context, param *CreateGroupParam, userID int) (*Group, error) {
+	if err := validateParam(param); err != nil {
+		return nil, err
+	}
+
+	var group *Group
+	err := u.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		g, err := u.GroupRepository.Create(ctx, &model.Group{
+			UserID: userID,
+			Name:   param.Name,
+		})
+		if err != nil {
+			return err
+		}
+
+		members := make([]*model.GroupMember, 0, len(param.Members))
+		for _, name := range param.Members {
+			m, err := u.GroupRepository.CreateMember(ctx, &model.GroupMember{
+				GroupID: g.ID,
+				Name:    name,
+			})
+			if err != nil {
+				return err
+			}
+			members = append(members, m)
+		}
+
+		group = toGroup(g, members)
+		return nil
+	})
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	return group, nil
+}
+
+func (u *groupUsecase) DeleteByID(ctx context.Context, userID, groupID int) error {
+	if err := u.GroupRepository.DeleteByID(ctx, userID, groupID); err != nil {
+		return repositoryError(err)
+	}
+	return nil
+}
+
+// AddMember : グループに同じ名前のメンバーがいる場合はConflictErrorを返す
+func (u *groupUsecase) AddMember(ctx context.Context, param *CreateGroupMemberParam, userID, groupID int) (*GroupMember, error) {
+	if err := validateParam(param); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.GroupRepository.FindByID(ctx, userID, groupID); err != nil {
+		return nil, repositoryError(err)
+	}
+
+	m, err := u.GroupRepository.CreateMember(ctx, &model.GroupMember{
+		GroupID: groupID,
+		Name:    param.Name,
+	})
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	return &GroupMember{ID: m.ID, Name: m.Name}, nil
+}
+
+func (u *groupUsecase) GetPayments(ctx context.Context, userID, groupID int) ([]*GroupPayment, error) {
+	if _, err := u.GroupRepository.FindByID(ctx, userID, groupID); err != nil {
+		return nil, repositoryError(err)
+	}
+
+	p, err := u.GroupRepository.GetPayments(ctx, groupID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	payments := make([]*GroupPayment, 0, len(p))
+	for _, v := range p {
+		payment, err := toGroupPayment(v)
+		if err != nil {
+			log.Println("invalid group payment splits")
+			return nil, InternalServerError{}
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, nil
+}
+
+// CreatePayment : 支払者・負担するメンバーがグループのメンバーでない場合はUnprocessableEntityErrorを返す
+func (u *groupUsecase) CreatePayment(ctx context.Context, param *CreateGroupPaymentParam, userID, groupID int) (*GroupPayment, error) {
+	if err := validateParam(param); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.GroupRepository.FindByID(ctx, userID, groupID); err != nil {
+		return nil, repositoryError(err)
+	}
+
+	members, err := u.GroupRepository.GetMembers(ctx, groupID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	memberIDs := make(map[int]bool, len(members))
+	for _, m := range members {
+		memberIDs[m.ID] = true
+	}
+
+	if !memberIDs[param.PayerMemberID] {
+		return nil, UnprocessableEntityError{Field: "payer_member_id"}
+	}
+
+	splits := make([]*model.GroupPaymentSplit, 0, len(param.Splits))
+	seen := map[int]bool{}
+	for _, s := range param.Splits {
+		if !memberIDs[s.MemberID] {
+			return nil, UnprocessableEntityError{Field: "member_id"}
+		}
+		if seen[s.MemberID] {
+			return nil, invalidParam("splits", "unique", "")
+		}
+		seen[s.MemberID] = true
+
+		value := s.Value
+		if param.SplitType == model.SplitEqual {
+			value = 0
+		}
+		splits = append(splits, &model.GroupPaymentSplit{MemberID: s.MemberID, Value: value})
+	}
+	// 端数を負担するメンバーが指定の順序によらず決まるよう、メンバーIDの順に保存する
+	sort.Slice(splits, func(i, j int) bool { return splits[i].MemberID < splits[j].MemberID })
+
+	if _, err := splitPayment(param.Payment, param.SplitType, splits); err != nil {
+		return nil, err
+	}
+
+	var payment *model.GroupPayment
+	err = u.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := u.GroupRepository.CreatePayment(ctx, &model.GroupPayment{
+			GroupID:       groupID,
+			PayerMemberID: param.PayerMemberID,
+			Description:   param.Description,
+			PaymentDate:   param.PaymentDate,
+			Payment:       param.Payment,
+			SplitType:     param.SplitType,
+			Splits:        splits,
+		})
+		if err != nil {
+			return err
+		}
+		payment = p
+		return nil
+	})
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	return toGroupPayment(payment)
+}
+
+func (u *groupUsecase) DeletePayment(ctx context.Context, userID, groupID, groupPaymentID int) error {
+	if _, err := u.GroupRepository.FindByID(ctx, userID, groupID); err != nil {
+		return repositoryError(err)
+	}
+
+	if err := u.GroupRepository.DeletePayment(ctx, groupID, groupPaymentID); err != nil {
+		return repositoryError(err)
+	}
+	return nil
+}
+
+func (u *groupUsecase) Settle(ctx context.Context, userID, groupID int) (*GroupSettlement, error) {
+	if _, err := u.GroupRepository.FindByID(ctx, userID, groupID); err != nil {
+		return nil, repositoryError(err)
+	}
+
+	members, err := u.GroupRepository.GetMembers(ctx, groupID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	payments, err := u.GroupRepository.GetPayments(ctx, groupID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	s := &GroupSettlement{
+		GroupID: groupID,
+		Members: make([]*GroupBalance, 0, len(members)),
+	}
+
+	balances := make(map[int]*GroupBalance, len(members))
+	for _, m := range members {
+		b := &GroupBalance{MemberID: m.ID, Name: m.Name}
+		balances[m.ID] = b
+		s.Members = append(s.Members, b)
+	}
+
+	for _, p := range payments {
+		amounts, err := splitPayment(p.Payment, p.SplitType, p.Splits)
+		if err != nil {
+			log.Println("invalid group payment splits")
+			return nil, InternalServerError{}
+		}
+
+		s.Total += p.Payment
+		balances[p.PayerMemberID].Paid += p.Payment
+		for i, split := range p.Splits {
+			balances[split.MemberID].Burden += amounts[i]
+		}
+	}
+
+	for _, b := range s.Members {
+		b.Balance = b.Paid - b.Burden
+	}
+	s.Transfers = groupTransfers(s.Members)
+
+	return s, nil
+}
+
+func toGroup(g *model.Group, members []*model.GroupMember) *Group {
+	group := &Group{
+		ID:        g.ID,
+		Name:      g.Name,
+		CreatedAt: util.ConvertJSTStringTime(g.CreatedAt),
+	}
+	if members != nil {
+		group.Members = make([]*GroupMember, 0, len(members))
+		for _, m := range members {
+			group.Members = append(group.Members, &GroupMember{ID: m.ID, Name: m.Name})
+		}
+	}
+	return group
+}
+
+func toGroupPayment(p *model.GroupPayment) (*GroupPayment, error) {
+	amounts, err := splitPayment(p.Payment, p.SplitType, p.Splits)
+	if err != nil {
+		return nil, err
+	}
+
+	payment := &GroupPayment{
+		ID:            p.ID,
+		PayerMemberID: p.PayerMemberID,
+		Description:   p.Description,
+		PaymentDate:   util.ConvertJSTStringDate(p.PaymentDate),
+		Payment:       p.Payment,
+		SplitType:     p.SplitType,
+		Splits:        make([]*GroupPaymentSplit, 0, len(p.Splits)),
+	}
+	for i, s := range p.Splits {
+		payment.Splits = append(payment.Splits, &GroupPaymentSplit{
+			MemberID: s.MemberID,
+			Value:    s.Value,
+			Amount:   amounts[i],
+		})
+	}
+	return payment, nil
+}
+
+// splitPayment : 支払額をsplitTypeの分け方で負担するメンバーに分け、splitsの順に負担額を返す。
+// 分け方に対してValueが正しくない場合はInvalidParamErrorを返す
+func splitPayment(payment int, splitType string, splits []*model.GroupPaymentSplit) ([]int, error) {
+	weights := make([]int, 0, len(splits))
+	sum := 0
+	for _, s := range splits {
+		weights = append(weights, s.Value)
+		sum += s.Value
+	}
+
+	switch splitType {
+	case model.SplitEqual:
+		for i := range weights {
+			weights[i] = 1
+		}
+	case model.SplitPercentage:
+		if sum != 100 {
+			return nil, invalidParam("splits", "sum", "100")
+		}
+	case model.SplitShares:
+		for _, w := range weights {
+			if w < 1 {
+				return nil, invalidParam("value", "min", "1")
+			}
+		}
+	case model.SplitExact:
+		if sum != payment {
+			return nil, invalidParam("splits", "sum", strconv.Itoa(payment))
+		}
+		return weights, nil
+	default:
+		return nil, invalidParam("split_type", "oneof", "equal percentage shares exact")
+	}
+
+	return allocate(payment, weights), nil
+}
+
+// allocate : totalをweightsの比で按分する。切り捨てで余った端数は、切り捨てた額の大きい順(同じ場合は先頭から)に1円ずつ負担する
+func allocate(total int, weights []int) []int {
+	sum := 0
+	for _, w := range weights {
+		sum += w
+	}
+
+	amounts := make([]int, len(weights))
+	if sum == 0 {
+		return amounts
+	}
+
+	remainders := make([]int, len(weights))
+	rest := total
+	for i, w := range weights {
+		amounts[i] = total * w / sum
+		remainders[i] = total * w % sum
+		rest -= amounts[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return remainders[order[i]] > remainders[order[j]] })
+
+	for i := 0; i < rest; i++ {
+		amounts[order[i]]++
+	}
+	return amounts
+}
+
+// maxExactTransferMembers : groupTransfersで送金の件数を最小にする、残高が0でないメンバー数の上限。
+// 組み合わせを全て調べるため、これを超える場合は全員をまとめて精算する
+const maxExactTransferMembers = 16
+
+// groupTransfers : 残高の合計が0になるメンバーの組にできるだけ多く分け、組ごとに精算する。
+// k人の組は送金k-1件で精算できるため、組の数が最も多い分け方で送金の件数が最小になる
+func groupTransfers(balances []*GroupBalance) []*GroupTransfer {
+	rest := make([]int, len(balances))
+	for i, b := range balances {
+		rest[i] = b.Balance
+	}
+
+	result := make([]*GroupTransfer, 0)
+	for _, members := range zeroSumGroups(rest) {
+		result = append(result, settleTransfers(balances, rest, members)...)
+	}
+	return result
+}
+
+// zeroSumGroups : 残高が0でないメンバーを、残高の合計が0になる組に分ける。組の数が最も多くなる分け方のうち、
+// 各組と組の並びはメンバーの順に並べて返す
+func zeroSumGroups(rest []int) [][]int {
+	idx := make([]int, 0, len(rest))
+	for i, v := range rest {
+		if v != 0 {
+			idx = append(idx, i)
+		}
+	}
+	if len(idx) == 0 {
+		return nil
+	}
+	if len(idx) > maxExactTransferMembers {
+		return [][]int{idx}
+	}
+
+	// dp[mask] : maskのメンバーを残高の合計が0になる組に分けたときの組の数の最大
+	n := len(idx)
+	full := 1<<uint(n) - 1
+	sum := make([]int, full+1)
+	dp := make([]int, full+1)
+	for mask := 1; mask <= full; mask++ {
+		for i := 0; i < n; i++ {
+			bit := 1 << uint(i)
+			if mask&bit == 0 {
+				continue
+			}
+			sum[mask] = sum[mask^bit] + rest[idx[i]]
+			break
+		}
+		for i := 0; i < n; i++ {
+			if bit := 1 << uint(i); mask&bit != 0 && dp[mask^bit] > dp[mask] {
+				dp[mask] = dp[mask^bit]
+			}
+		}
+		if sum[mask] == 0 {
+			dp[mask]++
+		}
+	}
+
+	// 全員から1人ずつ外していき、残りの合計が0になるたびに外したメンバーを1つの組にする
+	groups := make([][]int, 0, dp[full])
+	current := make([]int, 0)
+	for mask := full; mask != 0; {
+		inc := 0
+		if sum[mask] == 0 {
+			inc = 1
+		}
+		for i := 0; i < n; i++ {
+			if bit := 1 << uint(i); mask&bit != 0 && dp[mask^bit]+inc == dp[mask] {
+				current = append(current, idx[i])
+				mask ^= bit
+				break
+			}
+		}
+		if sum[mask] == 0 {
+			sort.Ints(current)
+			groups = append(groups, current)
+			current = make([]int, 0)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i][0] < groups[j][0] })
+	return groups
+}
+
+// settleTransfers : 組のうち残高が最も少ないメンバーから最も多いメンバーへ、どちらかの残高が0になるまで送金することを繰り返す。
+// 1回の送金で少なくとも1人の残高が0になるため、送金の件数は最大で組の人数-1になる
+func settleTransfers(balances []*GroupBalance, rest []int, members []int) []*GroupTransfer {
+	result := make([]*GroupTransfer, 0)
+	for {
+		from, to := -1, -1
+		for _, i := range members {
+			v := rest[i]
+			if v < 0 && (from < 0 || v < rest[from]) {
+				from = i
+			}
+			if v > 0 && (to < 0 || v > rest[to]) {
+				to = i
+			}
+		}
+		if from < 0 || to < 0 {
+			return result
+		}
+
+		amount := -rest[from]
+		if rest[to] < amount {
+			amount = rest[to]
+		}
+		rest[from] += amount
+		rest[to] -= amount
+
+		result = append(result, &GroupTransfer{
+			FromMemberID: balances[from].MemberID,
+			ToMemberID:   balances[to].MemberID,
+			Amount:       amount,
+		})
+	}
+}
@@ -0,0 +1,554 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_groupUsecase_Create(t *testing.T) {
+	createdAt := time.Date(2020, time.April, 1, 3, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		param     *usecase.CreateGroupParam
+		createErr error
+		want      *usecase.Group
+		wantErr   error
+	}{
+		{
+			name:  "Success",
+			param: &usecase.CreateGroupParam{Name: "旅行", Members: []string{"A", "B", "C"}},
+			want: &usecase.Group{
+				ID:   1,
+				Name: "旅行",
+				Members: []*usecase.GroupMember{
+					{ID: 11, Name: "A"},
+					{ID: 12, Name: "B"},
+					{ID: 13, Name: "C"},
+				},
+				CreatedAt: "2020-04-01 12:00:00",
+			},
+		},
+		{
+			name:    "InvalidParam error one member",
+			param:   &usecase.CreateGroupParam{Name: "旅行", Members: []string{"A"}},
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name:    "InvalidParam error duplicate member names",
+			param:   &usecase.CreateGroupParam{Name: "旅行", Members: []string{"A", "A"}},
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name:      "Internal server error",
+			param:     &usecase.CreateGroupParam{Name: "旅行", Members: []string{"A", "B"}},
+			createErr: errors.New("error"),
+			wantErr:   usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockGroupRepository{}
+			m.On("Create", &model.Group{UserID: 1, Name: "旅行"}).Return(&model.Group{ID: 1, UserID: 1, Name: "旅行", CreatedAt: createdAt}, tt.createErr)
+			for i, name := range []string{"A", "B", "C"} {
+				m.On("CreateMember", &model.GroupMember{GroupID: 1, Name: name}).Return(&model.GroupMember{ID: 11 + i, GroupID: 1, Name: name}, nil)
+			}
+
+			u := usecase.NewGroupUseCase(m, &mockTxManager{})
+			got, err := u.Create(context.Background(), tt.param, 1)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Create() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_groupUsecase_CreatePayment(t *testing.T) {
+	paymentDate := time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC)
+	members := []*model.GroupMember{
+		{ID: 1, GroupID: 1, Name: "A"},
+		{ID: 2, GroupID: 1, Name: "B"},
+		{ID: 3, GroupID: 1, Name: "C"},
+	}
+
+	tests := []struct {
+		name      string
+		param     *usecase.CreateGroupPaymentParam
+		findErr   error
+		wantSaved *model.GroupPayment
+		want      *usecase.GroupPayment
+		wantErr   error
+	}{
+		{
+			name: "Success percentage split rounds remainder to largest fraction",
+			param: &usecase.CreateGroupPaymentParam{
+				PayerMemberID: 1,
+				PaymentDate:   paymentDate,
+				Payment:       1001,
+				SplitType:     model.SplitPercentage,
+				Splits: []*usecase.GroupPaymentSplitParam{
+					{MemberID: 3, Value: 34},
+					{MemberID: 1, Value: 33},
+					{MemberID: 2, Value: 33},
+				},
+			},
+			wantSaved: &model.GroupPayment{
+				GroupID:       1,
+				PayerMemberID: 1,
+				PaymentDate:   paymentDate,
+				Payment:       1001,
+				SplitType:     model.SplitPercentage,
+				Splits: []*model.GroupPaymentSplit{
+					{MemberID: 1, Value: 33},
+					{MemberID: 2, Value: 33},
+					{MemberID: 3, Value: 34},
+				},
+			},
+			want: &usecase.GroupPayment{
+				ID:            1,
+				PayerMemberID: 1,
+				PaymentDate:   "2020-04-01",
+				Payment:       1001,
+				SplitType:     model.SplitPercentage,
+				Splits: []*usecase.GroupPaymentSplit{
+					{MemberID: 1, Value: 33, Amount: 330},
+					{MemberID: 2, Value: 33, Amount: 330},
+					{MemberID: 3, Value: 34, Amount: 341},
+				},
+			},
+		},
+		{
+			name: "Success equal split among subset ignores value",
+			param: &usecase.CreateGroupPaymentParam{
+				PayerMemberID: 3,
+				Description:   sql.NullString{String: "タクシー", Valid: true},
+				PaymentDate:   paymentDate,
+				Payment:       100,
+				SplitType:     model.SplitEqual,
+				Splits: []*usecase.GroupPaymentSplitParam{
+					{MemberID: 2, Value: 5},
+					{MemberID: 3},
+				},
+			},
+			wantSaved: &model.GroupPayment{
+				GroupID:       1,
+				PayerMemberID: 3,
+				Description:   sql.NullString{String: "タクシー", Valid: true},
+				PaymentDate:   paymentDate,
+				Payment:       100,
+				SplitType:     model.SplitEqual,
+				Splits: []*model.GroupPaymentSplit{
+					{MemberID: 2},
+					{MemberID: 3},
+				},
+			},
+			want: &usecase.GroupPayment{
+				ID:            1,
+				PayerMemberID: 3,
+				Description:   sql.NullString{String: "タクシー", Valid: true},
+				PaymentDate:   "2020-04-01",
+				Payment:       100,
+				SplitType:     model.SplitEqual,
+				Splits: []*usecase.GroupPaymentSplit{
+					{MemberID: 2, Amount: 50},
+					{MemberID: 3, Amount: 50},
+				},
+			},
+		},
+		{
+			name: "InvalidParam error percentage does not total 100",
+			param: &usecase.CreateGroupPaymentParam{
+				PayerMemberID: 1,
+				PaymentDate:   paymentDate,
+				Payment:       1000,
+				SplitType:     model.SplitPercentage,
+				Splits: []*usecase.GroupPaymentSplitParam{
+					{MemberID: 1, Value: 50},
+					{MemberID: 2, Value: 40},
+				},
+			},
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name: "InvalidParam error exact amounts do not total payment",
+			param: &usecase.CreateGroupPaymentParam{
+				PayerMemberID: 1,
+				PaymentDate:   paymentDate,
+				Payment:       1000,
+				SplitType:     model.SplitExact,
+				Splits: []*usecase.GroupPaymentSplitParam{
+					{MemberID: 1, Value: 500},
+					{MemberID: 2, Value: 400},
+				},
+			},
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name: "InvalidParam error zero shares",
+			param: &usecase.CreateGroupPaymentParam{
+				PayerMemberID: 1,
+				PaymentDate:   paymentDate,
+				Payment:       1000,
+				SplitType:     model.SplitShares,
+				Splits: []*usecase.GroupPaymentSplitParam{
+					{MemberID: 1, Value: 2},
+					{MemberID: 2, Value: 0},
+				},
+			},
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name: "InvalidParam error duplicate member",
+			param: &usecase.CreateGroupPaymentParam{
+				PayerMemberID: 1,
+				PaymentDate:   paymentDate,
+				Payment:       1000,
+				SplitType:     model.SplitEqual,
+				Splits: []*usecase.GroupPaymentSplitParam{
+					{MemberID: 1},
+					{MemberID: 1},
+				},
+			},
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name: "InvalidParam error unknown split type",
+			param: &usecase.CreateGroupPaymentParam{
+				PayerMemberID: 1,
+				PaymentDate:   paymentDate,
+				Payment:       1000,
+				SplitType:     "ratio",
+				Splits: []*usecase.GroupPaymentSplitParam{
+					{MemberID: 1},
+				},
+			},
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name: "UnprocessableEntity error payer is not a member",
+			param: &usecase.CreateGroupPaymentParam{
+				PayerMemberID: 9,
+				PaymentDate:   paymentDate,
+				Payment:       1000,
+				SplitType:     model.SplitEqual,
+				Splits: []*usecase.GroupPaymentSplitParam{
+					{MemberID: 1},
+				},
+			},
+			wantErr: usecase.UnprocessableEntityError{},
+		},
+		{
+			name: "UnprocessableEntity error split member is not a member",
+			param: &usecase.CreateGroupPaymentParam{
+				PayerMemberID: 1,
+				PaymentDate:   paymentDate,
+				Payment:       1000,
+				SplitType:     model.SplitEqual,
+				Splits: []*usecase.GroupPaymentSplitParam{
+					{MemberID: 1},
+					{MemberID: 9},
+				},
+			},
+			wantErr: usecase.UnprocessableEntityError{},
+		},
+		{
+			name: "NotFound error other user's group",
+			param: &usecase.CreateGroupPaymentParam{
+				PayerMemberID: 1,
+				PaymentDate:   paymentDate,
+				Payment:       1000,
+				SplitType:     model.SplitEqual,
+				Splits: []*usecase.GroupPaymentSplitParam{
+					{MemberID: 1},
+				},
+			},
+			findErr: repository.ErrNotFound,
+			wantErr: usecase.NotFoundError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockGroupRepository{}
+			m.On("FindByID", 10, 1).Return(&model.Group{ID: 1, UserID: 10}, tt.findErr)
+			m.On("GetMembers", 1).Return(members, nil)
+			if tt.wantSaved != nil {
+				saved := *tt.wantSaved
+				saved.ID = 1
+				m.On("CreatePayment", tt.wantSaved).Return(&saved, nil)
+			}
+
+			u := usecase.NewGroupUseCase(m, &mockTxManager{})
+			got, err := u.CreatePayment(context.Background(), tt.param, 10, 1)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("CreatePayment() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_groupUsecase_Settle(t *testing.T) {
+	members := []*model.GroupMember{
+		{ID: 1, GroupID: 1, Name: "A"},
+		{ID: 2, GroupID: 1, Name: "B"},
+		{ID: 3, GroupID: 1, Name: "C"},
+	}
+
+	tests := []struct {
+		name     string
+		payments []*model.GroupPayment
+		findErr  error
+		want     *usecase.GroupSettlement
+		wantErr  error
+	}{
+		{
+			name: "Success all split types",
+			payments: []*model.GroupPayment{
+				{
+					ID: 1, PayerMemberID: 1, Payment: 3000, SplitType: model.SplitEqual,
+					Splits: []*model.GroupPaymentSplit{{MemberID: 1}, {MemberID: 2}, {MemberID: 3}},
+				},
+				{
+					ID: 2, PayerMemberID: 2, Payment: 1000, SplitType: model.SplitPercentage,
+					Splits: []*model.GroupPaymentSplit{{MemberID: 1, Value: 50}, {MemberID: 2, Value: 25}, {MemberID: 3, Value: 25}},
+				},
+				{
+					ID: 3, PayerMemberID: 3, Payment: 100, SplitType: model.SplitShares,
+					Splits: []*model.GroupPaymentSplit{{MemberID: 1, Value: 1}, {MemberID: 2, Value: 1}, {MemberID: 3, Value: 1}},
+				},
+				{
+					ID: 4, PayerMemberID: 1, Payment: 600, SplitType: model.SplitExact,
+					Splits: []*model.GroupPaymentSplit{{MemberID: 2, Value: 600}},
+				},
+			},
+			want: &usecase.GroupSettlement{
+				GroupID: 1,
+				Total:   4700,
+				Members: []*usecase.GroupBalance{
+					{MemberID: 1, Name: "A", Paid: 3600, Burden: 1534, Balance: 2066},
+					{MemberID: 2, Name: "B", Paid: 1000, Burden: 1883, Balance: -883},
+					{MemberID: 3, Name: "C", Paid: 100, Burden: 1283, Balance: -1183},
+				},
+				Transfers: []*usecase.GroupTransfer{
+					{FromMemberID: 3, ToMemberID: 1, Amount: 1183},
+					{FromMemberID: 2, ToMemberID: 1, Amount: 883},
+				},
+			},
+		},
+		{
+			name: "Success chained debts need fewer transfers than payments",
+			payments: []*model.GroupPayment{
+				{
+					ID: 1, PayerMemberID: 1, Payment: 900, SplitType: model.SplitExact,
+					Splits: []*model.GroupPaymentSplit{{MemberID: 2, Value: 900}},
+				},
+				{
+					ID: 2, PayerMemberID: 2, Payment: 900, SplitType: model.SplitExact,
+					Splits: []*model.GroupPaymentSplit{{MemberID: 3, Value: 900}},
+				},
+			},
+			want: &usecase.GroupSettlement{
+				GroupID: 1,
+				Total:   1800,
+				Members: []*usecase.GroupBalance{
+					{MemberID: 1, Name: "A", Paid: 900, Burden: 0, Balance: 900},
+					{MemberID: 2, Name: "B", Paid: 900, Burden: 900, Balance: 0},
+					{MemberID: 3, Name: "C", Paid: 0, Burden: 900, Balance: -900},
+				},
+				Transfers: []*usecase.GroupTransfer{
+					{FromMemberID: 3, ToMemberID: 1, Amount: 900},
+				},
+			},
+		},
+		{
+			name:     "Success no payments",
+			payments: []*model.GroupPayment{},
+			want: &usecase.GroupSettlement{
+				GroupID: 1,
+				Members: []*usecase.GroupBalance{
+					{MemberID: 1, Name: "A"},
+					{MemberID: 2, Name: "B"},
+					{MemberID: 3, Name: "C"},
+				},
+				Transfers: []*usecase.GroupTransfer{},
+			},
+		},
+		{
+			name:     "NotFound error",
+			payments: []*model.GroupPayment{},
+			findErr:  repository.ErrNotFound,
+			wantErr:  usecase.NotFoundError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockGroupRepository{}
+			m.On("FindByID", 10, 1).Return(&model.Group{ID: 1, UserID: 10}, tt.findErr)
+			m.On("GetMembers", 1).Return(members, nil)
+			m.On("GetPayments", 1).Return(tt.payments, nil)
+
+			u := usecase.NewGroupUseCase(m, &mockTxManager{})
+			got, err := u.Settle(context.Background(), 10, 1)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Settle() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_groupUsecase_Settle_ZeroSumSubgroups(t *testing.T) {
+	members := []*model.GroupMember{
+		{ID: 1, GroupID: 1, Name: "A"},
+		{ID: 2, GroupID: 1, Name: "B"},
+		{ID: 3, GroupID: 1, Name: "C"},
+		{ID: 4, GroupID: 1, Name: "D"},
+		{ID: 5, GroupID: 1, Name: "E"},
+	}
+	// B+500とC-500は2人だけで精算できるため、A・D・Eと分けて3件で精算する。
+	// 全員をまとめて残高の多い順に精算すると4件になる
+	payments := []*model.GroupPayment{
+		{
+			ID: 1, PayerMemberID: 1, Payment: 600, SplitType: model.SplitExact,
+			Splits: []*model.GroupPaymentSplit{{MemberID: 4, Value: 400}, {MemberID: 5, Value: 200}},
+		},
+		{
+			ID: 2, PayerMemberID: 2, Payment: 500, SplitType: model.SplitExact,
+			Splits: []*model.GroupPaymentSplit{{MemberID: 3, Value: 500}},
+		},
+	}
+
+	m := &mockGroupRepository{}
+	m.On("FindByID", 10, 1).Return(&model.Group{ID: 1, UserID: 10}, nil)
+	m.On("GetMembers", 1).Return(members, nil)
+	m.On("GetPayments", 1).Return(payments, nil)
+
+	u := usecase.NewGroupUseCase(m, &mockTxManager{})
+	got, err := u.Settle(context.Background(), 10, 1)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+
+	want := []*usecase.GroupTransfer{
+		{FromMemberID: 4, ToMemberID: 1, Amount: 400},
+		{FromMemberID: 5, ToMemberID: 1, Amount: 200},
+		{FromMemberID: 3, ToMemberID: 2, Amount: 500},
+	}
+	if diff := cmp.Diff(want, got.Transfers); diff != "" {
+		t.Errorf("Settle() mismatch transfers (-want +got):\n%s", diff)
+	}
+}
+
+type mockGroupRepository struct {
+	mock.Mock
+}
+
+func (m *mockGroupRepository) GetData(ctx context.Context, userID int) ([]*model.Group, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).([]*model.Group), ret.Error(1)
+}
+
+func (m *mockGroupRepository) FindByID(ctx context.Context, userID, groupID int) (*model.Group, error) {
+	ret := m.Called(userID, groupID)
+	return ret.Get(0).(*model.Group), ret.Error(1)
+}
+
+func (m *mockGroupRepository) Create(ctx context.Context, g *model.Group) (*model.Group, error) {
+	ret := m.Called(g)
+	return ret.Get(0).(*model.Group), ret.Error(1)
+}
+
+func (m *mockGroupRepository) DeleteByID(ctx context.Context, userID, groupID int) error {
+	ret := m.Called(userID, groupID)
+	return ret.Error(0)
+}
+
+func (m *mockGroupRepository) GetMembers(ctx context.Context, groupID int) ([]*model.GroupMember, error) {
+	ret := m.Called(groupID)
+	return ret.Get(0).([]*model.GroupMember), ret.Error(1)
+}
+
+func (m *mockGroupRepository) CreateMember(ctx context.Context, gm *model.GroupMember) (*model.GroupMember, error) {
+	ret := m.Called(gm)
+	return ret.Get(0).(*model.GroupMember), ret.Error(1)
+}
+
+func (m *mockGroupRepository) GetPayments(ctx context.Context, groupID int) ([]*model.GroupPayment, error) {
+	ret := m.Called(groupID)
+	return ret.Get(0).([]*model.GroupPayment), ret.Error(1)
+}
+
+func (m *mockGroupRepository) CreatePayment(ctx context.Context, p *model.GroupPayment) (*model.GroupPayment, error) {
+	ret := m.Called(p)
+	return ret.Get(0).(*model.GroupPayment), ret.Error(1)
+}
+
+func (m *mockGroupRepository) DeletePayment(ctx context.Context, groupID, groupPaymentID int) error {
+	ret := m.Called(groupID, groupPaymentID)
+	return ret.Error(0)
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+)
+
+type HouseholdUseCase interface {
+	// Get : userIDのユーザーが作成または参加している家計簿を返す
+	Get(ctx context.Context, userID int) (*Household, error)
+	// Invite : パートナーを招待するトークンを発行する。発行済みのトークンは無効になる
+	Invite(ctx context.Context, userID int) (*HouseholdInvitation, error)
+	// Accept : 招待トークンの家計簿にuserIDのユーザーをパートナーとして参加させる
+	Accept(ctx context.Context, param *AcceptHouseholdParam, userID int) (*Household, error)
+}
+
+func NewHouseholdUseCase(r repository.HouseholdRepository, tx repository.TxManager) *householdUsecase {
+	return &householdUsecase{r, tx}
+}
+
+var _ HouseholdUseCase = &householdUsecase{}
+
+type householdUsecase struct {
+	HouseholdRepository repository.HouseholdRepository
+	TxManager           repository.TxManager
+}
+
+// householdInvitationTTL : 招待トークンの有効期間
+const householdInvitationTTL = 7 * 24 * time.Hour
+
+// Household : LedgerUserIDは家計簿を作成したユーザーのID。支払いなどのパスの{user_id}にはこの値を指定する
+type Household struct {
+	ID           int                `json:"id"`
+	LedgerUserID int                `json:"ledger_user_id"`
+	Members      []*HouseholdMember `json:"members"`
+}
+
+// HouseholdMember : 家計簿に参加しているアカウントと、対応する支払者
+type HouseholdMember struct {
+	UserID  int `json:"user_id"`
+	PayerID int `json:"payer_id"`
+}
+
+// HouseholdInvitation : Tokenはパートナーに共有し、承認時に指定してもらう。再発行はできないため保存していない
+type HouseholdInvitation struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type AcceptHouseholdParam struct {
+	Token string `json:"token" validate:"required"`
+}
+
+func (u *householdUsecase) Get(ctx context.Context, userID int) (*Household, error) {
+	h, err := u.HouseholdRepository.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	return toHousehold(h), nil
+}
+
+func toHousehold(h *model.Household) *Household {
+	household := &Household{
+		ID:           h.ID,
+		LedgerUserID: h.OwnerID,
+		Members:      []*HouseholdMember{{UserID: h.OwnerID, PayerID: model.PayerUser}},
+	}
+	if h.PartnerID != 0 {
+		household.Members = append(household.Members, &HouseholdMember{UserID: h.PartnerID, PayerID: model.PayerPartner})
+	}
+	return household
+}
+
+// Invite : 他の家計簿に参加している場合や、パートナーが既に参加している場合はConflictErrorを返す
+func (u *householdUsecase) Invite(ctx context.Context, userID int) (*HouseholdInvitation, error) {
+	h, err := u.HouseholdRepository.FindByUserID(ctx, userID)
+	if err == nil && (h.OwnerID != userID || h.PartnerID != 0) {
+		return nil, ConflictError{}
+	}
+	if err != nil && errors.Cause(err) != repository.ErrNotFound {
+		return nil, repositoryError(err)
+	}
+
+	token, err := newInvitationToken()
+	if err != nil {
+		log.Println("failed to generate invitation token")
+		return nil, InternalServerError{}
+	}
+
+	expiresAt := time.Now().Add(householdInvitationTTL).Truncate(time.Second)
+	if _, err := u.HouseholdRepository.SaveInvitation(ctx, userID, hashInvitationToken(token), expiresAt); err != nil {
+		return nil, repositoryError(err)
+	}
+
+	return &HouseholdInvitation{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// Accept : 招待トークンが存在しない場合や有効期限切れの場合はNotFoundError、
+// パートナーが参加済みの家計簿に既に属している場合はConflictErrorを返す。招待中の自分の家計簿は削除する
+func (u *householdUsecase) Accept(ctx context.Context, param *AcceptHouseholdParam, userID int) (*Household, error) {
+	if err := validateParam(param); err != nil {
+		return nil, err
+	}
+
+	var household *model.Household
+	err := u.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		h, err := u.HouseholdRepository.FindByUserID(ctx, userID)
+		switch {
+		case err == nil && (h.OwnerID != userID || h.PartnerID != 0):
+			return errors.WithStack(repository.ErrConflict)
+		case err == nil:
+			if err := u.HouseholdRepository.DeleteByID(ctx, h.ID); err != nil {
+				return err
+			}
+		case errors.Cause(err) != repository.ErrNotFound:
+			return err
+		}
+
+		household, err = u.HouseholdRepository.Accept(ctx, hashInvitationToken(param.Token), userID, time.Now())
+		return err
+	})
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	return toHousehold(household), nil
+}
+
+// newInvitationToken : URLに含めて共有できる、推測できないトークンを生成する
+func newInvitationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashInvitationToken : データベースにはトークンのSHA-256を保存する
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,307 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_householdUsecase_Get(t *testing.T) {
+	tests := []struct {
+		name    string
+		mock    *model.Household
+		mockErr error
+		want    *usecase.Household
+		wantErr error
+	}{
+		{
+			name: "Success with partner",
+			mock: &model.Household{ID: 1, OwnerID: 10, PartnerID: 20},
+			want: &usecase.Household{
+				ID:           1,
+				LedgerUserID: 10,
+				Members: []*usecase.HouseholdMember{
+					{UserID: 10, PayerID: model.PayerUser},
+					{UserID: 20, PayerID: model.PayerPartner},
+				},
+			},
+		},
+		{
+			name: "Success invitation pending",
+			mock: &model.Household{ID: 1, OwnerID: 10},
+			want: &usecase.Household{
+				ID:           1,
+				LedgerUserID: 10,
+				Members: []*usecase.HouseholdMember{
+					{UserID: 10, PayerID: model.PayerUser},
+				},
+			},
+		},
+		{
+			name:    "NotFound error",
+			mock:    &model.Household{},
+			mockErr: repository.ErrNotFound,
+			wantErr: usecase.NotFoundError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockHouseholdRepository{}
+			m.On("FindByUserID", 10).Return(tt.mock, tt.mockErr)
+
+			u := usecase.NewHouseholdUseCase(m, &mockTxManager{})
+			got, err := u.Get(context.Background(), 10)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_householdUsecase_Invite(t *testing.T) {
+	tests := []struct {
+		name     string
+		find     *model.Household
+		findErr  error
+		saveErr  error
+		wantSave bool
+		wantErr  error
+	}{
+		{
+			name:     "Success create household",
+			find:     &model.Household{},
+			findErr:  repository.ErrNotFound,
+			wantSave: true,
+		},
+		{
+			name:     "Success reissue invitation",
+			find:     &model.Household{ID: 1, OwnerID: 10},
+			wantSave: true,
+		},
+		{
+			name:    "Conflict error partner already joined",
+			find:    &model.Household{ID: 1, OwnerID: 10, PartnerID: 20},
+			wantErr: usecase.ConflictError{},
+		},
+		{
+			name:    "Conflict error joined other household",
+			find:    &model.Household{ID: 1, OwnerID: 30, PartnerID: 10},
+			wantErr: usecase.ConflictError{},
+		},
+		{
+			name:     "Repository error",
+			find:     &model.Household{},
+			findErr:  repository.ErrNotFound,
+			saveErr:  errors.New("repository error"),
+			wantSave: true,
+			wantErr:  usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var savedHash string
+			m := &mockHouseholdRepository{}
+			m.On("FindByUserID", 10).Return(tt.find, tt.findErr)
+			m.On("SaveInvitation", 10, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				savedHash = args.String(1)
+			}).Return(&model.Household{ID: 1, OwnerID: 10}, tt.saveErr)
+
+			u := usecase.NewHouseholdUseCase(m, &mockTxManager{})
+			got, err := u.Invite(context.Background(), 10)
+
+			if saved := len(m.Calls) == 2; saved != tt.wantSave {
+				t.Errorf("Invite() saved invitation = %v, want %v", saved, tt.wantSave)
+			}
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			// トークン自体は保存せず、ハッシュのみ保存する
+			if got.Token == "" || savedHash == got.Token || savedHash != hashToken(got.Token) {
+				t.Errorf("Invite() token = %q, saved hash = %q", got.Token, savedHash)
+			}
+			if !got.ExpiresAt.After(time.Now().Add(6 * 24 * time.Hour)) {
+				t.Errorf("Invite() expires_at = %v, want about 7 days later", got.ExpiresAt)
+			}
+		})
+	}
+}
+
+func Test_householdUsecase_Accept(t *testing.T) {
+	tests := []struct {
+		name       string
+		param      *usecase.AcceptHouseholdParam
+		find       *model.Household
+		findErr    error
+		accept     *model.Household
+		acceptErr  error
+		wantDelete bool
+		want       *usecase.Household
+		wantErr    error
+	}{
+		{
+			name:    "Success",
+			param:   &usecase.AcceptHouseholdParam{Token: "token"},
+			find:    &model.Household{},
+			findErr: repository.ErrNotFound,
+			accept:  &model.Household{ID: 1, OwnerID: 10, PartnerID: 20},
+			want: &usecase.Household{
+				ID:           1,
+				LedgerUserID: 10,
+				Members: []*usecase.HouseholdMember{
+					{UserID: 10, PayerID: model.PayerUser},
+					{UserID: 20, PayerID: model.PayerPartner},
+				},
+			},
+		},
+		{
+			name:       "Success replaces own pending household",
+			param:      &usecase.AcceptHouseholdParam{Token: "token"},
+			find:       &model.Household{ID: 2, OwnerID: 20},
+			accept:     &model.Household{ID: 1, OwnerID: 10, PartnerID: 20},
+			wantDelete: true,
+			want: &usecase.Household{
+				ID:           1,
+				LedgerUserID: 10,
+				Members: []*usecase.HouseholdMember{
+					{UserID: 10, PayerID: model.PayerUser},
+					{UserID: 20, PayerID: model.PayerPartner},
+				},
+			},
+		},
+		{
+			name:    "InvalidParam error no token",
+			param:   &usecase.AcceptHouseholdParam{},
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name:    "Conflict error already joined",
+			param:   &usecase.AcceptHouseholdParam{Token: "token"},
+			find:    &model.Household{ID: 3, OwnerID: 30, PartnerID: 20},
+			wantErr: usecase.ConflictError{},
+		},
+		{
+			name:      "NotFound error invalid or expired token",
+			param:     &usecase.AcceptHouseholdParam{Token: "token"},
+			find:      &model.Household{},
+			findErr:   repository.ErrNotFound,
+			accept:    &model.Household{},
+			acceptErr: repository.ErrNotFound,
+			wantErr:   usecase.NotFoundError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockHouseholdRepository{}
+			m.On("FindByUserID", 20).Return(tt.find, tt.findErr)
+			m.On("DeleteByID", 2).Return(nil)
+			m.On("Accept", hashToken("token"), 20, mock.Anything).Return(tt.accept, tt.acceptErr)
+
+			u := usecase.NewHouseholdUseCase(m, &mockTxManager{})
+			got, err := u.Accept(context.Background(), tt.param, 20)
+
+			deleted := false
+			for _, c := range m.Calls {
+				deleted = deleted || c.Method == "DeleteByID"
+			}
+			if deleted != tt.wantDelete {
+				t.Errorf("Accept() deleted own household = %v, want %v", deleted, tt.wantDelete)
+			}
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Accept() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type mockHouseholdRepository struct {
+	mock.Mock
+}
+
+func (m *mockHouseholdRepository) FindByUserID(ctx context.Context, userID int) (*model.Household, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).(*model.Household), ret.Error(1)
+}
+
+func (m *mockHouseholdRepository) SaveInvitation(ctx context.Context, ownerID int, tokenHash string, expiresAt time.Time) (*model.Household, error) {
+	ret := m.Called(ownerID, tokenHash, expiresAt)
+	return ret.Get(0).(*model.Household), ret.Error(1)
+}
+
+func (m *mockHouseholdRepository) Accept(ctx context.Context, tokenHash string, partnerID int, now time.Time) (*model.Household, error) {
+	ret := m.Called(tokenHash, partnerID, now)
+	return ret.Get(0).(*model.Household), ret.Error(1)
+}
+
+func (m *mockHouseholdRepository) DeleteByID(ctx context.Context, householdID int) error {
+	ret := m.Called(householdID)
+	return ret.Error(0)
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"log"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+)
+
+type PayerUseCase interface {
+	GetData(userID int) ([]*model.Payer, error)
+}
+
+func NewPayerUseCase(r repository.PayerRepository) *payerUsecase {
+	return &payerUsecase{r}
+}
+
+var _ PayerUseCase = &payerUsecase{}
+
+type payerUsecase struct {
+	PayerRepository repository.PayerRepository
+}
+
+func (u *payerUsecase) GetData(userID int) ([]*model.Payer, error) {
+	payers, err := u.PayerRepository.GetData(userID)
+	if err != nil {
+		log.Println("repository error")
+		return nil, InternalServerError{}
+	}
+
+	// 退会済みなどでユーザーが存在しない場合は支払者も返らない
+	if len(payers) == 0 {
+		return nil, NotFoundError{}
+	}
+
+	return payers, nil
+}
@@ -0,0 +1,88 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_payerUsecase_GetData(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int
+		mockWant []*model.Payer
+		mockErr  error
+		want     []*model.Payer
+		wantErr  error
+	}{
+		{
+			name:   "Success",
+			userID: 1,
+			mockWant: []*model.Payer{
+				{ID: 1, Name: "たろう"},
+				{ID: 2, Name: "はなこ"},
+			},
+			want: []*model.Payer{
+				{ID: 1, Name: "たろう"},
+				{ID: 2, Name: "はなこ"},
+			},
+		},
+		{
+			name:     "NotFound error",
+			userID:   1,
+			mockWant: []*model.Payer{},
+			wantErr:  usecase.NotFoundError{},
+		},
+		{
+			name:     "Repository error",
+			userID:   1,
+			mockWant: []*model.Payer{},
+			mockErr:  errors.New("repository error"),
+			wantErr:  usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockPayerRepository{}
+			m.On("GetData", tt.userID).Return(tt.mockWant, tt.mockErr)
+
+			u := usecase.NewPayerUseCase(m)
+			got, err := u.GetData(tt.userID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetData() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type mockPayerRepository struct {
+	mock.Mock
+}
+
+func (m *mockPayerRepository) GetData(userID int) ([]*model.Payer, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).([]*model.Payer), ret.Error(1)
+}
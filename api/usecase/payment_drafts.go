@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/usecase/statement"
+	"github.com/warikan/api/usecase/util"
+)
+
+type PaymentDraftUseCase interface {
+	GetData(ctx context.Context, userID int) ([]*PaymentDraft, error)
+	// Import : 利用明細をformatの形式で読み込み、支出の明細を下書きとして登録する
+	Import(ctx context.Context, userID int, format string, r io.Reader) (*PaymentDraftImportResult, error)
+	// Confirm : 下書きから支払いを登録し、下書きを削除する
+	Confirm(ctx context.Context, param *ConfirmPaymentDraftParam, userID, paymentDraftID int) (*model.Payment, error)
+	DeleteByID(ctx context.Context, userID, paymentDraftID int) error
+}
+
+// NewPaymentDraftUseCase : csvFormatsに同じ名前の形式が複数ある場合は後のものを使う
+func NewPaymentDraftUseCase(draft repository.PaymentDraftRepository, payment repository.PaymentRepository, tx repository.TxManager, csvFormats []*statement.CSVFormat) *paymentDraftUsecase {
+	parsers := map[string]func(io.Reader) ([]*statement.Transaction, error){
+		statement.FormatOFX: statement.ParseOFX,
+		statement.FormatQIF: statement.ParseQIF,
+	}
+	for _, f := range csvFormats {
+		parsers[f.Name] = f.Parse
+	}
+	return &paymentDraftUsecase{draft, payment, tx, parsers}
+}
+
+var _ PaymentDraftUseCase = &paymentDraftUsecase{}
+
+type paymentDraftUsecase struct {
+	PaymentDraftRepository repository.PaymentDraftRepository
+	PaymentRepository      repository.PaymentRepository
+	TxManager              repository.TxManager
+	parsers                map[string]func(io.Reader) ([]*statement.Transaction, error)
+}
+
+type PaymentDraft struct {
+	ID          int            `json:"id"`
+	Source      string         `json:"source"`
+	Description sql.NullString `json:"description"`
+	PaymentDate string         `json:"payment_date"`
+	Payment     int            `json:"payment"`
+	CreatedAt   string         `json:"created_at"`
+}
+
+// PaymentDraftImportResult : Skippedは返金や入金など、支出でないため登録しなかった明細の件数。
+// Duplicatesは登録済みの下書きと重複するため登録しなかった明細の件数
+type PaymentDraftImportResult struct {
+	Drafts     []*PaymentDraft `json:"drafts"`
+	Skipped    int             `json:"skipped"`
+	Duplicates int             `json:"duplicates"`
+}
+
+// ConfirmPaymentDraftParam : Descriptionを指定した場合は下書きの内容の代わりに使う
+type ConfirmPaymentDraftParam struct {
+	CategoryID  int            `json:"category_id" validate:"required"`
+	PayerID     int            `json:"payer_id" validate:"required"`
+	Description sql.NullString `json:"description"`
+	// CreatedBy : 確定したユーザー。支払いを登録したユーザーとして記録する。0の場合は記録しない
+	CreatedBy int `json:"-"`
+}
+
+func (u *paymentDraftUsecase) GetData(ctx context.Context, userID int) ([]*PaymentDraft, error) {
+	d, err := u.PaymentDraftRepository.GetData(ctx, userID)
+	if err != nil {
+		log.Println("repository error")
+		return nil, InternalServerError{}
+	}
+
+	drafts := make([]*PaymentDraft, 0, len(d))
+	for _, v := range d {
+		drafts = append(drafts, toPaymentDraft(v))
+	}
+
+	return drafts, nil
+}
+
+func toPaymentDraft(d *model.PaymentDraft) *PaymentDraft {
+	return &PaymentDraft{
+		ID:          d.ID,
+		Source:      d.Source,
+		Description: d.Description,
+		PaymentDate: util.ConvertJSTStringDate(d.PaymentDate),
+		Payment:     d.Payment,
+		CreatedAt:   util.ConvertJSTStringTime(d.CreatedAt),
+	}
+}
+
+func (u *paymentDraftUsecase) Import(ctx context.Context, userID int, format string, r io.Reader) (*PaymentDraftImportResult, error) {
+	parse, ok := u.parsers[strings.ToLower(format)]
+	if !ok {
+		names := make([]string, 0, len(u.parsers))
+		for name := range u.parsers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, invalidParam("format", "oneof", strings.Join(names, " "))
+	}
+
+	txs, err := parse(r)
+	if err != nil {
+		log.Println("validation error")
+		return nil, invalidParam("file", "format", "")
+	}
+	if len(txs) > maxImportRows {
+		return nil, invalidParam("file", "max", strconv.Itoa(maxImportRows))
+	}
+
+	result := &PaymentDraftImportResult{Drafts: make([]*PaymentDraft, 0, len(txs))}
+	err = u.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		// 同じ明細を読み込み直した場合に備え、同じ内容の明細は登録済みの下書きの件数までを重複とみなす
+		existing := map[string]int{}
+		seen := map[string]int{}
+		for _, tx := range txs {
+			if tx.Amount <= 0 {
+				result.Skipped++
+				continue
+			}
+
+			md := &model.PaymentDraft{
+				UserID:      userID,
+				Source:      strings.ToLower(format),
+				Description: sql.NullString{String: tx.Description, Valid: tx.Description != ""},
+				PaymentDate: tx.Date,
+				Payment:     tx.Amount,
+			}
+			key := draftDuplicateKey(md)
+			n, ok := existing[key]
+			if !ok {
+				var err error
+				if n, err = u.PaymentDraftRepository.CountDuplicates(ctx, md); err != nil {
+					return err
+				}
+				existing[key] = n
+			}
+			if seen[key]++; seen[key] <= n {
+				result.Duplicates++
+				continue
+			}
+
+			d, err := u.PaymentDraftRepository.Create(ctx, md)
+			if err != nil {
+				return err
+			}
+			result.Drafts = append(result.Drafts, toPaymentDraft(d))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	return result, nil
+}
+
+func (u *paymentDraftUsecase) Confirm(ctx context.Context, param *ConfirmPaymentDraftParam, userID, paymentDraftID int) (*model.Payment, error) {
+	if err := validateParam(param); err != nil {
+		return nil, err
+	}
+
+	var payment *model.Payment
+	err := u.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		d, err := u.PaymentDraftRepository.FindByID(ctx, userID, paymentDraftID)
+		if err != nil {
+			return err
+		}
+
+		p := &CreatePaymentParam{
+			CategoryID:  param.CategoryID,
+			PayerID:     param.PayerID,
+			Description: d.Description,
+			PaymentDate: d.PaymentDate,
+			Payment:     d.Payment,
+		}
+		if param.Description.Valid {
+			p.Description = param.Description
+		}
+		// 明細の金額や日付が支払いとして登録できない場合は、支払いの登録と同じエラーを返す
+		if err := validateParam(p); err != nil {
+			return err
+		}
+
+		mp := &model.Payment{
+			UserID:      userID,
+			CategoryID:  p.CategoryID,
+			PayerID:     p.PayerID,
+			Description: p.Description,
+			PaymentDate: p.PaymentDate,
+			Payment:     p.Payment,
+		}
+		if param.CreatedBy != 0 {
+			mp.CreatedBy = sql.NullInt64{Int64: int64(param.CreatedBy), Valid: true}
+		}
+
+		payment, err = u.PaymentRepository.Create(ctx, mp)
+		if err != nil {
+			return err
+		}
+
+		return u.PaymentDraftRepository.DeleteByID(ctx, userID, paymentDraftID)
+	})
+	if e, ok := err.(InvalidParamError); ok {
+		return nil, e
+	}
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	return payment, nil
+}
+
+func (u *paymentDraftUsecase) DeleteByID(ctx context.Context, userID, paymentDraftID int) error {
+	if err := u.PaymentDraftRepository.DeleteByID(ctx, userID, paymentDraftID); err != nil {
+		return repositoryError(err)
+	}
+	return nil
+}
+
+// draftDuplicateKey : 支払日は時刻を含めずJSTの日付で比較する
+func draftDuplicateKey(d *model.PaymentDraft) string {
+	return fmt.Sprintf("%s_%d_%t_%s", util.ConvertJSTStringDate(d.PaymentDate), d.Payment, d.Description.Valid, d.Description.String)
+}
@@ -0,0 +1,387 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/usecase"
+	"github.com/warikan/api/usecase/statement"
+)
+
+func Test_paymentDraftUsecase_GetData(t *testing.T) {
+	tests := []struct {
+		name    string
+		mock    []*model.PaymentDraft
+		mockErr error
+		want    []*usecase.PaymentDraft
+		wantErr error
+	}{
+		{
+			name: "Success",
+			mock: []*model.PaymentDraft{
+				{ID: 1, UserID: 1, Source: "ofx", Description: sql.NullString{String: "スーパー", Valid: true}, PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, jst), Payment: 1234, CreatedAt: time.Date(2020, time.April, 2, 12, 0, 0, 0, jst)},
+			},
+			want: []*usecase.PaymentDraft{
+				{ID: 1, Source: "ofx", Description: sql.NullString{String: "スーパー", Valid: true}, PaymentDate: "2020-04-01", Payment: 1234, CreatedAt: "2020-04-02 12:00:00"},
+			},
+		},
+		{
+			name: "Success no drafts",
+			mock: []*model.PaymentDraft{},
+			want: []*usecase.PaymentDraft{},
+		},
+		{
+			name:    "Repository error",
+			mock:    []*model.PaymentDraft{},
+			mockErr: errors.New("repository error"),
+			wantErr: usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockPaymentDraftRepository{}
+			m.On("GetData", 1).Return(tt.mock, tt.mockErr)
+
+			u := usecase.NewPaymentDraftUseCase(m, &mockPaymentRepository{}, &mockTxManager{}, nil)
+			got, err := u.GetData(context.Background(), 1)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetData() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_paymentDraftUsecase_Import(t *testing.T) {
+	csvFormats := []*statement.CSVFormat{
+		{Name: "card", SkipRows: 1, DateColumn: 0, AmountColumn: 2, DescriptionColumn: 1},
+	}
+
+	tests := []struct {
+		name       string
+		format     string
+		body       string
+		existing   map[string]int
+		createErr  error
+		want       *usecase.PaymentDraftImportResult
+		wantCreate []*model.PaymentDraft
+		wantErr    error
+	}{
+		{
+			name:   "Success csv skips refunds",
+			format: "card",
+			body:   "利用日,利用店名,利用金額\n2020/04/01,スーパー,1234\n2020/04/02,返品,-500\n2020/04/03,,300\n",
+			want: &usecase.PaymentDraftImportResult{
+				Drafts: []*usecase.PaymentDraft{
+					{ID: 1, Source: "card", Description: sql.NullString{String: "スーパー", Valid: true}, PaymentDate: "2020-04-01", Payment: 1234, CreatedAt: "2020-04-10 00:00:00"},
+					{ID: 2, Source: "card", PaymentDate: "2020-04-03", Payment: 300, CreatedAt: "2020-04-10 00:00:00"},
+				},
+				Skipped: 1,
+			},
+			wantCreate: []*model.PaymentDraft{
+				{UserID: 1, Source: "card", Description: sql.NullString{String: "スーパー", Valid: true}, PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, jst), Payment: 1234},
+				{UserID: 1, Source: "card", PaymentDate: time.Date(2020, time.April, 3, 0, 0, 0, 0, jst), Payment: 300},
+			},
+		},
+		{
+			name:     "Success skips drafts already imported",
+			format:   "card",
+			body:     "利用日,利用店名,利用金額\n2020/04/01,スーパー,1234\n2020/04/01,スーパー,1234\n2020/04/02,スーパー,1234\n",
+			existing: map[string]int{"2020-04-01_スーパー": 1},
+			want: &usecase.PaymentDraftImportResult{
+				Drafts: []*usecase.PaymentDraft{
+					{ID: 1, Source: "card", Description: sql.NullString{String: "スーパー", Valid: true}, PaymentDate: "2020-04-01", Payment: 1234, CreatedAt: "2020-04-10 00:00:00"},
+					{ID: 2, Source: "card", Description: sql.NullString{String: "スーパー", Valid: true}, PaymentDate: "2020-04-02", Payment: 1234, CreatedAt: "2020-04-10 00:00:00"},
+				},
+				Duplicates: 1,
+			},
+			wantCreate: []*model.PaymentDraft{
+				{UserID: 1, Source: "card", Description: sql.NullString{String: "スーパー", Valid: true}, PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, jst), Payment: 1234},
+				{UserID: 1, Source: "card", Description: sql.NullString{String: "スーパー", Valid: true}, PaymentDate: time.Date(2020, time.April, 2, 0, 0, 0, 0, jst), Payment: 1234},
+			},
+		},
+		{
+			name:   "Success qif",
+			format: "QIF",
+			body:   "!Type:CCard\nD04/01/2020\nT-1234\nPスーパー\n^\n",
+			want: &usecase.PaymentDraftImportResult{
+				Drafts: []*usecase.PaymentDraft{
+					{ID: 1, Source: "qif", Description: sql.NullString{String: "スーパー", Valid: true}, PaymentDate: "2020-04-01", Payment: 1234, CreatedAt: "2020-04-10 00:00:00"},
+				},
+			},
+			wantCreate: []*model.PaymentDraft{
+				{UserID: 1, Source: "qif", Description: sql.NullString{String: "スーパー", Valid: true}, PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, jst), Payment: 1234},
+			},
+		},
+		{
+			name:    "Invalid param error unknown format",
+			format:  "xlsx",
+			wantErr: usecase.InvalidParamError{Fields: []usecase.FieldError{{Field: "format", Rule: "oneof", Param: "card ofx qif"}}},
+		},
+		{
+			name:    "Invalid param error malformed statement",
+			format:  "ofx",
+			body:    "not an ofx file",
+			wantErr: usecase.InvalidParamError{Fields: []usecase.FieldError{{Field: "file", Rule: "format"}}},
+		},
+		{
+			name:      "Repository error",
+			format:    "card",
+			body:      "利用日,利用店名,利用金額\n2020/04/01,スーパー,1234\n",
+			createErr: errors.New("repository error"),
+			wantErr:   usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockPaymentDraftRepository{}
+			m.On("CountDuplicates", mock.Anything).Return(func(d *model.PaymentDraft) int {
+				return tt.existing[d.PaymentDate.Format("2006-01-02")+"_"+d.Description.String]
+			}, nil)
+			var created []*model.PaymentDraft
+			m.On("Create", mock.Anything).Return(func(d *model.PaymentDraft) *model.PaymentDraft {
+				created = append(created, d)
+				res := *d
+				res.ID = len(created)
+				res.CreatedAt = time.Date(2020, time.April, 10, 0, 0, 0, 0, jst)
+				return &res
+			}, tt.createErr)
+
+			u := usecase.NewPaymentDraftUseCase(m, &mockPaymentRepository{}, &mockTxManager{}, csvFormats)
+			got, err := u.Import(context.Background(), 1, tt.format, strings.NewReader(tt.body))
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if diff := cmp.Diff(tt.wantErr, err); diff != "" {
+					t.Errorf("Import() error mismatch (-want +got):\n%s", diff)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Import() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantCreate, created); diff != "" {
+				t.Errorf("Import() created mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_paymentDraftUsecase_Confirm(t *testing.T) {
+	draft := func(payment int) *model.PaymentDraft {
+		return &model.PaymentDraft{
+			ID:          1,
+			UserID:      1,
+			Source:      "ofx",
+			Description: sql.NullString{String: "スーパー", Valid: true},
+			PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, jst),
+			Payment:     payment,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		param         *usecase.ConfirmPaymentDraftParam
+		draft         *model.PaymentDraft
+		findErr       error
+		createErr     error
+		wantPayment   *model.Payment
+		wantErr       error
+		wantNoPayment bool
+	}{
+		{
+			name:  "Success",
+			param: &usecase.ConfirmPaymentDraftParam{CategoryID: 2, PayerID: 1},
+			draft: draft(1234),
+			wantPayment: &model.Payment{
+				UserID:      1,
+				CategoryID:  2,
+				PayerID:     1,
+				Description: sql.NullString{String: "スーパー", Valid: true},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, jst),
+				Payment:     1234,
+			},
+		},
+		{
+			name:  "Success with description",
+			param: &usecase.ConfirmPaymentDraftParam{CategoryID: 2, PayerID: 1, Description: sql.NullString{String: "夕食の材料", Valid: true}},
+			draft: draft(1234),
+			wantPayment: &model.Payment{
+				UserID:      1,
+				CategoryID:  2,
+				PayerID:     1,
+				Description: sql.NullString{String: "夕食の材料", Valid: true},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, jst),
+				Payment:     1234,
+			},
+		},
+		{
+			name:          "Invalid param error",
+			param:         &usecase.ConfirmPaymentDraftParam{PayerID: 1},
+			draft:         draft(1234),
+			wantErr:       usecase.InvalidParamError{Fields: []usecase.FieldError{{Field: "category_id", Rule: "required"}}},
+			wantNoPayment: true,
+		},
+		{
+			name:          "Invalid param error amount exceeds payment limit",
+			param:         &usecase.ConfirmPaymentDraftParam{CategoryID: 2, PayerID: 1},
+			draft:         draft(20000000),
+			wantErr:       usecase.InvalidParamError{Fields: []usecase.FieldError{{Field: "payment", Rule: "max", Param: "10000000"}}},
+			wantNoPayment: true,
+		},
+		{
+			name:          "Not found error",
+			param:         &usecase.ConfirmPaymentDraftParam{CategoryID: 2, PayerID: 1},
+			findErr:       repository.ErrNotFound,
+			wantErr:       usecase.NotFoundError{},
+			wantNoPayment: true,
+		},
+		{
+			name:          "UnprocessableEntity error unknown category",
+			param:         &usecase.ConfirmPaymentDraftParam{CategoryID: 999, PayerID: 1},
+			draft:         draft(1234),
+			createErr:     repository.InvalidFieldError{Field: "category_id"},
+			wantErr:       usecase.UnprocessableEntityError{Field: "category_id"},
+			wantNoPayment: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			md := &mockPaymentDraftRepository{}
+			md.On("FindByID", 1, 1).Return(tt.draft, tt.findErr)
+			md.On("DeleteByID", 1, 1).Return(nil)
+			mp := &mockPaymentRepository{}
+			mp.On("Create", mock.Anything).Return(&model.Payment{ID: 100}, tt.createErr)
+
+			u := usecase.NewPaymentDraftUseCase(md, mp, &mockTxManager{}, nil)
+			got, err := u.Confirm(context.Background(), tt.param, 1, 1)
+			if tt.wantNoPayment {
+				md.AssertNotCalled(t, "DeleteByID", 1, 1)
+			}
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if diff := cmp.Diff(tt.wantErr, err); diff != "" {
+					t.Errorf("Confirm() error mismatch (-want +got):\n%s", diff)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(&model.Payment{ID: 100}, got); diff != "" {
+				t.Errorf("Confirm() mismatch (-want +got):\n%s", diff)
+			}
+			mp.AssertCalled(t, "Create", tt.wantPayment)
+			md.AssertCalled(t, "DeleteByID", 1, 1)
+		})
+	}
+}
+
+func Test_paymentDraftUsecase_DeleteByID(t *testing.T) {
+	tests := []struct {
+		name    string
+		mockErr error
+		wantErr error
+	}{
+		{name: "Success"},
+		{name: "Not found error", mockErr: repository.ErrNotFound, wantErr: usecase.NotFoundError{}},
+		{name: "Forbidden error", mockErr: repository.ErrForbidden, wantErr: usecase.ForbiddenError{}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockPaymentDraftRepository{}
+			m.On("DeleteByID", 1, 1).Return(tt.mockErr)
+
+			u := usecase.NewPaymentDraftUseCase(m, &mockPaymentRepository{}, &mockTxManager{}, nil)
+			err := u.DeleteByID(context.Background(), 1, 1)
+			if diff := cmp.Diff(tt.wantErr, err); diff != "" {
+				t.Errorf("DeleteByID() error mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type mockPaymentDraftRepository struct {
+	mock.Mock
+}
+
+func (m *mockPaymentDraftRepository) GetData(ctx context.Context, userID int) ([]*model.PaymentDraft, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).([]*model.PaymentDraft), ret.Error(1)
+}
+
+func (m *mockPaymentDraftRepository) FindByID(ctx context.Context, userID, paymentDraftID int) (*model.PaymentDraft, error) {
+	ret := m.Called(userID, paymentDraftID)
+	return ret.Get(0).(*model.PaymentDraft), ret.Error(1)
+}
+
+func (m *mockPaymentDraftRepository) Create(ctx context.Context, md *model.PaymentDraft) (*model.PaymentDraft, error) {
+	ret := m.Called(md)
+	if f, ok := ret.Get(0).(func(*model.PaymentDraft) *model.PaymentDraft); ok {
+		return f(md), ret.Error(1)
+	}
+	return ret.Get(0).(*model.PaymentDraft), ret.Error(1)
+}
+
+func (m *mockPaymentDraftRepository) CountDuplicates(ctx context.Context, md *model.PaymentDraft) (int, error) {
+	ret := m.Called(md)
+	if f, ok := ret.Get(0).(func(*model.PaymentDraft) int); ok {
+		return f(md), ret.Error(1)
+	}
+	return ret.Int(0), ret.Error(1)
+}
+
+func (m *mockPaymentDraftRepository) DeleteByID(ctx context.Context, userID, paymentDraftID int) error {
+	ret := m.Called(userID, paymentDraftID)
+	return ret.Error(0)
+}
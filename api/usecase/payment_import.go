@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/usecase/util"
+)
+
+type PaymentImportUseCase interface {
+	// Import : CSVの支払いを1つのトランザクションで取り込む。不正な行が1行でもある場合と、dryRunがtrueの場合は何も登録しない。
+	// createdByは取り込んだユーザーで、取り込んだ支払いはこのユーザーが登録したものになる。0の場合は記録しない
+	Import(ctx context.Context, userID, createdBy int, r io.Reader, dryRun bool) (*ImportResult, error)
+}
+
+func NewPaymentImportUseCase(payment repository.PaymentRepository, category repository.CategoryRepository, payer repository.PayerRepository, tx repository.TxManager) *paymentImportUsecase {
+	return &paymentImportUsecase{payment, category, payer, tx}
+}
+
+var _ PaymentImportUseCase = &paymentImportUsecase{}
+
+type paymentImportUsecase struct {
+	PaymentRepository  repository.PaymentRepository
+	CategoryRepository repository.CategoryRepository
+	PayerRepository    repository.PayerRepository
+	TxManager          repository.TxManager
+}
+
+// ImportResult : Rowsはヘッダーを除いた行数、Importedは登録した(dryRunの場合は登録する)件数、
+// Duplicatesは既存の支払いと重複するためスキップした行の番号
+type ImportResult struct {
+	DryRun     bool
+	Rows       int
+	Imported   int
+	Duplicates []int
+	Errors     []*ImportRowError
+}
+
+// ImportRowError : Rowはヘッダーを1行目とするCSVのレコードの番号
+type ImportRowError struct {
+	Row    int
+	Fields []FieldError
+}
+
+// maxImportRows : 1回に取り込める行数
+const maxImportRows = 10000
+
+// importColumns : CSVの見出しと取り込む項目の対応。見出しは大文字小文字を区別しない
+var importColumns = map[string]string{
+	"支払日":          "payment_date",
+	"日付":           "payment_date",
+	"date":         "payment_date",
+	"payment_date": "payment_date",
+	"カテゴリー":        "category_id",
+	"カテゴリ":         "category_id",
+	"category":     "category_id",
+	"支払者":          "payer_id",
+	"payer":        "payer_id",
+	"金額":           "payment",
+	"amount":       "payment",
+	"payment":      "payment",
+	"内容":           "description",
+	"メモ":           "description",
+	"description":  "description",
+}
+
+var requiredImportColumns = []string{"payment_date", "category_id", "payer_id", "payment"}
+
+// importRow : numberはImportRowError.Rowと同じレコードの番号
+type importRow struct {
+	number int
+	values map[string]string
+}
+
+func (u *paymentImportUsecase) Import(ctx context.Context, userID, createdBy int, r io.Reader, dryRun bool) (*ImportResult, error) {
+	rows, err := readImportCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := u.CategoryRepository.GetData(userID)
+	if err != nil {
+		log.Println("repository error")
+		return nil, InternalServerError{}
+	}
+	categoryIDs := make(map[string]int, len(categories))
+	for _, c := range categories {
+		if _, ok := categoryIDs[c.Name]; !ok {
+			categoryIDs[c.Name] = c.ID
+		}
+	}
+
+	payers, err := u.PayerRepository.GetData(userID)
+	if err != nil {
+		log.Println("repository error")
+		return nil, InternalServerError{}
+	}
+	payerIDs := make(map[string]int, len(payers))
+	for _, p := range payers {
+		payerIDs[p.Name] = p.ID
+	}
+
+	result := &ImportResult{DryRun: dryRun, Rows: len(rows)}
+	type validRow struct {
+		number  int
+		payment *model.Payment
+	}
+	valid := make([]validRow, 0, len(rows))
+	for _, row := range rows {
+		p, fields := importPayment(row, userID, categoryIDs, payerIDs)
+		if len(fields) > 0 {
+			result.Errors = append(result.Errors, &ImportRowError{Row: row.number, Fields: fields})
+			continue
+		}
+		if createdBy != 0 {
+			p.CreatedBy = sql.NullInt64{Int64: int64(createdBy), Valid: true}
+		}
+		valid = append(valid, validRow{row.number, p})
+	}
+	if len(result.Errors) > 0 {
+		log.Println("validation error")
+	}
+	insert := len(result.Errors) == 0 && !dryRun
+
+	err = u.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		// 同じ内容の行が複数ある場合は、既存の支払いの件数までを重複とみなす
+		existing := map[string]int{}
+		seen := map[string]int{}
+		for _, v := range valid {
+			key := duplicateKey(v.payment)
+			n, ok := existing[key]
+			if !ok {
+				var err error
+				if n, err = u.PaymentRepository.CountDuplicates(ctx, v.payment); err != nil {
+					return err
+				}
+				existing[key] = n
+			}
+			if seen[key]++; seen[key] <= n {
+				result.Duplicates = append(result.Duplicates, v.number)
+				continue
+			}
+
+			if insert {
+				if _, err := u.PaymentRepository.Create(ctx, v.payment); err != nil {
+					return err
+				}
+			}
+			result.Imported++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	if len(result.Errors) > 0 {
+		result.Imported = 0
+	}
+	return result, nil
+}
+
+// readImportCSV : 見出し行で列を判定してCSVを読み込む。Excelで保存したBOM付きのCSVも読み込める
+func readImportCSV(r io.Reader) ([]*importRow, error) {
+	br := bufio.NewReader(r)
+	if b, err := br.Peek(3); err == nil && string(b) == "\xEF\xBB\xBF" {
+		// nolint:errcheck
+		br.Discard(3)
+	}
+
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, invalidParam("file", "required", "")
+	}
+	if err != nil {
+		return nil, invalidParam("file", "format", "")
+	}
+
+	columns := map[string]int{}
+	for i, h := range header {
+		if c, ok := importColumns[strings.ToLower(strings.TrimSpace(h))]; ok {
+			columns[c] = i
+		}
+	}
+	for _, c := range requiredImportColumns {
+		if _, ok := columns[c]; !ok {
+			return nil, invalidParam(c, "required", "")
+		}
+	}
+
+	rows := make([]*importRow, 0)
+	for number := 2; ; number++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, invalidParam("file", "format", "")
+		}
+		if len(rows) == maxImportRows {
+			return nil, invalidParam("file", "max", strconv.Itoa(maxImportRows))
+		}
+
+		values := make(map[string]string, len(columns))
+		for c, i := range columns {
+			if i < len(record) {
+				values[c] = importText(strings.TrimSpace(record[i]))
+			}
+		}
+		rows = append(rows, &importRow{number: number, values: values})
+	}
+
+	return rows, nil
+}
+
+// importText : エクスポート時に数式の開始文字の前に付けた'を取り除く
+func importText(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(s[1])) {
+		return s[1:]
+	}
+	return s
+}
+
+// importPayment : 行の値をCreatePaymentParamと同じ規則で検証し、支払いに変換する
+func importPayment(row *importRow, userID int, categoryIDs, payerIDs map[string]int) (*model.Payment, []FieldError) {
+	var fields []FieldError
+	param := &CreatePaymentParam{}
+
+	if s := row.values["payment_date"]; s != "" {
+		d, err := util.ParseJSTSpreadsheetDate(s)
+		if err != nil {
+			fields = append(fields, FieldError{Field: "payment_date", Rule: "format", Param: "yyyy-MM-dd"})
+		}
+		param.PaymentDate = d
+	}
+	if s := row.values["category_id"]; s != "" {
+		id, ok := categoryIDs[s]
+		if !ok {
+			fields = append(fields, FieldError{Field: "category_id", Rule: "exists"})
+		}
+		param.CategoryID = id
+	}
+	if s := row.values["payer_id"]; s != "" {
+		id, ok := payerIDs[s]
+		if !ok {
+			fields = append(fields, FieldError{Field: "payer_id", Rule: "exists"})
+		}
+		param.PayerID = id
+	}
+	if s := row.values["payment"]; s != "" {
+		n, err := strconv.Atoi(strings.NewReplacer(",", "", "¥", "", "￥", "", "円", "").Replace(s))
+		if err != nil {
+			fields = append(fields, FieldError{Field: "payment", Rule: "format"})
+		}
+		param.Payment = n
+	}
+	if s := row.values["description"]; s != "" {
+		param.Description = sql.NullString{String: s, Valid: true}
+	}
+
+	// 変換に失敗した項目はゼロ値になるため、検証ルールのエラーは変換に成功した項目のみ返す
+	failed := map[string]bool{}
+	for _, f := range fields {
+		failed[f.Field] = true
+	}
+	for _, f := range fieldErrors(param) {
+		if !failed[f.Field] {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) > 0 {
+		return nil, fields
+	}
+
+	return &model.Payment{
+		UserID:      userID,
+		CategoryID:  param.CategoryID,
+		PayerID:     param.PayerID,
+		Description: param.Description,
+		PaymentDate: param.PaymentDate,
+		Payment:     param.Payment,
+	}, nil
+}
+
+// duplicateKey : 支払日は時刻を含めずJSTの日付で比較する
+func duplicateKey(p *model.Payment) string {
+	return fmt.Sprintf("%s_%d_%d_%d_%t_%s", util.ConvertJSTStringDate(p.PaymentDate), p.CategoryID, p.PayerID, p.Payment, p.Description.Valid, p.Description.String)
+}
@@ -0,0 +1,162 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_paymentImportUsecase_Import(t *testing.T) {
+	categories := []*model.Category{{ID: 1, Name: "食費"}, {ID: 2, Name: "日用品"}}
+	payers := []*model.Payer{{ID: 1, Name: "ユーザー"}, {ID: 2, Name: "パートナー"}}
+	payment := func(day, categoryID, payerID, amount int, description string) *model.Payment {
+		return &model.Payment{
+			UserID:      1,
+			CategoryID:  categoryID,
+			PayerID:     payerID,
+			Description: sql.NullString{String: description, Valid: description != ""},
+			PaymentDate: time.Date(2019, time.April, day, 0, 0, 0, 0, jst),
+			Payment:     amount,
+			CreatedBy:   sql.NullInt64{Int64: 2, Valid: true},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		csv        string
+		dryRun     bool
+		duplicates map[*model.Payment]int
+		createErr  error
+		want       *usecase.ImportResult
+		wantCreate []*model.Payment
+		wantErr    error
+	}{
+		{
+			name: "Success",
+			csv: "\xEF\xBB\xBF支払日,カテゴリー,支払者,金額,内容\n" +
+				"2019/4/1,食費,ユーザー,\"1,200\",スーパー\n" +
+				"2019-04-02,日用品,パートナー,¥500,\n",
+			want:       &usecase.ImportResult{Rows: 2, Imported: 2},
+			wantCreate: []*model.Payment{payment(1, 1, 1, 1200, "スーパー"), payment(2, 2, 2, 500, "")},
+		},
+		{
+			name: "Success english header in any order",
+			csv: "Amount,Date,Payer,Category\n" +
+				"300,2019-04-03,パートナー,食費\n",
+			want:       &usecase.ImportResult{Rows: 1, Imported: 1},
+			wantCreate: []*model.Payment{payment(3, 1, 2, 300, "")},
+		},
+		{
+			name: "Dry run",
+			csv: "支払日,カテゴリー,支払者,金額\n" +
+				"2019-04-01,食費,ユーザー,1200\n",
+			dryRun: true,
+			want:   &usecase.ImportResult{DryRun: true, Rows: 1, Imported: 1},
+		},
+		{
+			name: "Skip duplicates up to the existing count",
+			csv: "支払日,カテゴリー,支払者,金額\n" +
+				"2019-04-01,食費,ユーザー,1200\n" +
+				"2019-04-01,食費,ユーザー,1200\n" +
+				"2019-04-02,食費,ユーザー,1200\n",
+			duplicates: map[*model.Payment]int{payment(1, 1, 1, 1200, ""): 1},
+			want:       &usecase.ImportResult{Rows: 3, Imported: 2, Duplicates: []int{2}},
+			wantCreate: []*model.Payment{payment(1, 1, 1, 1200, ""), payment(2, 1, 1, 1200, "")},
+		},
+		{
+			name: "Row errors",
+			csv: "支払日,カテゴリー,支払者,金額\n" +
+				"2019-04-01,食費,ユーザー,1200\n" +
+				"2019-13-01,交通費,ユーザー,abc\n" +
+				",食費,,0\n",
+			want: &usecase.ImportResult{Rows: 3, Errors: []*usecase.ImportRowError{
+				{Row: 3, Fields: []usecase.FieldError{
+					{Field: "payment_date", Rule: "format", Param: "yyyy-MM-dd"},
+					{Field: "category_id", Rule: "exists"},
+					{Field: "payment", Rule: "format"},
+				}},
+				{Row: 4, Fields: []usecase.FieldError{
+					{Field: "payer_id", Rule: "required"},
+					{Field: "payment_date", Rule: "required"},
+					{Field: "payment", Rule: "required"},
+				}},
+			}},
+		},
+		{
+			name:    "Missing column",
+			csv:     "支払日,カテゴリー,金額\n2019-04-01,食費,1200\n",
+			wantErr: usecase.InvalidParamError{Fields: []usecase.FieldError{{Field: "payer_id", Rule: "required"}}},
+		},
+		{
+			name:    "Empty file",
+			csv:     "",
+			wantErr: usecase.InvalidParamError{Fields: []usecase.FieldError{{Field: "file", Rule: "required"}}},
+		},
+		{
+			name:    "Malformed csv",
+			csv:     "支払日,カテゴリー,支払者,金額\n\"2019-04-01,食費,ユーザー,1200\n",
+			wantErr: usecase.InvalidParamError{Fields: []usecase.FieldError{{Field: "file", Rule: "format"}}},
+		},
+		{
+			name: "Repository error",
+			csv: "支払日,カテゴリー,支払者,金額\n" +
+				"2019-04-01,食費,ユーザー,1200\n",
+			createErr: errors.New("repository error"),
+			wantErr:   usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mc := &mockCategoryRepository{}
+			mc.On("GetData", 1).Return(categories, nil)
+			mp := &mockPayerRepository{}
+			mp.On("GetData", 1).Return(payers, nil)
+
+			m := &mockPaymentRepository{}
+			for p, n := range tt.duplicates {
+				m.On("CountDuplicates", p).Return(n, nil)
+			}
+			m.On("CountDuplicates", mock.Anything).Return(0, nil)
+			var created []*model.Payment
+			m.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+				created = append(created, args.Get(0).(*model.Payment))
+			}).Return(&model.Payment{}, tt.createErr)
+
+			u := usecase.NewPaymentImportUseCase(m, mc, mp, &mockTxManager{})
+			got, err := u.Import(context.Background(), 1, 2, strings.NewReader(tt.csv), tt.dryRun)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if diff := cmp.Diff(tt.wantErr, err); diff != "" {
+					t.Errorf("Import() error mismatch (-want +got):\n%s", diff)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Import() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantCreate, created); diff != "" {
+				t.Errorf("Import() created mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -1,32 +1,48 @@
 package usecase
 
 import (
+	"context"
 	"database/sql"
 	"log"
+	"sort"
 	"time"
 
-	"github.com/go-playground/validator"
 	"github.com/warikan/api/domain/model"
 	"github.com/warikan/api/domain/repository"
 	"github.com/warikan/api/usecase/util"
 )
 
 type PaymentUseCase interface {
-	GetData(userID, cursor int) ([]*Payment, error)
-	Create(req *CreatePaymentParam, userID int) (*model.Payment, error)
-	Update(req *UpdatePaymentParam, userID int, paymentID int) (*model.Payment, error)
-	DeleteByID(userID, paymentID int) error
-	FetchDate(userID int) (*PaymentDate, error)
+	GetData(ctx context.Context, userID int, param *GetPaymentsParam) (*PaymentPage, error)
+	GetByID(ctx context.Context, userID, paymentID int) (*PaymentDetail, error)
+	Create(ctx context.Context, req *CreatePaymentParam, userID int) (*model.Payment, error)
+	Update(ctx context.Context, req *UpdatePaymentParam, userID int, paymentID int) (*model.Payment, error)
+	// DeleteByID : versionが空でない場合、取得後に支払いが更新されていればConflictErrorを返す
+	DeleteByID(ctx context.Context, userID, paymentID int, version string) error
+	// Approve : 承認待ちの支払いを承認し、精算と月ごとの集計の対象にする。reviewerIDは操作したユーザー
+	Approve(ctx context.Context, userID, reviewerID, paymentID int) (*model.Payment, error)
+	// Reject : 承認待ちの支払いを却下する。却下した支払いは精算と月ごとの集計の対象にしない。reviewerIDは操作したユーザー
+	Reject(ctx context.Context, userID, reviewerID, paymentID int) (*model.Payment, error)
+	FetchMonthlyCost(ctx context.Context, userID int) (*MonthlyCosts, error)
+	// Export : paramの絞り込み条件に一致する全ての支払いを支払日の降順でfnに渡す。fnがエラーを返した場合は中断してそのエラーを返す
+	Export(ctx context.Context, userID int, param *GetPaymentsParam, fn func(*ExportPayment) error) error
 }
 
-func NewPaymentUseCase(r repository.PaymentRepository) *paymentUsecase {
-	return &paymentUsecase{r}
+func NewPaymentUseCase(r repository.PaymentRepository, pageSize PageSize) *paymentUsecase {
+	return &paymentUsecase{r, pageSize}
 }
 
 var _ PaymentUseCase = &paymentUsecase{}
 
 type paymentUsecase struct {
 	PaymentRepository repository.PaymentRepository
+	PageSize          PageSize
+}
+
+// PageSize : 一覧で件数が指定されない場合はDefault件、指定された場合も最大Max件まで返す
+type PageSize struct {
+	Default int
+	Max     int
 }
 
 type Payment struct {
@@ -35,15 +51,72 @@ type Payment struct {
 	PayerName    string `json:"payer_name"`
 	PaymentDate  string `json:"payment_date"`
 	Payment      int    `json:"payment"`
+	Status       string `json:"status"`
 	CreatedAt    string `json:"created_at"`
 }
 
+// PaymentDetail : 支払いの編集画面で使う、支払いの全項目
+type PaymentDetail struct {
+	ID           int            `json:"id"`
+	CategoryID   int            `json:"category_id"`
+	CategoryName string         `json:"category_name"`
+	PayerID      int            `json:"payer_id"`
+	PayerName    string         `json:"payer_name"`
+	Description  sql.NullString `json:"description"`
+	PaymentDate  string         `json:"payment_date"`
+	Payment      int            `json:"payment"`
+	Status       string         `json:"status"`
+	CreatedAt    string         `json:"created_at"`
+	UpdatedAt    string         `json:"updated_at"`
+	// Version : 更新時にUpdatePaymentParam.Versionに指定する
+	Version string `json:"-"`
+}
+
+// GetPaymentsParam : Cursorには前のページのNextCursorを指定する。空の場合は先頭から取得する。
+// 絞り込み条件はゼロ値の場合は使わない。Month(yyyy-MM)とFrom・To(yyyy-MM-dd、Toを含む)は同時に指定できない
+type GetPaymentsParam struct {
+	Cursor      string `json:"cursor"`
+	Limit       int    `json:"limit" validate:"min=0"`
+	Month       string `json:"month"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	CategoryID  int    `json:"category_id" validate:"min=0"`
+	PayerID     int    `json:"payer_id" validate:"min=0"`
+	MinPayment  int    `json:"min_payment" validate:"min=0"`
+	MaxPayment  int    `json:"max_payment" validate:"min=0"`
+	Description string `json:"description"`
+	Status      string `json:"status" validate:"omitempty,oneof=pending approved rejected"`
+}
+
+// ExportPayment : エクスポートする支払い。PaymentDateはJSTの日付
+type ExportPayment struct {
+	PaymentDate  string
+	CategoryName string
+	PayerName    string
+	Payment      int
+	Description  string
+}
+
+// exportPageSize : エクスポート時に1回のクエリで取得する件数
+const exportPageSize = 500
+
+// PaymentPage : HasMoreがtrueの場合、NextCursorを指定して次のページを取得できる
+type PaymentPage struct {
+	Payments   []*Payment
+	NextCursor string
+	HasMore    bool
+}
+
+// CreatePaymentParam : Statusにpendingを指定した場合は、承認されるまで精算と月ごとの集計の対象にしない。空の場合はapproved
 type CreatePaymentParam struct {
 	CategoryID  int            `json:"category_id" validate:"required"`
 	PayerID     int            `json:"payer_id" validate:"required"`
 	Description sql.NullString `json:"description"`
-	PaymentDate time.Time      `json:"payment_date" validate:"required"`
-	Payment     int            `json:"payment" validate:"required"`
+	PaymentDate time.Time      `json:"payment_date" validate:"required,maxfuture=365"`
+	Payment     int            `json:"payment" validate:"required,gt=0,max=10000000"`
+	Status      string         `json:"status" validate:"omitempty,oneof=pending approved"`
+	// CreatedBy : 登録したユーザー。このユーザーは承認・却下できない。0の場合は記録しない
+	CreatedBy int `json:"-"`
 }
 
 type UpdatePaymentParam struct {
@@ -51,48 +124,208 @@ type UpdatePaymentParam struct {
 	CategoryID  int            `json:"category_id" validate:"required"`
 	PayerID     int            `json:"payer_id" validate:"required"`
 	Description sql.NullString `json:"description"`
-	PaymentDate time.Time      `json:"payment_date" validate:"required"`
-	Payment     int            `json:"payment" validate:"required"`
+	PaymentDate time.Time      `json:"payment_date" validate:"required,maxfuture=365"`
+	Payment     int            `json:"payment" validate:"required,gt=0,max=10000000"`
+	// Version : 空でない場合、取得後に支払いが更新されていればConflictErrorを返す
+	Version string `json:"-"`
+	// UpdatedBy : 更新したユーザー。承認待ちに戻した場合はこのユーザーが登録したユーザーになる。0の場合は記録しない
+	UpdatedBy int `json:"-"`
+}
+
+type MonthlyCosts struct {
+	MonthlyCosts []*MonthlyCost `json:"monthly_costs"`
+}
+
+type MonthlyCost struct {
+	YearMonth  string          `json:"year_month"`
+	Total      int             `json:"total"`
+	Count      int             `json:"count"`
+	Payers     []*PayerCost    `json:"payers"`
+	Categories []*CategoryCost `json:"categories"`
+}
+
+type PayerCost struct {
+	PayerID int `json:"payer_id"`
+	Total   int `json:"total"`
+	Count   int `json:"count"`
 }
 
-type PaymentDate struct {
-	PaymentDate []*string `json:"payment_date"`
+type CategoryCost struct {
+	CategoryID   int    `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	Total        int    `json:"total"`
+	Count        int    `json:"count"`
 }
 
-func (u *paymentUsecase) GetData(userID, cursor int) ([]*Payment, error) {
+func (u *paymentUsecase) GetData(ctx context.Context, userID int, param *GetPaymentsParam) (*PaymentPage, error) {
+	filter, err := paymentFilter(param)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := param.Limit
+	switch {
+	case limit == 0:
+		limit = u.PageSize.Default
+	case limit > u.PageSize.Max:
+		limit = u.PageSize.Max
+	}
+
+	var cursor *model.PaymentCursor
+	if param.Cursor != "" {
+		paymentDate, id, err := util.DecodeCursor(param.Cursor)
+		if err != nil {
+			return nil, invalidParam("cursor", "format", "")
+		}
+		cursor = &model.PaymentCursor{PaymentDate: paymentDate, ID: id}
+	}
 
-	p, err := u.PaymentRepository.GetData(userID, cursor)
+	// 次のページがあるかどうかを判定するため1件多く取得する
+	p, err := u.PaymentRepository.GetData(ctx, userID, filter, cursor, limit+1)
 	if err != nil {
 		log.Println("internal server error")
 		return nil, InternalServerError{}
 	}
 
-	payments := make([]*Payment, 0, len(p))
+	page := &PaymentPage{
+		Payments: make([]*Payment, 0, len(p)),
+	}
+	if len(p) > limit {
+		p = p[:limit]
+		last := p[len(p)-1]
+		page.HasMore = true
+		page.NextCursor = util.EncodeCursor(last.PaymentDate, last.ID)
+	}
 
 	for _, v := range p {
-
 		res := &Payment{
 			ID:           v.ID,
 			CategoryName: v.CategoryName,
 			PayerName:    v.PayerName,
 			PaymentDate:  util.ConvertJSTStringDate(v.PaymentDate),
 			Payment:      v.Payment,
+			Status:       v.Status,
 			CreatedAt:    util.ConvertJSTStringTime(v.CreatedAt),
 		}
-		payments = append(payments, res)
+		page.Payments = append(page.Payments, res)
+	}
+
+	return page, nil
+}
 
+func (u *paymentUsecase) Export(ctx context.Context, userID int, param *GetPaymentsParam, fn func(*ExportPayment) error) error {
+	filter, err := paymentFilter(param)
+	if err != nil {
+		return err
 	}
 
-	return payments, nil
+	// 全件を一度に読み込まないよう、一覧と同じカーソルで区切って取得する
+	var cursor *model.PaymentCursor
+	for {
+		p, err := u.PaymentRepository.GetData(ctx, userID, filter, cursor, exportPageSize)
+		if err != nil {
+			log.Println("repository error")
+			return InternalServerError{}
+		}
+
+		for _, v := range p {
+			e := &ExportPayment{
+				PaymentDate:  util.ConvertJSTStringDate(v.PaymentDate),
+				CategoryName: v.CategoryName,
+				PayerName:    v.PayerName,
+				Payment:      v.Payment,
+				Description:  v.Description.String,
+			}
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+
+		if len(p) < exportPageSize {
+			return nil
+		}
+		last := p[len(p)-1]
+		cursor = &model.PaymentCursor{PaymentDate: last.PaymentDate, ID: last.ID}
+	}
 }
 
-func (u *paymentUsecase) Create(param *CreatePaymentParam, userID int) (*model.Payment, error) {
+func (u *paymentUsecase) GetByID(ctx context.Context, userID, paymentID int) (*PaymentDetail, error) {
+	p, err := u.PaymentRepository.FindByID(ctx, userID, paymentID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	return &PaymentDetail{
+		ID:           p.ID,
+		CategoryID:   p.CategoryID,
+		CategoryName: p.CategoryName,
+		PayerID:      p.PayerID,
+		PayerName:    p.PayerName,
+		Description:  p.Description,
+		PaymentDate:  util.ConvertJSTStringDate(p.PaymentDate),
+		Payment:      p.Payment,
+		Status:       p.Status,
+		CreatedAt:    util.ConvertJSTStringTime(p.CreatedAt),
+		UpdatedAt:    util.ConvertJSTStringTime(p.UpdatedAt),
+		Version:      util.EncodeVersion(p.UpdatedAt),
+	}, nil
+}
 
-	validate := validator.New()
-	err := validate.Struct(param)
+// paymentFilter : 一覧の絞り込み条件を検証し、JSTの日付を期間に変換する
+func paymentFilter(param *GetPaymentsParam) (*model.PaymentFilter, error) {
+	if err := validateParam(param); err != nil {
+		return nil, err
+	}
+	if param.MaxPayment != 0 && param.MinPayment > param.MaxPayment {
+		return nil, invalidParam("min_payment", "ltefield", "max_payment")
+	}
+	if param.Month != "" && (param.From != "" || param.To != "") {
+		return nil, invalidParam("month", "excluded_with", "from to")
+	}
+
+	filter := &model.PaymentFilter{
+		CategoryID:  param.CategoryID,
+		PayerID:     param.PayerID,
+		MinPayment:  param.MinPayment,
+		MaxPayment:  param.MaxPayment,
+		Description: param.Description,
+		Status:      param.Status,
+	}
+
+	if param.Month != "" {
+		month, err := util.ParseJSTYearMonth(param.Month)
+		if err != nil {
+			return nil, invalidParam("month", "format", "yyyy-MM")
+		}
+		filter.From = month
+		filter.To = month.AddDate(0, 1, 0)
+	}
+	if param.From != "" {
+		from, err := util.ParseJSTDate(param.From)
+		if err != nil {
+			return nil, invalidParam("from", "format", "yyyy-MM-dd")
+		}
+		filter.From = from
+	}
+	if param.To != "" {
+		to, err := util.ParseJSTDate(param.To)
+		if err != nil {
+			return nil, invalidParam("to", "format", "yyyy-MM-dd")
+		}
+		filter.To = to.AddDate(0, 0, 1)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, invalidParam("to", "gtefield", "from")
+	}
+
+	return filter, nil
+}
+
+func (u *paymentUsecase) Create(ctx context.Context, param *CreatePaymentParam, userID int) (*model.Payment, error) {
+
+	err := validateParam(param)
 	if err != nil {
-		log.Println("validation error")
-		return nil, InvalidParamError{}
+		return nil, err
 	}
 
 	payment := &model.Payment{
@@ -102,24 +335,37 @@ func (u *paymentUsecase) Create(param *CreatePaymentParam, userID int) (*model.P
 		Description: param.Description,
 		PaymentDate: param.PaymentDate,
 		Payment:     param.Payment,
+		Status:      param.Status,
+	}
+	if param.CreatedBy != 0 {
+		payment.CreatedBy = sql.NullInt64{Int64: int64(param.CreatedBy), Valid: true}
 	}
 
-	payment, err = u.PaymentRepository.Create(payment)
-
+	payment, err = u.PaymentRepository.Create(ctx, payment)
 	if err != nil {
-		log.Println("repository error")
-		return nil, InternalServerError{}
+		return nil, repositoryError(err)
 	}
 	return payment, nil
 }
 
-func (u *paymentUsecase) Update(param *UpdatePaymentParam, userID, paymentID int) (*model.Payment, error) {
+func (u *paymentUsecase) Update(ctx context.Context, param *UpdatePaymentParam, userID, paymentID int) (*model.Payment, error) {
 
-	validate := validator.New()
-	err := validate.Struct(param)
+	err := validateParam(param)
 	if err != nil {
-		log.Println("validation error")
-		return nil, InvalidParamError{}
+		return nil, err
+	}
+
+	var version time.Time
+	if param.Version != "" {
+		version, err = util.DecodeVersion(param.Version)
+		if err != nil {
+			return nil, invalidParam("version", "format", "")
+		}
+	}
+
+	current, err := u.PaymentRepository.FindByID(ctx, userID, paymentID)
+	if err != nil {
+		return nil, repositoryError(err)
 	}
 
 	payment := &model.Payment{
@@ -130,36 +376,139 @@ func (u *paymentUsecase) Update(param *UpdatePaymentParam, userID, paymentID int
 		Description: param.Description,
 		PaymentDate: param.PaymentDate,
 		Payment:     param.Payment,
+		Status:      current.Status,
+		CreatedBy:   current.CreatedBy,
+		// 取得した支払いをもとに承認状態を決めるため、取得後に更新されていれば上書きしない
+		UpdatedAt: current.UpdatedAt,
+	}
+	if !version.IsZero() {
+		payment.UpdatedAt = version
 	}
 
-	payment, err = u.PaymentRepository.Update(payment)
+	// 承認待ちでない支払いの精算に関わる項目を変更した場合は、変更したユーザーの登録として改めて相手の承認を受ける
+	if current.Status != model.PaymentStatusPending && affectsSettlement(current, payment) {
+		payment.Status = model.PaymentStatusPending
+		payment.CreatedBy = sql.NullInt64{}
+		if param.UpdatedBy != 0 {
+			payment.CreatedBy = sql.NullInt64{Int64: int64(param.UpdatedBy), Valid: true}
+		}
+	}
 
+	payment, err = u.PaymentRepository.Update(ctx, payment)
 	if err != nil {
-		log.Println("repository error")
-		return nil, InternalServerError{}
+		return nil, repositoryError(err)
 	}
 	return payment, nil
 }
 
-func (u *paymentUsecase) DeleteByID(userID, paymentID int) error {
-	err := u.PaymentRepository.DeleteByID(userID, paymentID)
+// affectsSettlement : 精算額に影響する項目(カテゴリー・支払者・支払日・金額)が変わったかどうかを返す
+func affectsSettlement(before, after *model.Payment) bool {
+	return before.CategoryID != after.CategoryID ||
+		before.PayerID != after.PayerID ||
+		!before.PaymentDate.Equal(after.PaymentDate) ||
+		before.Payment != after.Payment
+}
+
+func (u *paymentUsecase) DeleteByID(ctx context.Context, userID, paymentID int, version string) error {
+	var updatedAt time.Time
+	if version != "" {
+		var err error
+		if updatedAt, err = util.DecodeVersion(version); err != nil {
+			return invalidParam("version", "format", "")
+		}
+	}
+
+	err := u.PaymentRepository.DeleteByID(ctx, userID, paymentID, updatedAt)
 	if err != nil {
-		log.Println("repository error")
-		return InternalServerError{}
+		return repositoryError(err)
 	}
 	return nil
 }
 
-func (u *paymentUsecase) FetchDate(userID int) (*PaymentDate, error) {
+func (u *paymentUsecase) Approve(ctx context.Context, userID, reviewerID, paymentID int) (*model.Payment, error) {
+	return u.review(ctx, userID, reviewerID, paymentID, model.PaymentStatusApproved)
+}
+
+func (u *paymentUsecase) Reject(ctx context.Context, userID, reviewerID, paymentID int) (*model.Payment, error) {
+	return u.review(ctx, userID, reviewerID, paymentID, model.PaymentStatusRejected)
+}
+
+// review : 自分が登録した支払いはForbiddenError、承認待ちでない支払いはConflictErrorを返す
+func (u *paymentUsecase) review(ctx context.Context, userID, reviewerID, paymentID int, status string) (*model.Payment, error) {
+	current, err := u.PaymentRepository.FindByID(ctx, userID, paymentID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	if current.CreatedBy.Valid && int(current.CreatedBy.Int64) == reviewerID {
+		return nil, ForbiddenError{}
+	}
+
+	payment, err := u.PaymentRepository.UpdateStatus(ctx, userID, reviewerID, paymentID, status)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	return payment, nil
+}
+
+func (u *paymentUsecase) FetchMonthlyCost(ctx context.Context, userID int) (*MonthlyCosts, error) {
 
-	p, err := u.PaymentRepository.FetchDate(userID)
+	p, err := u.PaymentRepository.FetchMonthlyCosts(ctx, userID)
 	if err != nil {
 		log.Println("internal server error")
 		return nil, InternalServerError{}
 	}
 
-	pd := new(PaymentDate)
-	pd.PaymentDate = append(pd.PaymentDate, p...)
+	mc := &MonthlyCosts{
+		MonthlyCosts: make([]*MonthlyCost, 0),
+	}
 
-	return pd, nil
+	// 月 > カテゴリー > 支払者の順で並んでいる集計結果を月ごとにまとめる
+	var current *MonthlyCost
+	for _, v := range p {
+		if current == nil || current.YearMonth != v.YearMonth {
+			current = &MonthlyCost{
+				YearMonth:  v.YearMonth,
+				Payers:     make([]*PayerCost, 0),
+				Categories: make([]*CategoryCost, 0),
+			}
+			mc.MonthlyCosts = append(mc.MonthlyCosts, current)
+		}
+
+		current.Total += v.Payment
+		current.Count += v.Count
+
+		if n := len(current.Categories); n == 0 || current.Categories[n-1].CategoryID != v.CategoryID {
+			current.Categories = append(current.Categories, &CategoryCost{
+				CategoryID:   v.CategoryID,
+				CategoryName: v.CategoryName,
+			})
+		}
+		c := current.Categories[len(current.Categories)-1]
+		c.Total += v.Payment
+		c.Count += v.Count
+
+		payer := findPayerCost(current.Payers, v.PayerID)
+		if payer == nil {
+			payer = &PayerCost{PayerID: v.PayerID}
+			current.Payers = append(current.Payers, payer)
+		}
+		payer.Total += v.Payment
+		payer.Count += v.Count
+	}
+
+	for _, v := range mc.MonthlyCosts {
+		payers := v.Payers
+		sort.Slice(payers, func(i, j int) bool { return payers[i].PayerID < payers[j].PayerID })
+	}
+
+	return mc, nil
+}
+
+func findPayerCost(payers []*PayerCost, payerID int) *PayerCost {
+	for _, v := range payers {
+		if v.PayerID == payerID {
+			return v
+		}
+	}
+	return nil
 }
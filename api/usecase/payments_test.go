@@ -1,63 +1,187 @@
 package usecase_test
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/mock"
 	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
 	"github.com/warikan/api/usecase"
+	"github.com/warikan/api/usecase/util"
 )
 
+var testPageSize = usecase.PageSize{Default: 2, Max: 3}
+
+var jst, _ = time.LoadLocation("Asia/Tokyo")
+
 func Test_paymentUsecase_GetData(t *testing.T) {
+	date := func(day int) time.Time {
+		return time.Date(2020, time.April, day, 0, 0, 0, 0, time.UTC)
+	}
+	payment := func(id, day int) *model.Payment {
+		return &model.Payment{
+			ID:           id,
+			CategoryName: "カテゴリー名",
+			PayerName:    "パートナー",
+			PaymentDate:  date(day),
+			Payment:      1234,
+			CreatedAt:    date(day),
+		}
+	}
+	want := func(id, day int) *usecase.Payment {
+		return &usecase.Payment{
+			ID:           id,
+			CategoryName: "カテゴリー名",
+			PayerName:    "パートナー",
+			PaymentDate:  fmt.Sprintf("2020-04-%02d", day),
+			Payment:      1234,
+			CreatedAt:    fmt.Sprintf("2020-04-%02d 09:00:00", day),
+		}
+	}
+	cursor := util.EncodeCursor(date(2), 2)
 
 	tests := []struct {
-		name     string
-		userID   int
-		cursor   int
-		mockWant []*model.Payment
-		mockErr  error
-		want     []*usecase.Payment
-		wantErr  error
+		name       string
+		userID     int
+		param      *usecase.GetPaymentsParam
+		mockFilter *model.PaymentFilter
+		mockCursor *model.PaymentCursor
+		mockLimit  int
+		mockWant   []*model.Payment
+		mockErr    error
+		want       *usecase.PaymentPage
+		wantErr    error
 	}{
 		{
-			name:   "Success",
+			name:      "Success last page",
+			userID:    1,
+			param:     &usecase.GetPaymentsParam{},
+			mockLimit: 3,
+			mockWant:  []*model.Payment{payment(3, 3)},
+			want: &usecase.PaymentPage{
+				Payments: []*usecase.Payment{want(3, 3)},
+			},
+		},
+		{
+			name:      "Success has more",
+			userID:    1,
+			param:     &usecase.GetPaymentsParam{},
+			mockLimit: 3,
+			mockWant:  []*model.Payment{payment(3, 3), payment(2, 2), payment(1, 1)},
+			want: &usecase.PaymentPage{
+				Payments:   []*usecase.Payment{want(3, 3), want(2, 2)},
+				NextCursor: cursor,
+				HasMore:    true,
+			},
+		},
+		{
+			name:       "Success with cursor",
+			userID:     1,
+			param:      &usecase.GetPaymentsParam{Cursor: cursor, Limit: 1},
+			mockCursor: &model.PaymentCursor{PaymentDate: date(2), ID: 2},
+			mockLimit:  2,
+			mockWant:   []*model.Payment{payment(1, 1)},
+			want: &usecase.PaymentPage{
+				Payments: []*usecase.Payment{want(1, 1)},
+			},
+		},
+		{
+			name:      "Success limit is capped",
+			userID:    1,
+			param:     &usecase.GetPaymentsParam{Limit: 100},
+			mockLimit: 4,
+			mockWant:  []*model.Payment{},
+			want: &usecase.PaymentPage{
+				Payments: []*usecase.Payment{},
+			},
+		},
+		{
+			name:   "Success with month filter",
 			userID: 1,
-			cursor: 1,
-			mockWant: []*model.Payment{
-				{
-					ID:           1,
-					CategoryName: "カテゴリー名",
-					PayerName:    "パートナー",
-					PaymentDate:  time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
-					Payment:      1234,
-					CreatedAt:    time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
-				},
+			param: &usecase.GetPaymentsParam{
+				Month:       "2020-03",
+				CategoryID:  2,
+				PayerID:     2,
+				Description: "スーパー",
 			},
-			mockErr: nil,
-			want: []*usecase.Payment{
-				{
-					ID:           1,
-					CategoryName: "カテゴリー名",
-					PayerName:    "パートナー",
-					PaymentDate:  "2020-04-01",
-					Payment:      1234,
-					CreatedAt:    "2020-04-01 09:00:00",
-				},
+			mockFilter: &model.PaymentFilter{
+				From:        time.Date(2020, time.March, 1, 0, 0, 0, 0, jst),
+				To:          time.Date(2020, time.April, 1, 0, 0, 0, 0, jst),
+				CategoryID:  2,
+				PayerID:     2,
+				Description: "スーパー",
+			},
+			mockLimit: 3,
+			mockWant:  []*model.Payment{},
+			want: &usecase.PaymentPage{
+				Payments: []*usecase.Payment{},
 			},
-			wantErr: nil,
 		},
 		{
-			name:     "Repository error",
-			userID:   1,
-			cursor:   1,
-			mockWant: []*model.Payment{},
-			mockErr:  errors.New("repository error"),
-			want:     []*usecase.Payment{},
-			wantErr:  usecase.InternalServerError{},
+			name:   "Success with date range filter",
+			userID: 1,
+			param: &usecase.GetPaymentsParam{
+				From:       "2020-03-10",
+				To:         "2020-03-20",
+				MinPayment: 1000,
+				MaxPayment: 5000,
+			},
+			mockFilter: &model.PaymentFilter{
+				From:       time.Date(2020, time.March, 10, 0, 0, 0, 0, jst),
+				To:         time.Date(2020, time.March, 21, 0, 0, 0, 0, jst),
+				MinPayment: 1000,
+				MaxPayment: 5000,
+			},
+			mockLimit: 3,
+			mockWant:  []*model.Payment{},
+			want: &usecase.PaymentPage{
+				Payments: []*usecase.Payment{},
+			},
+		},
+		{
+			name:    "Invalid param error month and date range",
+			userID:  1,
+			param:   &usecase.GetPaymentsParam{Month: "2020-03", From: "2020-03-10"},
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name:    "Invalid param error from is after to",
+			userID:  1,
+			param:   &usecase.GetPaymentsParam{From: "2020-03-20", To: "2020-03-10"},
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name:    "Invalid param error min payment is greater than max payment",
+			userID:  1,
+			param:   &usecase.GetPaymentsParam{MinPayment: 5000, MaxPayment: 1000},
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name:    "Invalid param error cursor",
+			userID:  1,
+			param:   &usecase.GetPaymentsParam{Cursor: "invalid"},
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name:    "Invalid param error limit",
+			userID:  1,
+			param:   &usecase.GetPaymentsParam{Limit: -1},
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name:      "Repository error",
+			userID:    1,
+			param:     &usecase.GetPaymentsParam{},
+			mockLimit: 3,
+			mockWant:  []*model.Payment{},
+			mockErr:   errors.New("repository error"),
+			wantErr:   usecase.InternalServerError{},
 		},
 	}
 
@@ -66,11 +190,112 @@ func Test_paymentUsecase_GetData(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			mock := &mockPaymentRepository{}
-			mock.On("GetData", tt.userID, tt.cursor).Return(tt.mockWant, tt.mockErr)
+			mockFilter := tt.mockFilter
+			if mockFilter == nil {
+				mockFilter = &model.PaymentFilter{}
+			}
+
+			m := &mockPaymentRepository{}
+			m.On("GetData", tt.userID, mockFilter, mock.MatchedBy(func(c *model.PaymentCursor) bool {
+				if c == nil || tt.mockCursor == nil {
+					return c == tt.mockCursor
+				}
+				return c.PaymentDate.Equal(tt.mockCursor.PaymentDate) && c.ID == tt.mockCursor.ID
+			}), tt.mockLimit).Return(tt.mockWant, tt.mockErr)
+
+			u := usecase.NewPaymentUseCase(m, testPageSize)
+			got, err := u.GetData(context.Background(), tt.userID, tt.param)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetData() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_paymentUsecase_GetByID(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    int
+		paymentID int
+		mockWant  *model.Payment
+		mockErr   error
+		want      *usecase.PaymentDetail
+		wantErr   error
+	}{
+		{
+			name:      "Success",
+			userID:    1,
+			paymentID: 1,
+			mockWant: &model.Payment{
+				ID:           1,
+				UserID:       1,
+				CategoryID:   2,
+				CategoryName: "食費",
+				PayerID:      2,
+				PayerName:    "パートナー",
+				Description:  sql.NullString{String: "スーパー", Valid: true},
+				PaymentDate:  time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:      1234,
+				CreatedAt:    time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt:    time.Date(2020, time.April, 2, 0, 0, 0, 0, time.UTC),
+			},
+			want: &usecase.PaymentDetail{
+				ID:           1,
+				CategoryID:   2,
+				CategoryName: "食費",
+				PayerID:      2,
+				PayerName:    "パートナー",
+				Description:  sql.NullString{String: "スーパー", Valid: true},
+				PaymentDate:  "2020-04-01",
+				Payment:      1234,
+				CreatedAt:    "2020-04-01 09:00:00",
+				UpdatedAt:    "2020-04-02 09:00:00",
+				Version:      "1585785600000000",
+			},
+		},
+		{
+			name:      "NotFound error",
+			userID:    1,
+			paymentID: 999,
+			mockWant:  &model.Payment{},
+			mockErr:   repository.ErrNotFound,
+			wantErr:   usecase.NotFoundError{},
+		},
+		{
+			name:      "Repository error",
+			userID:    1,
+			paymentID: 1,
+			mockWant:  &model.Payment{},
+			mockErr:   errors.New("repository error"),
+			wantErr:   usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
 
-			u := usecase.NewPaymentUseCase(mock)
-			got, err := u.GetData(tt.userID, tt.cursor)
+			m := &mockPaymentRepository{}
+			m.On("FindByID", tt.userID, tt.paymentID).Return(tt.mockWant, tt.mockErr)
+
+			u := usecase.NewPaymentUseCase(m, testPageSize)
+			got, err := u.GetByID(context.Background(), tt.userID, tt.paymentID)
 			if tt.wantErr != nil {
 				if err == nil {
 					t.Error("expected error, but got nil")
@@ -83,11 +308,11 @@ func Test_paymentUsecase_GetData(t *testing.T) {
 			}
 
 			if err != nil {
-				t.Error("expected error, but got nil")
+				t.Errorf("err should be nil, but got %q", err)
 				return
 			}
 			if diff := cmp.Diff(tt.want, got); diff != "" {
-				t.Errorf("usecase returned wrong response mismatch (-want +got):\n%s", diff)
+				t.Errorf("GetByID() mismatch (-want +got):\n%s", diff)
 			}
 		})
 	}
@@ -133,6 +358,41 @@ func TestPaymentsUseCase_Create(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "Success pending",
+			param: &usecase.CreatePaymentParam{
+				CategoryID:  1,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+				Status:      model.PaymentStatusPending,
+				CreatedBy:   1,
+			},
+			userID: 1,
+			mock: &model.Payment{
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+				Status:      model.PaymentStatusPending,
+				CreatedBy:   sql.NullInt64{Int64: 1, Valid: true},
+			},
+			mockErr: nil,
+			want: &model.Payment{
+				ID:          0,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+				Status:      model.PaymentStatusPending,
+			},
+			wantErr: nil,
+		},
 		{
 			name: "InvalidParam error",
 			param: &usecase.CreatePaymentParam{
@@ -142,6 +402,28 @@ func TestPaymentsUseCase_Create(t *testing.T) {
 			want:    nil,
 			wantErr: usecase.InvalidParamError{},
 		},
+		{
+			name: "UnprocessableEntity error unknown category",
+			param: &usecase.CreatePaymentParam{
+				CategoryID:  999,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+			},
+			userID: 1,
+			mock: &model.Payment{
+				UserID:      1,
+				CategoryID:  999,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+			},
+			mockErr: repository.InvalidFieldError{Field: "category_id"},
+			want:    nil,
+			wantErr: usecase.UnprocessableEntityError{Field: "category_id"},
+		},
 		{
 			name: "Repository error",
 			param: &usecase.CreatePaymentParam{
@@ -174,8 +456,8 @@ func TestPaymentsUseCase_Create(t *testing.T) {
 			m := &mockPaymentRepository{}
 			m.On("Create", tt.mock).Return(tt.want, tt.mockErr)
 
-			u := usecase.NewPaymentUseCase(m)
-			got, err := u.Create(tt.param, tt.userID)
+			u := usecase.NewPaymentUseCase(m, testPageSize)
+			got, err := u.Create(context.Background(), tt.param, tt.userID)
 			if tt.wantErr != nil {
 				if err == nil {
 					t.Error("expected error, but got nil")
@@ -198,12 +480,92 @@ func TestPaymentsUseCase_Create(t *testing.T) {
 	}
 }
 
+func TestPaymentsUseCase_Create_InvalidParam(t *testing.T) {
+	valid := func(f func(p *usecase.CreatePaymentParam)) *usecase.CreatePaymentParam {
+		p := &usecase.CreatePaymentParam{
+			CategoryID:  1,
+			PayerID:     1,
+			PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+			Payment:     1234,
+		}
+		f(p)
+		return p
+	}
+
+	tests := []struct {
+		name    string
+		param   *usecase.CreatePaymentParam
+		wantErr error
+	}{
+		{
+			name:  "required",
+			param: &usecase.CreatePaymentParam{},
+			wantErr: usecase.InvalidParamError{Fields: []usecase.FieldError{
+				{Field: "category_id", Rule: "required"},
+				{Field: "payer_id", Rule: "required"},
+				{Field: "payment_date", Rule: "required"},
+				{Field: "payment", Rule: "required"},
+			}},
+		},
+		{
+			name:  "payment is negative",
+			param: valid(func(p *usecase.CreatePaymentParam) { p.Payment = -1 }),
+			wantErr: usecase.InvalidParamError{Fields: []usecase.FieldError{
+				{Field: "payment", Rule: "gt", Param: "0"},
+			}},
+		},
+		{
+			name:  "payment is too large",
+			param: valid(func(p *usecase.CreatePaymentParam) { p.Payment = 10000001 }),
+			wantErr: usecase.InvalidParamError{Fields: []usecase.FieldError{
+				{Field: "payment", Rule: "max", Param: "10000000"},
+			}},
+		},
+		{
+			name:  "payment_date is far in the future",
+			param: valid(func(p *usecase.CreatePaymentParam) { p.PaymentDate = time.Now().AddDate(1, 1, 0) }),
+			wantErr: usecase.InvalidParamError{Fields: []usecase.FieldError{
+				{Field: "payment_date", Rule: "maxfuture", Param: "365"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			u := usecase.NewPaymentUseCase(&mockPaymentRepository{}, testPageSize)
+			_, err := u.Create(context.Background(), tt.param, 1)
+			if diff := cmp.Diff(tt.wantErr, err); diff != "" {
+				t.Errorf("Create() mismatch error (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestPaymentsUseCase_Update(t *testing.T) {
+	updatedAt := time.Date(2020, time.April, 2, 0, 0, 0, 0, time.UTC)
+	current := &model.Payment{
+		ID:          1,
+		UserID:      1,
+		CategoryID:  1,
+		PayerID:     1,
+		Description: sql.NullString{String: "", Valid: false},
+		PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+		Payment:     1234,
+		Status:      model.PaymentStatusApproved,
+		CreatedBy:   sql.NullInt64{Int64: 1, Valid: true},
+		UpdatedAt:   updatedAt,
+	}
+
 	tests := []struct {
 		name      string
 		param     *usecase.UpdatePaymentParam
 		userID    int
 		paymentID int
+		found     *model.Payment
+		findErr   error
 		mock      *model.Payment
 		mockErr   error
 		want      *model.Payment
@@ -214,20 +576,25 @@ func TestPaymentsUseCase_Update(t *testing.T) {
 			param: &usecase.UpdatePaymentParam{
 				CategoryID:  1,
 				PayerID:     1,
-				Description: sql.NullString{String: "", Valid: false},
+				Description: sql.NullString{String: "更新後", Valid: true},
 				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
 				Payment:     1234,
+				UpdatedBy:   2,
 			},
 			userID:    1,
 			paymentID: 1,
+			found:     current,
 			mock: &model.Payment{
 				ID:          1,
 				UserID:      1,
 				CategoryID:  1,
 				PayerID:     1,
-				Description: sql.NullString{String: "", Valid: false},
+				Description: sql.NullString{String: "更新後", Valid: true},
 				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
 				Payment:     1234,
+				Status:      model.PaymentStatusApproved,
+				CreatedBy:   sql.NullInt64{Int64: 1, Valid: true},
+				UpdatedAt:   updatedAt,
 			},
 			mockErr: nil,
 			want: &model.Payment{
@@ -235,45 +602,184 @@ func TestPaymentsUseCase_Update(t *testing.T) {
 				UserID:      1,
 				CategoryID:  1,
 				PayerID:     1,
-				Description: sql.NullString{String: "", Valid: false},
+				Description: sql.NullString{String: "更新後", Valid: true},
 				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
 				Payment:     1234,
+				Status:      model.PaymentStatusApproved,
+				CreatedBy:   sql.NullInt64{Int64: 1, Valid: true},
 			},
 			wantErr: nil,
 		},
 		{
-			name: "InvalidParam error",
+			name: "Success pending when partner changes payer",
 			param: &usecase.UpdatePaymentParam{
+				CategoryID:  1,
+				PayerID:     2,
 				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+				UpdatedBy:   2,
 			},
 			userID:    1,
 			paymentID: 1,
-			want:      nil,
-			wantErr:   usecase.InvalidParamError{},
+			found:     current,
+			mock: &model.Payment{
+				ID:          1,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     2,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+				Status:      model.PaymentStatusPending,
+				CreatedBy:   sql.NullInt64{Int64: 2, Valid: true},
+				UpdatedAt:   updatedAt,
+			},
+			want: &model.Payment{
+				ID:          1,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     2,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+				Status:      model.PaymentStatusPending,
+				CreatedBy:   sql.NullInt64{Int64: 2, Valid: true},
+			},
 		},
 		{
-			name: "Repository error",
+			name: "Success pending when payment decreased",
 			param: &usecase.UpdatePaymentParam{
 				CategoryID:  1,
 				PayerID:     1,
 				Description: sql.NullString{String: "", Valid: false},
 				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
-				Payment:     1234,
+				Payment:     1000,
+				UpdatedBy:   1,
 			},
 			userID:    1,
 			paymentID: 1,
+			found:     current,
 			mock: &model.Payment{
 				ID:          1,
 				UserID:      1,
+				CategoryID:  1,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1000,
+				Status:      model.PaymentStatusPending,
+				CreatedBy:   sql.NullInt64{Int64: 1, Valid: true},
+				UpdatedAt:   updatedAt,
+			},
+			want: &model.Payment{
+				ID:          1,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1000,
+				Status:      model.PaymentStatusPending,
+				CreatedBy:   sql.NullInt64{Int64: 1, Valid: true},
+			},
+		},
+		{
+			name: "InvalidParam error",
+			param: &usecase.UpdatePaymentParam{
+				Description: sql.NullString{String: "", Valid: false},
+			},
+			userID:    1,
+			paymentID: 1,
+			want:      nil,
+			wantErr:   usecase.InvalidParamError{},
+		},
+		{
+			name: "NotFound error other user's payment",
+			param: &usecase.UpdatePaymentParam{
 				CategoryID:  1,
 				PayerID:     1,
 				Description: sql.NullString{String: "", Valid: false},
 				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
 				Payment:     1234,
 			},
-			mockErr: errors.New("repository error"),
-			want:    nil,
-			wantErr: usecase.InternalServerError{},
+			userID:    2,
+			paymentID: 1,
+			findErr:   repository.ErrNotFound,
+			want:      nil,
+			wantErr:   usecase.NotFoundError{},
+		},
+		{
+			name: "Repository error",
+			param: &usecase.UpdatePaymentParam{
+				CategoryID:  1,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+			},
+			userID:    1,
+			paymentID: 1,
+			found:     current,
+			mock: &model.Payment{
+				ID:          1,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+				Status:      model.PaymentStatusApproved,
+				CreatedBy:   sql.NullInt64{Int64: 1, Valid: true},
+				UpdatedAt:   updatedAt,
+			},
+			mockErr: errors.New("repository error"),
+			want:    nil,
+			wantErr: usecase.InternalServerError{},
+		},
+		{
+			name: "Conflict error updated by partner",
+			param: &usecase.UpdatePaymentParam{
+				CategoryID:  1,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+				Version:     "1585785600000000",
+			},
+			userID:    1,
+			paymentID: 1,
+			found:     current,
+			mock: &model.Payment{
+				ID:          1,
+				UserID:      1,
+				CategoryID:  1,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+				Status:      model.PaymentStatusApproved,
+				CreatedBy:   sql.NullInt64{Int64: 1, Valid: true},
+				UpdatedAt:   time.Unix(0, 1585785600000000*int64(time.Microsecond)),
+			},
+			mockErr: repository.ErrConflict,
+			want:    nil,
+			wantErr: usecase.ConflictError{},
+		},
+		{
+			name: "InvalidParam error version",
+			param: &usecase.UpdatePaymentParam{
+				CategoryID:  1,
+				PayerID:     1,
+				Description: sql.NullString{String: "", Valid: false},
+				PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Payment:     1234,
+				Version:     "invalid",
+			},
+			userID:    1,
+			paymentID: 1,
+			want:      nil,
+			wantErr:   usecase.InvalidParamError{},
 		},
 	}
 
@@ -283,10 +789,11 @@ func TestPaymentsUseCase_Update(t *testing.T) {
 			t.Parallel()
 
 			m := &mockPaymentRepository{}
+			m.On("FindByID", tt.userID, tt.paymentID).Return(tt.found, tt.findErr)
 			m.On("Update", tt.mock).Return(tt.want, tt.mockErr)
 
-			u := usecase.NewPaymentUseCase(m)
-			got, err := u.Update(tt.param, tt.userID, tt.paymentID)
+			u := usecase.NewPaymentUseCase(m, testPageSize)
+			got, err := u.Update(context.Background(), tt.param, tt.userID, tt.paymentID)
 			if tt.wantErr != nil {
 				if err == nil {
 					t.Error("expected error, but got nil")
@@ -309,11 +816,51 @@ func TestPaymentsUseCase_Update(t *testing.T) {
 	}
 }
 
+// 相手が承認済みの支払いを変更した場合は、変更した相手自身では承認できない
+func TestPaymentsUseCase_UpdateThenApproveByEditor(t *testing.T) {
+	stored := &model.Payment{
+		ID:          1,
+		UserID:      1,
+		CategoryID:  1,
+		PayerID:     1,
+		PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+		Payment:     1234,
+		Status:      model.PaymentStatusApproved,
+		CreatedBy:   sql.NullInt64{Int64: 1, Valid: true},
+	}
+
+	m := &mockPaymentRepository{}
+	m.On("FindByID", 1, 1).Return(func() *model.Payment { return stored }, nil)
+	m.On("Update", mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*model.Payment)
+	}).Return(func() *model.Payment { return stored }, nil)
+
+	u := usecase.NewPaymentUseCase(m, testPageSize)
+	param := &usecase.UpdatePaymentParam{
+		CategoryID:  1,
+		PayerID:     1,
+		PaymentDate: time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC),
+		Payment:     99999,
+		UpdatedBy:   2,
+	}
+	if _, err := u.Update(context.Background(), param, 1, 1); err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+
+	_, err := u.Approve(context.Background(), 1, 2, 1)
+	if diff := cmp.Diff(usecase.ForbiddenError{}, err); diff != "" {
+		t.Errorf("Approve() mismatch error (-want +got):\n%s", diff)
+	}
+	m.AssertNotCalled(t, "UpdateStatus", 1, 2, 1, model.PaymentStatusApproved)
+}
+
 func TestPaymentsUseCase_DeleteByID(t *testing.T) {
 	tests := []struct {
 		name      string
 		userID    int
 		paymentID int
+		version   string
+		updatedAt time.Time
 		mockErr   error
 		wantErr   error
 	}{
@@ -324,6 +871,36 @@ func TestPaymentsUseCase_DeleteByID(t *testing.T) {
 			mockErr:   nil,
 			wantErr:   nil,
 		},
+		{
+			name:      "Conflict error updated by partner",
+			userID:    1,
+			paymentID: 1,
+			version:   "1585785600000000",
+			updatedAt: time.Unix(0, 1585785600000000*int64(time.Microsecond)),
+			mockErr:   repository.ErrConflict,
+			wantErr:   usecase.ConflictError{},
+		},
+		{
+			name:      "InvalidParam error version",
+			userID:    1,
+			paymentID: 1,
+			version:   "invalid",
+			wantErr:   usecase.InvalidParamError{},
+		},
+		{
+			name:      "NotFound error",
+			userID:    1,
+			paymentID: 999,
+			mockErr:   repository.ErrNotFound,
+			wantErr:   usecase.NotFoundError{},
+		},
+		{
+			name:      "NotFound error other user's payment",
+			userID:    2,
+			paymentID: 1,
+			mockErr:   repository.ErrNotFound,
+			wantErr:   usecase.NotFoundError{},
+		},
 		{
 			name:      "Repository error",
 			userID:    1,
@@ -339,10 +916,10 @@ func TestPaymentsUseCase_DeleteByID(t *testing.T) {
 			t.Parallel()
 
 			m := &mockPaymentRepository{}
-			m.On("DeleteByID", tt.userID, tt.paymentID).Return(tt.mockErr)
+			m.On("DeleteByID", tt.userID, tt.paymentID, tt.updatedAt).Return(tt.mockErr)
 
-			u := usecase.NewPaymentUseCase(m)
-			err := u.DeleteByID(tt.userID, tt.paymentID)
+			u := usecase.NewPaymentUseCase(m, testPageSize)
+			err := u.DeleteByID(context.Background(), tt.userID, tt.paymentID, tt.version)
 			if tt.wantErr != nil {
 				if err == nil {
 					t.Error("expected error, but got nil")
@@ -362,31 +939,349 @@ func TestPaymentsUseCase_DeleteByID(t *testing.T) {
 	}
 }
 
+func TestPaymentsUseCase_Approve(t *testing.T) {
+	updatedAt := time.Date(2020, time.April, 2, 0, 0, 0, 0, time.UTC)
+	pending := &model.Payment{ID: 1, UserID: 1, Payment: 1234, Status: model.PaymentStatusPending, CreatedBy: sql.NullInt64{Int64: 1, Valid: true}}
+
+	tests := []struct {
+		name      string
+		userID    int
+		paymentID int
+		found     *model.Payment
+		findErr   error
+		mock      *model.Payment
+		mockErr   error
+		want      *model.Payment
+		wantErr   error
+	}{
+		{
+			name:      "Success",
+			userID:    1,
+			paymentID: 1,
+			found:     pending,
+			mock:      &model.Payment{ID: 1, UserID: 1, Payment: 1234, Status: model.PaymentStatusApproved, UpdatedAt: updatedAt},
+			want:      &model.Payment{ID: 1, UserID: 1, Payment: 1234, Status: model.PaymentStatusApproved, UpdatedAt: updatedAt},
+		},
+		{
+			name:      "NotFound error",
+			userID:    1,
+			paymentID: 999,
+			findErr:   repository.ErrNotFound,
+			wantErr:   usecase.NotFoundError{},
+		},
+		{
+			name:      "NotFound error other user's payment",
+			userID:    2,
+			paymentID: 1,
+			findErr:   repository.ErrNotFound,
+			wantErr:   usecase.NotFoundError{},
+		},
+		{
+			name:      "Conflict error not pending",
+			userID:    1,
+			paymentID: 1,
+			found:     pending,
+			mockErr:   repository.ErrConflict,
+			wantErr:   usecase.ConflictError{},
+		},
+		{
+			name:      "Forbidden error own payment",
+			userID:    1,
+			paymentID: 1,
+			found:     &model.Payment{ID: 1, UserID: 1, Payment: 1234, Status: model.PaymentStatusPending, CreatedBy: sql.NullInt64{Int64: 2, Valid: true}},
+			wantErr:   usecase.ForbiddenError{},
+		},
+		{
+			name:      "Forbidden error own payment in repository",
+			userID:    1,
+			paymentID: 1,
+			found:     pending,
+			mockErr:   repository.ErrForbidden,
+			wantErr:   usecase.ForbiddenError{},
+		},
+		{
+			name:      "Repository error",
+			userID:    1,
+			paymentID: 1,
+			found:     pending,
+			mockErr:   errors.New("repository error"),
+			wantErr:   usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockPaymentRepository{}
+			m.On("FindByID", tt.userID, tt.paymentID).Return(tt.found, tt.findErr)
+			m.On("UpdateStatus", tt.userID, 2, tt.paymentID, model.PaymentStatusApproved).Return(tt.mock, tt.mockErr)
+
+			u := usecase.NewPaymentUseCase(m, testPageSize)
+			got, err := u.Approve(context.Background(), tt.userID, 2, tt.paymentID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Approve() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPaymentsUseCase_Reject(t *testing.T) {
+	m := &mockPaymentRepository{}
+	m.On("FindByID", 1, 1).Return(&model.Payment{ID: 1, UserID: 1, Status: model.PaymentStatusPending}, nil)
+	m.On("UpdateStatus", 1, 2, 1, model.PaymentStatusRejected).Return(&model.Payment{ID: 1, UserID: 1, Status: model.PaymentStatusRejected}, nil)
+
+	u := usecase.NewPaymentUseCase(m, testPageSize)
+	got, err := u.Reject(context.Background(), 1, 2, 1)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+	if diff := cmp.Diff(&model.Payment{ID: 1, UserID: 1, Status: model.PaymentStatusRejected}, got); diff != "" {
+		t.Errorf("Reject() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPaymentsUseCase_FetchMonthlyCost(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int
+		mockWant []*model.MonthlyCategoryPayment
+		mockErr  error
+		want     *usecase.MonthlyCosts
+		wantErr  error
+	}{
+		{
+			name:   "Success",
+			userID: 1,
+			mockWant: []*model.MonthlyCategoryPayment{
+				{YearMonth: "2020-05", CategoryID: 1, CategoryName: "家賃", PayerID: 2, Payment: 80000, Count: 1},
+				{YearMonth: "2020-04", CategoryID: 1, CategoryName: "家賃", PayerID: 2, Payment: 80000, Count: 1},
+				{YearMonth: "2020-04", CategoryID: 2, CategoryName: "食費", PayerID: 1, Payment: 3000, Count: 2},
+				{YearMonth: "2020-04", CategoryID: 2, CategoryName: "食費", PayerID: 2, Payment: 1500, Count: 1},
+			},
+			want: &usecase.MonthlyCosts{
+				MonthlyCosts: []*usecase.MonthlyCost{
+					{
+						YearMonth: "2020-05",
+						Total:     80000,
+						Count:     1,
+						Payers: []*usecase.PayerCost{
+							{PayerID: 2, Total: 80000, Count: 1},
+						},
+						Categories: []*usecase.CategoryCost{
+							{CategoryID: 1, CategoryName: "家賃", Total: 80000, Count: 1},
+						},
+					},
+					{
+						YearMonth: "2020-04",
+						Total:     84500,
+						Count:     4,
+						Payers: []*usecase.PayerCost{
+							{PayerID: 1, Total: 3000, Count: 2},
+							{PayerID: 2, Total: 81500, Count: 2},
+						},
+						Categories: []*usecase.CategoryCost{
+							{CategoryID: 1, CategoryName: "家賃", Total: 80000, Count: 1},
+							{CategoryID: 2, CategoryName: "食費", Total: 4500, Count: 3},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "Success no payments",
+			userID:   1,
+			mockWant: []*model.MonthlyCategoryPayment{},
+			want: &usecase.MonthlyCosts{
+				MonthlyCosts: []*usecase.MonthlyCost{},
+			},
+		},
+		{
+			name:     "Repository error",
+			userID:   1,
+			mockWant: []*model.MonthlyCategoryPayment{},
+			mockErr:  errors.New("repository error"),
+			wantErr:  usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockPaymentRepository{}
+			m.On("FetchMonthlyCosts", tt.userID).Return(tt.mockWant, tt.mockErr)
+
+			u := usecase.NewPaymentUseCase(m, testPageSize)
+			got, err := u.FetchMonthlyCost(context.Background(), tt.userID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("FetchMonthlyCost() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPaymentsUseCase_Export(t *testing.T) {
+	date := func(day int) time.Time {
+		return time.Date(2020, time.April, day, 0, 0, 0, 0, time.UTC)
+	}
+	// 1ページ目は取得件数の上限まで返し、2ページ目で終わる
+	firstPage := make([]*model.Payment, 0, 500)
+	for i := 0; i < 500; i++ {
+		firstPage = append(firstPage, &model.Payment{ID: 1000 - i, CategoryName: "食費", PayerName: "パートナー", PaymentDate: date(2), Payment: 100})
+	}
+	secondPage := []*model.Payment{
+		{ID: 1, CategoryName: "日用品", PayerName: "ユーザー", Description: sql.NullString{String: "ドラッグストア", Valid: true}, PaymentDate: date(1), Payment: 500},
+	}
+
+	tests := []struct {
+		name      string
+		param     *usecase.GetPaymentsParam
+		mockErr   error
+		wantCount int
+		wantLast  *usecase.ExportPayment
+		wantErr   error
+	}{
+		{
+			name:      "Success",
+			param:     &usecase.GetPaymentsParam{Month: "2020-04"},
+			wantCount: 501,
+			wantLast:  &usecase.ExportPayment{PaymentDate: "2020-04-01", CategoryName: "日用品", PayerName: "ユーザー", Payment: 500, Description: "ドラッグストア"},
+		},
+		{
+			name:    "Invalid param error",
+			param:   &usecase.GetPaymentsParam{Month: "2020-04", From: "2020-04-01"},
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name:    "Repository error",
+			param:   &usecase.GetPaymentsParam{Month: "2020-04"},
+			mockErr: errors.New("repository error"),
+			wantErr: usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockPaymentRepository{}
+			m.On("GetData", 1, mock.Anything, (*model.PaymentCursor)(nil), 500).Return(firstPage, tt.mockErr)
+			m.On("GetData", 1, mock.Anything, mock.MatchedBy(func(c *model.PaymentCursor) bool {
+				return c != nil && c.ID == 501 && c.PaymentDate.Equal(date(2))
+			}), 500).Return(secondPage, nil)
+
+			u := usecase.NewPaymentUseCase(m, testPageSize)
+			var got []*usecase.ExportPayment
+			err := u.Export(context.Background(), 1, tt.param, func(p *usecase.ExportPayment) error {
+				got = append(got, p)
+				return nil
+			})
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("Export() count = %d, want %d", len(got), tt.wantCount)
+				return
+			}
+			if diff := cmp.Diff(tt.wantLast, got[len(got)-1]); diff != "" {
+				t.Errorf("Export() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 type mockPaymentRepository struct {
 	mock.Mock
 }
 
-func (m *mockPaymentRepository) GetData(userID, cursor int) ([]*model.Payment, error) {
-	ret := m.Called(userID, cursor)
+func (m *mockPaymentRepository) GetData(ctx context.Context, userID int, filter *model.PaymentFilter, cursor *model.PaymentCursor, limit int) ([]*model.Payment, error) {
+	ret := m.Called(userID, filter, cursor, limit)
 	return ret.Get(0).([]*model.Payment), ret.Error(1)
 }
 
-func (m *mockPaymentRepository) Create(mp *model.Payment) (*model.Payment, error) {
+func (m *mockPaymentRepository) FindByID(ctx context.Context, userID, paymentID int) (*model.Payment, error) {
+	ret := m.Called(userID, paymentID)
+	if f, ok := ret.Get(0).(func() *model.Payment); ok {
+		return f(), ret.Error(1)
+	}
+	return ret.Get(0).(*model.Payment), ret.Error(1)
+}
+
+func (m *mockPaymentRepository) Create(ctx context.Context, mp *model.Payment) (*model.Payment, error) {
 	ret := m.Called(mp)
 	return ret.Get(0).(*model.Payment), ret.Error(1)
 }
 
-func (m *mockPaymentRepository) Update(mp *model.Payment) (*model.Payment, error) {
+func (m *mockPaymentRepository) Update(ctx context.Context, mp *model.Payment) (*model.Payment, error) {
 	ret := m.Called(mp)
+	if f, ok := ret.Get(0).(func() *model.Payment); ok {
+		return f(), ret.Error(1)
+	}
 	return ret.Get(0).(*model.Payment), ret.Error(1)
 }
 
-func (m *mockPaymentRepository) DeleteByID(userID, paymentID int) error {
-	ret := m.Called(userID, paymentID)
+func (m *mockPaymentRepository) DeleteByID(ctx context.Context, userID, paymentID int, updatedAt time.Time) error {
+	ret := m.Called(userID, paymentID, updatedAt)
 	return ret.Error(0)
 }
 
-func (m *mockPaymentRepository) FetchDate(userID int) ([]*string, error) {
+func (m *mockPaymentRepository) UpdateStatus(ctx context.Context, userID, reviewerID, paymentID int, status string) (*model.Payment, error) {
+	ret := m.Called(userID, reviewerID, paymentID, status)
+	return ret.Get(0).(*model.Payment), ret.Error(1)
+}
+
+func (m *mockPaymentRepository) FetchMonthlyCosts(ctx context.Context, userID int) ([]*model.MonthlyCategoryPayment, error) {
 	ret := m.Called(userID)
-	return ret.Get(0).([]*string), ret.Error(1)
+	return ret.Get(0).([]*model.MonthlyCategoryPayment), ret.Error(1)
+}
+
+func (m *mockPaymentRepository) CountDuplicates(ctx context.Context, mp *model.Payment) (int, error) {
+	ret := m.Called(mp)
+	return ret.Int(0), ret.Error(1)
 }
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"log"
+	"sort"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/usecase/util"
+)
+
+type SettlementUseCase interface {
+	GetData(userID int, yearMonth string) (*Settlement, error)
+}
+
+func NewSettlementUseCase(r repository.SettlementRepository) *settlementUsecase {
+	return &settlementUsecase{r}
+}
+
+var _ SettlementUseCase = &settlementUsecase{}
+
+type settlementUsecase struct {
+	SettlementRepository repository.SettlementRepository
+}
+
+// Settlement : 月の精算結果。DebtorPayerIDの支払者がCreditorPayerIDの支払者へAmountを支払う
+type Settlement struct {
+	YearMonth       string                `json:"year_month"`
+	Proportion      int                   `json:"proportion"`
+	Total           int                   `json:"total"`
+	UserPayment     int                   `json:"user_payment"`
+	PartnerPayment  int                   `json:"partner_payment"`
+	UserBurden      int                   `json:"user_burden"`
+	PartnerBurden   int                   `json:"partner_burden"`
+	DebtorPayerID   int                   `json:"debtor_payer_id"`
+	CreditorPayerID int                   `json:"creditor_payer_id"`
+	Amount          int                   `json:"amount"`
+	Categories      []*CategorySettlement `json:"categories"`
+}
+
+type CategorySettlement struct {
+	CategoryID     int    `json:"category_id"`
+	CategoryName   string `json:"category_name"`
+	Total          int    `json:"total"`
+	UserPayment    int    `json:"user_payment"`
+	PartnerPayment int    `json:"partner_payment"`
+	UserBurden     int    `json:"user_burden"`
+	PartnerBurden  int    `json:"partner_burden"`
+}
+
+func (u *settlementUsecase) GetData(userID int, yearMonth string) (*Settlement, error) {
+	from, err := util.ParseJSTYearMonth(yearMonth)
+	if err != nil {
+		log.Println("invalid year month")
+		return nil, InvalidParamError{}
+	}
+	to := from.AddDate(0, 1, 0)
+
+	proportion, err := u.SettlementRepository.FetchProportion(userID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	cp, err := u.SettlementRepository.FetchCategoryPayments(userID, from, to)
+	if err != nil {
+		log.Println("repository error")
+		return nil, InternalServerError{}
+	}
+
+	s := &Settlement{
+		YearMonth:  yearMonth,
+		Proportion: proportion,
+		Categories: make([]*CategorySettlement, 0),
+	}
+
+	categories := map[int]*CategorySettlement{}
+	for _, v := range cp {
+		c, ok := categories[v.CategoryID]
+		if !ok {
+			c = &CategorySettlement{
+				CategoryID:   v.CategoryID,
+				CategoryName: v.CategoryName,
+			}
+			categories[v.CategoryID] = c
+			s.Categories = append(s.Categories, c)
+		}
+
+		switch v.PayerID {
+		case model.PayerUser:
+			c.UserPayment += v.Payment
+		case model.PayerPartner:
+			c.PartnerPayment += v.Payment
+		}
+	}
+
+	for _, c := range s.Categories {
+		c.Total = c.UserPayment + c.PartnerPayment
+
+		s.UserPayment += c.UserPayment
+		s.PartnerPayment += c.PartnerPayment
+	}
+
+	s.Total = s.UserPayment + s.PartnerPayment
+	s.UserBurden, s.PartnerBurden = burden(s.Total, proportion)
+	allocateBurden(s.Categories, proportion, s.UserBurden)
+
+	// 負担額より多く支払っている側が受け取る
+	switch diff := s.UserPayment - s.UserBurden; {
+	case diff > 0:
+		s.DebtorPayerID = model.PayerPartner
+		s.CreditorPayerID = model.PayerUser
+		s.Amount = diff
+	case diff < 0:
+		s.DebtorPayerID = model.PayerUser
+		s.CreditorPayerID = model.PayerPartner
+		s.Amount = -diff
+	}
+
+	return s, nil
+}
+
+// burden : 合計金額をユーザーの負担割合(%)で按分する。端数はパートナー側の負担とする
+func burden(total, proportion int) (int, int) {
+	user := total * proportion / 100
+	return user, total - user
+}
+
+// allocateBurden : カテゴリーごとの負担額の合計が全体の負担額と一致するように按分する。
+// カテゴリーごとに切り捨てた端数の合計は、切り捨てた額が大きいカテゴリーから順に1円ずつユーザーの負担に加える
+func allocateBurden(categories []*CategorySettlement, proportion, userBurden int) {
+	remainder := userBurden
+	for _, c := range categories {
+		c.UserBurden, c.PartnerBurden = burden(c.Total, proportion)
+		remainder -= c.UserBurden
+	}
+	if remainder <= 0 {
+		return
+	}
+
+	order := make([]*CategorySettlement, len(categories))
+	copy(order, categories)
+	sort.SliceStable(order, func(i, j int) bool {
+		return order[i].Total*proportion%100 > order[j].Total*proportion%100
+	})
+	for _, c := range order[:remainder] {
+		c.UserBurden++
+		c.PartnerBurden--
+	}
+}
@@ -0,0 +1,191 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_settlementUsecase_GetData(t *testing.T) {
+	jst := time.FixedZone("Asia/Tokyo", 9*60*60)
+	from := time.Date(2020, time.April, 1, 0, 0, 0, 0, jst)
+	to := time.Date(2020, time.May, 1, 0, 0, 0, 0, jst)
+
+	tests := []struct {
+		name          string
+		userID        int
+		yearMonth     string
+		proportion    int
+		proportionErr error
+		mockWant      []*model.CategoryPayment
+		mockErr       error
+		want          *usecase.Settlement
+		wantErr       error
+	}{
+		{
+			name:       "Success partner owes user",
+			userID:     1,
+			yearMonth:  "2020-04",
+			proportion: 50,
+			mockWant: []*model.CategoryPayment{
+				{CategoryID: 1, CategoryName: "家賃", PayerID: model.PayerUser, Payment: 100000},
+				{CategoryID: 2, CategoryName: "食費", PayerID: model.PayerUser, Payment: 3001},
+				{CategoryID: 2, CategoryName: "食費", PayerID: model.PayerPartner, Payment: 5000},
+			},
+			want: &usecase.Settlement{
+				YearMonth:       "2020-04",
+				Proportion:      50,
+				Total:           108001,
+				UserPayment:     103001,
+				PartnerPayment:  5000,
+				UserBurden:      54000,
+				PartnerBurden:   54001,
+				DebtorPayerID:   model.PayerPartner,
+				CreditorPayerID: model.PayerUser,
+				Amount:          49001,
+				Categories: []*usecase.CategorySettlement{
+					{CategoryID: 1, CategoryName: "家賃", Total: 100000, UserPayment: 100000, PartnerPayment: 0, UserBurden: 50000, PartnerBurden: 50000},
+					{CategoryID: 2, CategoryName: "食費", Total: 8001, UserPayment: 3001, PartnerPayment: 5000, UserBurden: 4000, PartnerBurden: 4001},
+				},
+			},
+		},
+		{
+			name:       "Success category burdens add up to total burden",
+			userID:     1,
+			yearMonth:  "2020-04",
+			proportion: 50,
+			mockWant: []*model.CategoryPayment{
+				{CategoryID: 1, CategoryName: "家賃", PayerID: model.PayerUser, Payment: 1001},
+				{CategoryID: 2, CategoryName: "食費", PayerID: model.PayerUser, Payment: 1001},
+				{CategoryID: 3, CategoryName: "日用品", PayerID: model.PayerUser, Payment: 1001},
+			},
+			want: &usecase.Settlement{
+				YearMonth:       "2020-04",
+				Proportion:      50,
+				Total:           3003,
+				UserPayment:     3003,
+				PartnerPayment:  0,
+				UserBurden:      1501,
+				PartnerBurden:   1502,
+				DebtorPayerID:   model.PayerPartner,
+				CreditorPayerID: model.PayerUser,
+				Amount:          1502,
+				Categories: []*usecase.CategorySettlement{
+					{CategoryID: 1, CategoryName: "家賃", Total: 1001, UserPayment: 1001, PartnerPayment: 0, UserBurden: 501, PartnerBurden: 500},
+					{CategoryID: 2, CategoryName: "食費", Total: 1001, UserPayment: 1001, PartnerPayment: 0, UserBurden: 500, PartnerBurden: 501},
+					{CategoryID: 3, CategoryName: "日用品", Total: 1001, UserPayment: 1001, PartnerPayment: 0, UserBurden: 500, PartnerBurden: 501},
+				},
+			},
+		},
+		{
+			name:       "Success user owes partner",
+			userID:     1,
+			yearMonth:  "2020-04",
+			proportion: 70,
+			mockWant: []*model.CategoryPayment{
+				{CategoryID: 1, CategoryName: "家賃", PayerID: model.PayerPartner, Payment: 10000},
+			},
+			want: &usecase.Settlement{
+				YearMonth:       "2020-04",
+				Proportion:      70,
+				Total:           10000,
+				UserPayment:     0,
+				PartnerPayment:  10000,
+				UserBurden:      7000,
+				PartnerBurden:   3000,
+				DebtorPayerID:   model.PayerUser,
+				CreditorPayerID: model.PayerPartner,
+				Amount:          7000,
+				Categories: []*usecase.CategorySettlement{
+					{CategoryID: 1, CategoryName: "家賃", Total: 10000, UserPayment: 0, PartnerPayment: 10000, UserBurden: 7000, PartnerBurden: 3000},
+				},
+			},
+		},
+		{
+			name:       "Success no payments",
+			userID:     1,
+			yearMonth:  "2020-04",
+			proportion: 50,
+			mockWant:   []*model.CategoryPayment{},
+			want: &usecase.Settlement{
+				YearMonth:  "2020-04",
+				Proportion: 50,
+				Categories: []*usecase.CategorySettlement{},
+			},
+		},
+		{
+			name:      "InvalidParam error",
+			userID:    1,
+			yearMonth: "2020-4-1",
+			wantErr:   usecase.InvalidParamError{},
+		},
+		{
+			name:          "NotFound error",
+			userID:        1,
+			yearMonth:     "2020-04",
+			proportionErr: repository.ErrNotFound,
+			wantErr:       usecase.NotFoundError{},
+		},
+		{
+			name:       "Repository error",
+			userID:     1,
+			yearMonth:  "2020-04",
+			proportion: 50,
+			mockWant:   []*model.CategoryPayment{},
+			mockErr:    errors.New("repository error"),
+			wantErr:    usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockSettlementRepository{}
+			m.On("FetchProportion", tt.userID).Return(tt.proportion, tt.proportionErr)
+			m.On("FetchCategoryPayments", tt.userID, mock.MatchedBy(from.Equal), mock.MatchedBy(to.Equal)).Return(tt.mockWant, tt.mockErr)
+
+			u := usecase.NewSettlementUseCase(m)
+			got, err := u.GetData(tt.userID, tt.yearMonth)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetData() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type mockSettlementRepository struct {
+	mock.Mock
+}
+
+func (m *mockSettlementRepository) FetchProportion(userID int) (int, error) {
+	ret := m.Called(userID)
+	return ret.Int(0), ret.Error(1)
+}
+
+func (m *mockSettlementRepository) FetchCategoryPayments(userID int, from, to time.Time) ([]*model.CategoryPayment, error) {
+	ret := m.Called(userID, from, to)
+	return ret.Get(0).([]*model.CategoryPayment), ret.Error(1)
+}
@@ -0,0 +1,125 @@
+package statement
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+
+	"github.com/warikan/api/usecase/util"
+)
+
+const (
+	EncodingUTF8     = "utf-8"
+	EncodingShiftJIS = "shift_jis"
+)
+
+// CSVFormat : カード会社ごとのCSVの列の対応。列の番号は0始まり。
+// SkipRowsは明細の前の見出しなどの行数、DateLayoutが空の場合はyyyy/M/d形式またはyyyy-M-d形式として読み込む。
+// 利用日が空の行は合計などの明細以外の行として読み飛ばす
+type CSVFormat struct {
+	Name              string
+	Encoding          string
+	SkipRows          int
+	DateColumn        int
+	DateLayout        string
+	AmountColumn      int
+	DescriptionColumn int
+}
+
+// DefaultCSVFormats : 設定ファイルで同じ名前の形式を指定した場合は設定ファイルの形式を使う
+var DefaultCSVFormats = []*CSVFormat{
+	// 三井住友カード(Vpass): 1行目は会員名とカード名、以降は利用日,利用店名,利用金額,支払区分,...
+	{Name: "smbc", Encoding: EncodingShiftJIS, SkipRows: 1, DateColumn: 0, AmountColumn: 2, DescriptionColumn: 1},
+	// 楽天カード(楽天e-NAVI): 利用日,利用店名・商品名,利用者,支払方法,利用金額,...
+	{Name: "rakuten", Encoding: EncodingUTF8, SkipRows: 1, DateColumn: 0, AmountColumn: 4, DescriptionColumn: 1},
+}
+
+// Validate : 名前・文字コード・列の番号を確認する。設定ファイルの形式の確認に使う
+func (f *CSVFormat) Validate() error {
+	if f.Name == "" {
+		return fmt.Errorf("statement: csv format name is empty")
+	}
+	switch strings.ToLower(f.Name) {
+	case FormatOFX, FormatQIF:
+		return fmt.Errorf("statement: csv format name %q is reserved", f.Name)
+	}
+	switch strings.ToLower(f.Encoding) {
+	case "", EncodingUTF8, EncodingShiftJIS:
+	default:
+		return fmt.Errorf("statement: unsupported encoding %q in csv format %q", f.Encoding, f.Name)
+	}
+	if f.SkipRows < 0 || f.DateColumn < 0 || f.AmountColumn < 0 || f.DescriptionColumn < 0 {
+		return fmt.Errorf("statement: negative row or column in csv format %q", f.Name)
+	}
+	return nil
+}
+
+// Parse : 利用金額は支出を正とする
+func (f *CSVFormat) Parse(r io.Reader) ([]*Transaction, error) {
+	if strings.ToLower(f.Encoding) == EncodingShiftJIS {
+		r = transform.NewReader(r, japanese.ShiftJIS.NewDecoder())
+	} else {
+		br := bufio.NewReader(r)
+		if b, err := br.Peek(3); err == nil && string(b) == "\xEF\xBB\xBF" {
+			// nolint:errcheck
+			br.Discard(3)
+		}
+		r = br
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	txs := make([]*Transaction, 0)
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &ParseError{Line: line, Message: err.Error()}
+		}
+		if line <= f.SkipRows {
+			continue
+		}
+
+		s := f.column(record, f.DateColumn)
+		if s == "" {
+			continue
+		}
+		date, err := f.parseDate(s)
+		if err != nil {
+			return nil, &ParseError{Line: line, Message: "invalid date: " + s}
+		}
+
+		amount, err := parseAmount(f.column(record, f.AmountColumn))
+		if err != nil {
+			return nil, &ParseError{Line: line, Message: "invalid amount: " + f.column(record, f.AmountColumn)}
+		}
+
+		txs = append(txs, &Transaction{Date: date, Amount: amount, Description: f.column(record, f.DescriptionColumn)})
+	}
+
+	return txs, nil
+}
+
+func (f *CSVFormat) column(record []string, i int) string {
+	if i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func (f *CSVFormat) parseDate(s string) (time.Time, error) {
+	if f.DateLayout == "" {
+		return util.ParseJSTSpreadsheetDate(s)
+	}
+	return util.ParseJSTTime(f.DateLayout, s)
+}
@@ -0,0 +1,133 @@
+package statement_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+
+	"github.com/warikan/api/usecase/statement"
+)
+
+func shiftJIS(t *testing.T, s string) []byte {
+	var b bytes.Buffer
+	w := transform.NewWriter(&b, japanese.ShiftJIS.NewEncoder())
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestCSVFormat_Parse(t *testing.T) {
+	formats := map[string]*statement.CSVFormat{}
+	for _, f := range statement.DefaultCSVFormats {
+		formats[f.Name] = f
+	}
+
+	tests := []struct {
+		name    string
+		format  *statement.CSVFormat
+		csv     []byte
+		want    []*statement.Transaction
+		wantErr bool
+	}{
+		{
+			name:   "Shift_JIS",
+			format: formats["smbc"],
+			csv: shiftJIS(t, "山田　太郎　様,4980-****-****-****,三井住友カード\r\n"+
+				"2020/04/01,スーパー,\"1,234\",１回払い,,1234,\r\n"+
+				"2020/04/05,ドラッグストア,-500,１回払い,,-500,返品\r\n"+
+				",,,,,734,\r\n"),
+			want: []*statement.Transaction{
+				{Date: time.Date(2020, time.April, 1, 0, 0, 0, 0, jst), Amount: 1234, Description: "スーパー"},
+				{Date: time.Date(2020, time.April, 5, 0, 0, 0, 0, jst), Amount: -500, Description: "ドラッグストア"},
+			},
+		},
+		{
+			name:   "UTF-8 with BOM",
+			format: formats["rakuten"],
+			csv: []byte("\xEF\xBB\xBF\"利用日\",\"利用店名・商品名\",\"利用者\",\"支払方法\",\"利用金額\"\n" +
+				"\"2020/04/10\",\"電気料金\",\"本人\",\"1回払い\",\"3000\"\n"),
+			want: []*statement.Transaction{
+				{Date: time.Date(2020, time.April, 10, 0, 0, 0, 0, jst), Amount: 3000, Description: "電気料金"},
+			},
+		},
+		{
+			name:   "Custom layout",
+			format: &statement.CSVFormat{Name: "custom", DateColumn: 1, DateLayout: "20060102", AmountColumn: 0, DescriptionColumn: 5},
+			csv:    []byte("100,20200401,x\n"),
+			want: []*statement.Transaction{
+				{Date: time.Date(2020, time.April, 1, 0, 0, 0, 0, jst), Amount: 100, Description: ""},
+			},
+		},
+		{
+			name:    "Invalid date",
+			format:  formats["smbc"],
+			csv:     shiftJIS(t, "header\r\n2020/13/01,スーパー,100\r\n"),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid amount",
+			format:  formats["rakuten"],
+			csv:     []byte("header\n2020/04/01,スーパー,本人,1回払い,abc\n"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.format.Parse(bytes.NewReader(tt.csv))
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Parse() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCSVFormat_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  *statement.CSVFormat
+		wantErr bool
+	}{
+		{name: "Default formats", format: statement.DefaultCSVFormats[0]},
+		{name: "Upper case encoding", format: &statement.CSVFormat{Name: "x", Encoding: "Shift_JIS"}},
+		{name: "Empty name", format: &statement.CSVFormat{}, wantErr: true},
+		{name: "Reserved name ofx", format: &statement.CSVFormat{Name: "ofx"}, wantErr: true},
+		{name: "Reserved name upper case qif", format: &statement.CSVFormat{Name: "QIF"}, wantErr: true},
+		{name: "Unsupported encoding", format: &statement.CSVFormat{Name: "x", Encoding: "euc-jp"}, wantErr: true},
+		{name: "Negative column", format: &statement.CSVFormat{Name: "x", AmountColumn: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.format.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
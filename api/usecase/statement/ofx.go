@@ -0,0 +1,139 @@
+package statement
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/transform"
+
+	"github.com/warikan/api/usecase/util"
+)
+
+var ofxEntities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&nbsp;", " ", "&amp;", "&")
+
+// ofxCharset : OFX 1.xのCHARSETヘッダとOFX 2.xのXML宣言のencoding
+var ofxCharset = regexp.MustCompile(`(?i)(?:^|\n)\s*CHARSET:\s*([^\s]+)|encoding\s*=\s*["']([^"']+)["']`)
+
+// ParseOFX : OFXの取引(STMTTRN)を読み込む。終了タグを省略するOFX 1.x(SGML)とOFX 2.x(XML)のどちらも読み込める。
+// ヘッダの文字コードがShift_JISであれば変換してから読む。
+// TRNAMTは入金を正とするため、符号を反転して返す
+func ParseOFX(r io.Reader) ([]*Transaction, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if isShiftJIS(ofxHeaderCharset(b)) {
+		if b, _, err = transform.Bytes(japanese.ShiftJIS.NewDecoder(), b); err != nil {
+			return nil, &ParseError{Message: "invalid Shift_JIS: " + err.Error()}
+		}
+	}
+	s := string(b)
+
+	start := strings.Index(strings.ToUpper(s), "<OFX>")
+	if start < 0 {
+		return nil, &ParseError{Message: "OFX element not found"}
+	}
+
+	txs := make([]*Transaction, 0)
+	var trn map[string]string
+	trnLine := 0
+	for pos := start; ; {
+		open := strings.IndexByte(s[pos:], '<')
+		if open < 0 {
+			break
+		}
+		open += pos
+		end := strings.IndexByte(s[open:], '>')
+		if end < 0 {
+			return nil, &ParseError{Line: lineAt(s, open), Message: "unterminated tag"}
+		}
+		end += open
+		tag := strings.ToUpper(strings.TrimSpace(s[open+1 : end]))
+		pos = end + 1
+		next := strings.IndexByte(s[pos:], '<')
+		if next < 0 {
+			next = len(s) - pos
+		}
+		value := ofxEntities.Replace(strings.TrimSpace(s[pos : pos+next]))
+
+		switch {
+		case tag == "STMTTRN":
+			trn = map[string]string{}
+			trnLine = lineAt(s, open)
+		case tag == "/STMTTRN":
+			if trn == nil {
+				return nil, &ParseError{Line: lineAt(s, open), Message: "unexpected </STMTTRN>"}
+			}
+			tx, err := ofxTransaction(trn, trnLine)
+			if err != nil {
+				return nil, err
+			}
+			txs = append(txs, tx)
+			trn = nil
+		case trn != nil && !strings.HasPrefix(tag, "/") && value != "":
+			trn[tag] = value
+		}
+	}
+	if trn != nil {
+		return nil, &ParseError{Line: trnLine, Message: "STMTTRN is not closed"}
+	}
+
+	return txs, nil
+}
+
+func ofxTransaction(trn map[string]string, line int) (*Transaction, error) {
+	// DTPOSTEDはYYYYMMDDの後に時刻とタイムゾーンが続く場合がある。日付のみを利用日とする
+	posted := trn["DTPOSTED"]
+	if len(posted) < 8 {
+		return nil, &ParseError{Line: line, Message: "invalid DTPOSTED: " + posted}
+	}
+	date, err := util.ParseJSTTime("20060102", posted[:8])
+	if err != nil {
+		return nil, &ParseError{Line: line, Message: "invalid DTPOSTED: " + posted}
+	}
+
+	amount, err := parseAmount(trn["TRNAMT"])
+	if err != nil {
+		return nil, &ParseError{Line: line, Message: "invalid TRNAMT: " + trn["TRNAMT"]}
+	}
+
+	description := trn["NAME"]
+	if description == "" {
+		description = trn["MEMO"]
+	}
+
+	return &Transaction{Date: date, Amount: -amount, Description: description}, nil
+}
+
+func lineAt(s string, pos int) int {
+	return strings.Count(s[:pos], "\n") + 1
+}
+
+// ofxHeaderCharset : <OFX>より前のヘッダから文字コードを返す。指定がなければ空文字を返す
+func ofxHeaderCharset(b []byte) string {
+	header := b
+	if i := bytes.Index(bytes.ToUpper(b), []byte("<OFX>")); i >= 0 {
+		header = b[:i]
+	}
+	m := ofxCharset.FindSubmatch(header)
+	if m == nil {
+		return ""
+	}
+	if len(m[1]) > 0 {
+		return string(m[1])
+	}
+	return string(m[2])
+}
+
+// isShiftJIS : Shift_JISとその別名(CP932など)か
+func isShiftJIS(charset string) bool {
+	switch strings.ToLower(strings.Replace(charset, "-", "_", -1)) {
+	case EncodingShiftJIS, "sjis", "windows_31j", "cp932", "ms932", "932":
+		return true
+	}
+	return false
+}
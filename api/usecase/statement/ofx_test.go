@@ -0,0 +1,99 @@
+package statement_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/warikan/api/usecase/statement"
+)
+
+var jst, _ = time.LoadLocation("Asia/Tokyo")
+
+func TestParseOFX(t *testing.T) {
+	tests := []struct {
+		name    string
+		ofx     string
+		want    []*statement.Transaction
+		wantErr bool
+	}{
+		{
+			name: "SGML",
+			ofx: "OFXHEADER:100\nDATA:OFXSGML\nVERSION:102\n\n<OFX>\n<CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS><BANKTRANLIST>\n" +
+				"<STMTTRN>\n<TRNTYPE>DEBIT\n<DTPOSTED>20200401120000[+9:JST]\n<TRNAMT>-1,234\n<FITID>1\n<NAME>スーパー &amp; ドラッグ\n</STMTTRN>\n" +
+				"<STMTTRN>\n<TRNTYPE>CREDIT\n<DTPOSTED>20200405\n<TRNAMT>500.00\n<FITID>2\n<MEMO>返金\n</STMTTRN>\n" +
+				"</BANKTRANLIST></CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1>\n</OFX>\n",
+			want: []*statement.Transaction{
+				{Date: time.Date(2020, time.April, 1, 0, 0, 0, 0, jst), Amount: 1234, Description: "スーパー & ドラッグ"},
+				{Date: time.Date(2020, time.April, 5, 0, 0, 0, 0, jst), Amount: -500, Description: "返金"},
+			},
+		},
+		{
+			name: "SGML Shift_JIS",
+			ofx: string(shiftJIS(t, "OFXHEADER:100\r\nDATA:OFXSGML\r\nVERSION:102\r\nENCODING:USASCII\r\nCHARSET:SHIFT_JIS\r\n\r\n"+
+				"<OFX>\r\n<STMTTRN>\r\n<DTPOSTED>20200402\r\n<TRNAMT>-800\r\n<NAME>コンビニ\r\n</STMTTRN>\r\n</OFX>\r\n")),
+			want: []*statement.Transaction{
+				{Date: time.Date(2020, time.April, 2, 0, 0, 0, 0, jst), Amount: 800, Description: "コンビニ"},
+			},
+		},
+		{
+			name: "XML",
+			ofx: `<?xml version="1.0" encoding="UTF-8"?><?OFX OFXHEADER="200" VERSION="220"?>` +
+				`<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>` +
+				`<STMTTRN><TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20200410</DTPOSTED><TRNAMT>-3000</TRNAMT><NAME>電気料金</NAME></STMTTRN>` +
+				`</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`,
+			want: []*statement.Transaction{
+				{Date: time.Date(2020, time.April, 10, 0, 0, 0, 0, jst), Amount: 3000, Description: "電気料金"},
+			},
+		},
+		{
+			name: "No transactions",
+			ofx:  "<OFX><BANKTRANLIST></BANKTRANLIST></OFX>",
+			want: []*statement.Transaction{},
+		},
+		{
+			name:    "Not OFX",
+			ofx:     "date,amount\n2020-04-01,100\n",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid date",
+			ofx:     "<OFX>\n<STMTTRN>\n<DTPOSTED>2020\n<TRNAMT>-100\n</STMTTRN>\n</OFX>",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid amount",
+			ofx:     "<OFX>\n<STMTTRN>\n<DTPOSTED>20200401\n<TRNAMT>abc\n</STMTTRN>\n</OFX>",
+			wantErr: true,
+		},
+		{
+			name:    "Unclosed transaction",
+			ofx:     "<OFX>\n<STMTTRN>\n<DTPOSTED>20200401\n<TRNAMT>-100\n</OFX>",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := statement.ParseOFX(strings.NewReader(tt.ofx))
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseOFX() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package statement
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/warikan/api/usecase/util"
+)
+
+// ParseQIF : QIFの取引を読み込む。Tは入金を正とするため、符号を反転して返す
+func ParseQIF(r io.Reader) ([]*Transaction, error) {
+	sc := bufio.NewScanner(r)
+
+	txs := make([]*Transaction, 0)
+	fields := map[byte]string{}
+	start := 0
+	flush := func() error {
+		if len(fields) == 0 {
+			return nil
+		}
+		tx, err := qifTransaction(fields, start)
+		if err != nil {
+			return err
+		}
+		txs = append(txs, tx)
+		fields = map[byte]string{}
+		return nil
+	}
+
+	for line := 1; sc.Scan(); line++ {
+		s := strings.TrimSpace(sc.Text())
+		if line == 1 {
+			s = strings.TrimPrefix(s, "\uFEFF")
+		}
+		if s == "" || s[0] == '!' {
+			continue
+		}
+		if s[0] == '^' {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if len(fields) == 0 {
+			start = line
+		}
+		// 分割(S, E, $)は取引全体の金額と重複するため読み込まない
+		if _, ok := fields[s[0]]; !ok {
+			fields[s[0]] = strings.TrimSpace(s[1:])
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	// 最後の取引の^は省略される場合がある
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return txs, nil
+}
+
+func qifTransaction(fields map[byte]string, line int) (*Transaction, error) {
+	date, ok := parseQIFDate(fields['D'])
+	if !ok {
+		return nil, &ParseError{Line: line, Message: "invalid date: " + fields['D']}
+	}
+
+	s, ok := fields['T']
+	if !ok {
+		s = fields['U']
+	}
+	amount, err := parseAmount(s)
+	if err != nil {
+		return nil, &ParseError{Line: line, Message: "invalid amount: " + s}
+	}
+
+	description := fields['P']
+	if description == "" {
+		description = fields['M']
+	}
+
+	return &Transaction{Date: date, Amount: -amount, Description: description}, nil
+}
+
+// parseQIFDate : QIFの日付はアプリケーションによって形式が異なる。
+// 先頭が4桁の場合はyyyy/MM/dd、それ以外はMM/dd/yyとして読み込む。
+// Quickenの2桁の年は、MM/dd/yyの場合は1900年代、MM/dd'yyの場合は2000年以降を表す
+func parseQIFDate(s string) (time.Time, bool) {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '/' || r == '-' || r == '.' || r == '\'' || r == ' '
+	})
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	n := make([]int, 3)
+	for i, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return time.Time{}, false
+		}
+		n[i] = v
+	}
+
+	year, month, day := n[2], n[0], n[1]
+	if len(parts[0]) == 4 {
+		year, month, day = n[0], n[1], n[2]
+	} else if len(parts[2]) <= 2 {
+		year += 1900
+		if strings.ContainsRune(s, '\'') {
+			year += 100
+		}
+	}
+
+	t, err := util.ParseJSTTime("2006-1-2", fmt.Sprintf("%04d-%d-%d", year, month, day))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,74 @@
+package statement_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/warikan/api/usecase/statement"
+)
+
+func TestParseQIF(t *testing.T) {
+	tests := []struct {
+		name    string
+		qif     string
+		want    []*statement.Transaction
+		wantErr bool
+	}{
+		{
+			name: "Success",
+			qif: "!Type:CCard\n" +
+				"D04/01/2020\nT-1,234.00\nPスーパー\nM食料品\n^\n" +
+				"D4/ 5'20\nU500\nM返金\n^\n" +
+				"D2020/04/10\nT-3000\nP電気料金\nSUtilities\n$-3000\n^\n" +
+				"D12/31/99\nT-800\nP書店\n^\n" +
+				"D1/ 2' 5\nT-900\nP薬局\n^\n",
+			want: []*statement.Transaction{
+				{Date: time.Date(2020, time.April, 1, 0, 0, 0, 0, jst), Amount: 1234, Description: "スーパー"},
+				{Date: time.Date(2020, time.April, 5, 0, 0, 0, 0, jst), Amount: -500, Description: "返金"},
+				{Date: time.Date(2020, time.April, 10, 0, 0, 0, 0, jst), Amount: 3000, Description: "電気料金"},
+				{Date: time.Date(1999, time.December, 31, 0, 0, 0, 0, jst), Amount: 800, Description: "書店"},
+				{Date: time.Date(2005, time.January, 2, 0, 0, 0, 0, jst), Amount: 900, Description: "薬局"},
+			},
+		},
+		{
+			name: "Empty",
+			qif:  "!Type:Bank\n",
+			want: []*statement.Transaction{},
+		},
+		{
+			name:    "Invalid date",
+			qif:     "!Type:Bank\nD02/30/2020\nT-100\n^\n",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid amount",
+			qif:     "!Type:Bank\nD04/01/2020\nTabc\n^\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := statement.ParseQIF(strings.NewReader(tt.qif))
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, but got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseQIF() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Package statement : 銀行やクレジットカードの利用明細を読み込む
+package statement
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 組み込みの明細の形式名。CSVの形式にはこれらの名前を使えない
+const (
+	FormatOFX = "ofx"
+	FormatQIF = "qif"
+)
+
+// Transaction : 明細の1件。Amountは支出を正、返金や入金を負とする
+type Transaction struct {
+	Date        time.Time
+	Amount      int
+	Description string
+}
+
+// ParseError : Lineは明細ファイルの行番号。行を特定できない場合は0
+type ParseError struct {
+	Line    int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return "statement: " + e.Message
+	}
+	return fmt.Sprintf("statement: line %d: %s", e.Line, e.Message)
+}
+
+// parseAmount : 桁区切りや通貨記号の付いた金額を円単位に丸めて返す
+func parseAmount(s string) (int, error) {
+	s = strings.NewReplacer(",", "", "¥", "", "￥", "", "円", "", " ", "", "　", "").Replace(s)
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.Abs(f) > math.MaxInt32 {
+		return 0, fmt.Errorf("amount out of range: %s", s)
+	}
+	return int(math.Round(f)), nil
+}
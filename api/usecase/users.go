@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+)
+
+type UserUseCase interface {
+	GetData(userID int) (*model.User, error)
+	Update(req *UpdateUserParam, userID int) (*model.User, error)
+	DeleteByID(ctx context.Context, userID int) error
+	Purge(ctx context.Context, retention time.Duration) (int, error)
+}
+
+func NewUserUseCase(r repository.UserRepository, tx repository.TxManager) *userUsecase {
+	return &userUsecase{r, tx}
+}
+
+var _ UserUseCase = &userUsecase{}
+
+type userUsecase struct {
+	UserRepository repository.UserRepository
+	TxManager      repository.TxManager
+}
+
+// UpdateUserParam : 指定された項目のみ更新する。Languageに空文字を指定した場合はAccept-Languageヘッダーの言語に戻す
+type UpdateUserParam struct {
+	UserName     *string `json:"user_name" validate:"omitempty,min=1"`
+	PartnerName  *string `json:"partner_name" validate:"omitempty,min=1"`
+	Email        *string `json:"email" validate:"omitempty,email"`
+	UserImage    *string `json:"user_image"`
+	PartnerImage *string `json:"partner_image"`
+	Proportion   *int    `json:"proportion" validate:"omitempty,min=0,max=100"`
+	Language     *string `json:"language" validate:"omitempty,oneof=ja en"`
+}
+
+func (u *userUsecase) GetData(userID int) (*model.User, error) {
+	user, err := u.UserRepository.FindByID(userID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	return user, nil
+}
+
+func (u *userUsecase) Update(param *UpdateUserParam, userID int) (*model.User, error) {
+	if err := validateParam(param); err != nil {
+		return nil, err
+	}
+
+	user, err := u.GetData(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if param.Email != nil && *param.Email != user.Email {
+		other, err := u.UserRepository.FindByEmail(*param.Email)
+		if err == nil && other.ID != userID {
+			return nil, ConflictError{}
+		}
+		if err != nil && errors.Cause(err) != repository.ErrNotFound {
+			return nil, repositoryError(err)
+		}
+		user.Email = *param.Email
+	}
+	if param.UserName != nil {
+		user.UserName = *param.UserName
+	}
+	if param.PartnerName != nil {
+		user.PartnerName = *param.PartnerName
+	}
+	if param.UserImage != nil {
+		user.UserImage = *param.UserImage
+	}
+	if param.PartnerImage != nil {
+		user.PartnerImage = *param.PartnerImage
+	}
+	if param.Proportion != nil {
+		user.Proportion = *param.Proportion
+	}
+	if param.Language != nil {
+		user.Language = *param.Language
+	}
+
+	user, err = u.UserRepository.Update(user)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	return user, nil
+}
+
+func (u *userUsecase) DeleteByID(ctx context.Context, userID int) error {
+	// 退会と家計簿から外す処理は両方成功した場合のみ確定する
+	err := u.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		return u.UserRepository.DeleteByID(ctx, userID)
+	})
+	if err != nil {
+		return repositoryError(err)
+	}
+	return nil
+}
+
+// Purge : 退会からretention以上経過したユーザーのデータを物理削除し、削除したユーザー数を返す
+func (u *userUsecase) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	var n int
+	err := u.TxManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		n, err = u.UserRepository.PurgeDeletedBefore(ctx, time.Now().Add(-retention))
+		return err
+	})
+	if err != nil {
+		log.Println("repository error")
+		return 0, InternalServerError{}
+	}
+	return n, nil
+}
@@ -0,0 +1,286 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/warikan/api/domain/model"
+	"github.com/warikan/api/domain/repository"
+	"github.com/warikan/api/usecase"
+)
+
+func Test_userUsecase_GetData(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int
+		mockWant *model.User
+		mockErr  error
+		want     *model.User
+		wantErr  error
+	}{
+		{
+			name:     "Success",
+			userID:   1,
+			mockWant: &model.User{ID: 1, UserName: "ユーザー", Proportion: 50},
+			want:     &model.User{ID: 1, UserName: "ユーザー", Proportion: 50},
+		},
+		{
+			name:     "NotFound error",
+			userID:   1,
+			mockWant: &model.User{},
+			mockErr:  repository.ErrNotFound,
+			wantErr:  usecase.NotFoundError{},
+		},
+		{
+			name:     "Repository error",
+			userID:   1,
+			mockWant: &model.User{},
+			mockErr:  errors.New("repository error"),
+			wantErr:  usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockUserRepository{}
+			m.On("FindByID", tt.userID).Return(tt.mockWant, tt.mockErr)
+
+			u := usecase.NewUserUseCase(m, &mockTxManager{})
+			got, err := u.GetData(tt.userID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GetData() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_userUsecase_Update(t *testing.T) {
+	name := "新しい名前"
+	email := "new@example.com"
+	proportion := 60
+	outOfRange := 101
+	english := "en"
+	unsupported := "fr"
+
+	tests := []struct {
+		name     string
+		param    *usecase.UpdateUserParam
+		userID   int
+		current  *model.User
+		other    *model.User
+		otherErr error
+		mock     *model.User
+		mockErr  error
+		want     *model.User
+		wantErr  error
+	}{
+		{
+			name:     "Success",
+			param:    &usecase.UpdateUserParam{UserName: &name, Email: &email, Proportion: &proportion},
+			userID:   1,
+			current:  &model.User{ID: 1, UserName: "ユーザー", PartnerName: "パートナー", Email: "test@example.com", Proportion: 50},
+			other:    &model.User{},
+			otherErr: repository.ErrNotFound,
+			mock:     &model.User{ID: 1, UserName: name, PartnerName: "パートナー", Email: email, Proportion: proportion},
+			want:     &model.User{ID: 1, UserName: name, PartnerName: "パートナー", Email: email, Proportion: proportion},
+		},
+		{
+			name:    "Success language",
+			param:   &usecase.UpdateUserParam{Language: &english},
+			userID:  1,
+			current: &model.User{ID: 1, UserName: "ユーザー"},
+			mock:    &model.User{ID: 1, UserName: "ユーザー", Language: english},
+			want:    &model.User{ID: 1, UserName: "ユーザー", Language: english},
+		},
+		{
+			name:    "InvalidParam error unsupported language",
+			param:   &usecase.UpdateUserParam{Language: &unsupported},
+			userID:  1,
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name:    "InvalidParam error proportion out of range",
+			param:   &usecase.UpdateUserParam{Proportion: &outOfRange},
+			userID:  1,
+			wantErr: usecase.InvalidParamError{},
+		},
+		{
+			name:    "Conflict error email already used",
+			param:   &usecase.UpdateUserParam{Email: &email},
+			userID:  1,
+			current: &model.User{ID: 1, Email: "test@example.com"},
+			other:   &model.User{ID: 2, Email: email},
+			wantErr: usecase.ConflictError{},
+		},
+		{
+			name:    "NotFound error",
+			param:   &usecase.UpdateUserParam{UserName: &name},
+			userID:  1,
+			current: &model.User{},
+			mockErr: repository.ErrNotFound,
+			wantErr: usecase.NotFoundError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockUserRepository{}
+			m.On("FindByID", tt.userID).Return(tt.current, tt.mockErr)
+			m.On("FindByEmail", email).Return(tt.other, tt.otherErr)
+			m.On("Update", tt.mock).Return(tt.want, nil)
+
+			u := usecase.NewUserUseCase(m, &mockTxManager{})
+			got, err := u.Update(tt.param, tt.userID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Update() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_userUsecase_DeleteByID(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  int
+		mockErr error
+		wantErr error
+	}{
+		{
+			name:   "Success",
+			userID: 1,
+		},
+		{
+			name:    "NotFound error",
+			userID:  1,
+			mockErr: repository.ErrNotFound,
+			wantErr: usecase.NotFoundError{},
+		},
+		{
+			name:    "Repository error",
+			userID:  1,
+			mockErr: errors.New("repository error"),
+			wantErr: usecase.InternalServerError{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := &mockUserRepository{}
+			m.On("DeleteByID", tt.userID).Return(tt.mockErr)
+
+			u := usecase.NewUserUseCase(m, &mockTxManager{})
+			err := u.DeleteByID(context.Background(), tt.userID)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Error("expected error, but got nil")
+					return
+				}
+				if g, e := err.Error(), tt.wantErr.Error(); g != e {
+					t.Errorf("unexpected error:\nwant: %v\ngot : %v", e, g)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("err should be nil, but got %q", err)
+			}
+		})
+	}
+}
+
+func Test_userUsecase_Purge(t *testing.T) {
+	retention := 30 * 24 * time.Hour
+
+	m := &mockUserRepository{}
+	m.On("PurgeDeletedBefore", mock.MatchedBy(func(before time.Time) bool {
+		d := time.Since(before) - retention
+		return d >= 0 && d < time.Minute
+	})).Return(2, nil)
+
+	u := usecase.NewUserUseCase(m, &mockTxManager{})
+	got, err := u.Purge(context.Background(), retention)
+	if err != nil {
+		t.Fatalf("err should be nil, but got %q", err)
+	}
+	if got != 2 {
+		t.Errorf("Purge() = %d, want 2", got)
+	}
+}
+
+type mockUserRepository struct {
+	mock.Mock
+}
+
+func (m *mockUserRepository) FindByID(userID int) (*model.User, error) {
+	ret := m.Called(userID)
+	return ret.Get(0).(*model.User), ret.Error(1)
+}
+
+func (m *mockUserRepository) Update(mu *model.User) (*model.User, error) {
+	ret := m.Called(mu)
+	return ret.Get(0).(*model.User), ret.Error(1)
+}
+
+func (m *mockUserRepository) Create(mu *model.User) (*model.User, error) {
+	ret := m.Called(mu)
+	return ret.Get(0).(*model.User), ret.Error(1)
+}
+
+func (m *mockUserRepository) FindByEmail(email string) (*model.User, error) {
+	ret := m.Called(email)
+	return ret.Get(0).(*model.User), ret.Error(1)
+}
+
+func (m *mockUserRepository) DeleteByID(ctx context.Context, userID int) error {
+	ret := m.Called(userID)
+	return ret.Error(0)
+}
+
+func (m *mockUserRepository) PurgeDeletedBefore(ctx context.Context, before time.Time) (int, error) {
+	ret := m.Called(before)
+	return ret.Int(0), ret.Error(1)
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"strings"
 	"time"
 )
 
@@ -31,3 +32,23 @@ func ConvertStringDate(t time.Time) string {
 func JST(t time.Time) time.Time {
 	return t.In(jst)
 }
+
+// ParseJSTYearMonth : yyyy-MM形式の文字列をJSTタイムゾーンの月初のtimeに変換
+func ParseJSTYearMonth(s string) (time.Time, error) {
+	return time.ParseInLocation("2006-01", s, jst)
+}
+
+// ParseJSTDate : yyyy-MM-dd形式の文字列をJSTタイムゾーンのtimeに変換
+func ParseJSTDate(s string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", s, jst)
+}
+
+// ParseJSTSpreadsheetDate : yyyy-M-d形式またはyyyy/M/d形式の文字列をJSTタイムゾーンのtimeに変換。表計算ソフトから出力した日付に使う
+func ParseJSTSpreadsheetDate(s string) (time.Time, error) {
+	return time.ParseInLocation("2006-1-2", strings.Replace(s, "/", "-", -1), jst)
+}
+
+// ParseJSTTime : layout形式の文字列をJSTタイムゾーンのtimeに変換
+func ParseJSTTime(layout, s string) (time.Time, error) {
+	return time.ParseInLocation(layout, s, jst)
+}
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EncodeCursor : 支払日とIDをページングのカーソル文字列に変換
+func EncodeCursor(t time.Time, id int) string {
+	s := strconv.FormatInt(t.UnixNano(), 10) + "_" + strconv.Itoa(id)
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+// DecodeCursor : EncodeCursorで作成したカーソル文字列を支払日とIDに変換
+func DecodeCursor(cursor string) (time.Time, int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	parts := strings.SplitN(string(b), "_", 2)
+	if len(parts) != 2 {
+		return time.Time{}, 0, fmt.Errorf("invalid cursor: %q", cursor)
+	}
+
+	nsec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	return time.Unix(0, nsec), id, nil
+}
@@ -0,0 +1,21 @@
+package util
+
+import (
+	"strconv"
+	"time"
+)
+
+// EncodeVersion : 更新日時を楽観的排他制御のバージョン文字列に変換。データベースの精度に合わせてマイクロ秒単位で扱う
+func EncodeVersion(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano()/int64(time.Microsecond), 10)
+}
+
+// DecodeVersion : EncodeVersionで作成したバージョン文字列を更新日時に変換
+func DecodeVersion(version string) (time.Time, error) {
+	usec, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(0, usec*int64(time.Microsecond)), nil
+}
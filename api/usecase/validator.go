@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator"
+)
+
+// validate : 項目名はJSONのキー名で報告する
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	// nolint:errcheck
+	v.RegisterValidation("maxfuture", maxFuture)
+	return v
+}
+
+// maxFuture : 日付が現在から指定日数より先でないことを検証する。例: maxfuture=365
+func maxFuture(fl validator.FieldLevel) bool {
+	t, ok := fl.Field().Interface().(time.Time)
+	if !ok {
+		return false
+	}
+	days, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		return false
+	}
+	return !t.After(time.Now().AddDate(0, 0, days))
+}
+
+// validateParam : 検証に失敗した項目をInvalidParamErrorのFieldsに設定して返す
+func validateParam(param interface{}) error {
+	fields := fieldErrors(param)
+	if fields == nil {
+		return nil
+	}
+
+	log.Println("validation error")
+	return InvalidParamError{Fields: fields}
+}
+
+// fieldErrors : 検証に失敗した項目を返す。全ての項目が正しい場合はnil
+func fieldErrors(param interface{}) []FieldError {
+	err := validate.Struct(param)
+	if err == nil {
+		return nil
+	}
+
+	ve, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return []FieldError{}
+	}
+
+	fields := make([]FieldError, 0, len(ve))
+	for _, fe := range ve {
+		fields = append(fields, FieldError{Field: fe.Field(), Rule: fe.Tag(), Param: fe.Param()})
+	}
+	return fields
+}
+
+// invalidParam : 構造体のタグで表せない検証に失敗した項目のInvalidParamErrorを返す
+func invalidParam(field, rule, param string) error {
+	log.Println("validation error")
+	return InvalidParamError{Fields: []FieldError{{Field: field, Rule: rule, Param: param}}}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"flag"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -19,6 +20,8 @@ import (
 	handler "github.com/warikan/api/handler/rest"
 	"github.com/warikan/api/infra"
 	"github.com/warikan/api/usecase"
+	"github.com/warikan/api/usecase/statement"
+	"github.com/warikan/config"
 	"github.com/warikan/db"
 	"github.com/warikan/log"
 )
@@ -44,46 +47,188 @@ func main() {
 	}
 	defer db.Close()
 
+	authConfig, err := config.GetAuth(configFilePath)
+	if err != nil {
+		log.Logger.Error("failed to load auth config", zap.Error(err))
+		os.Exit(1)
+	}
+
 	r := chi.NewRouter()
 
 	r.Use(middleware.SetHeader("Content-Type", "application/json"))
 	r.Use(middleware.Logger)
 	r.Use(middleware.Compress(6, "gzip"))
 	r.Use(middleware.StripSlashes)
+	r.Use(handler.Language)
 
 	cors := cors.New(cors.Options{
 		AllowedOrigins:   allowedOrigins(),
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "If-Match", "X-CSRF-Token"},
+		ExposedHeaders:   []string{"ETag", "Link"},
 		AllowCredentials: true,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	})
 
 	r.Use(cors.Handler)
 
+	pagination, err := config.GetPagination(configFilePath)
+	if err != nil {
+		log.Logger.Error("failed to load pagination config", zap.Error(err))
+		os.Exit(1)
+	}
+
+	queryTimeout, err := config.GetQueryTimeout(configFilePath)
+	if err != nil {
+		log.Logger.Error("failed to load query timeout config", zap.Error(err))
+		os.Exit(1)
+	}
+
+	txManager := infra.NewTxManager(db.Pool)
+
+	userRepository := infra.NewUserRepository(db.Pool)
+	householdRepository := infra.NewHouseholdRepository(db.Pool)
+	authUsecase := usecase.NewAuthUseCase(userRepository, householdRepository, []byte(authConfig.Secret), time.Duration(authConfig.ExpireHours)*time.Hour)
+	authHandler := handler.NewAuthHandler(authUsecase)
+	authMiddleware := handler.NewAuthMiddleware(authUsecase)
+
+	userUsecase := usecase.NewUserUseCase(userRepository, txManager)
+	usersHandler := handler.NewUsersHandler(userUsecase)
+
+	householdUsecase := usecase.NewHouseholdUseCase(householdRepository, txManager)
+	householdsHandler := handler.NewHouseholdsHandler(householdUsecase)
+
 	healthRepository := infra.NewPingPersistencePostgres(db.Pool)
 	healthUseCase := usecase.NewHealthUseCase(healthRepository)
 	healthHandler := handler.NewHealthHandler(healthUseCase, version)
 
-	paymentRepository := infra.NewPaymentsRepository(db.Pool)
-	paymentUsecase := usecase.NewPaymentUseCase(paymentRepository)
+	paymentRepository := infra.NewPaymentsRepository(db.Pool, queryTimeout)
+	paymentUsecase := usecase.NewPaymentUseCase(paymentRepository, usecase.PageSize{Default: pagination.PageSize, Max: pagination.MaxPageSize})
 	paymentsHandler := handler.NewPaymentsHandler(paymentUsecase)
 
+	fixedCostRepository := infra.NewFixedCostRepository(db.Pool)
+	fixedCostUsecase := usecase.NewFixedCostUseCase(fixedCostRepository, txManager)
+	fixedCostsHandler := handler.NewFixedCostsHandler(fixedCostUsecase)
+
+	categoryRepository := infra.NewCategoryRepository(db.Pool)
+	categoryUsecase := usecase.NewCategoryUseCase(categoryRepository)
+	categoriesHandler := handler.NewCategoriesHandler(categoryUsecase)
+
+	payerRepository := infra.NewPayerRepository(db.Pool)
+	payerUsecase := usecase.NewPayerUseCase(payerRepository)
+	payersHandler := handler.NewPayersHandler(payerUsecase)
+
+	paymentImportUsecase := usecase.NewPaymentImportUseCase(paymentRepository, categoryRepository, payerRepository, txManager)
+	paymentImportHandler := handler.NewPaymentImportHandler(paymentImportUsecase)
+
+	statementFormats, err := config.GetStatementFormats(configFilePath)
+	if err != nil {
+		log.Logger.Error("failed to load statement formats config", zap.Error(err))
+		os.Exit(1)
+	}
+	csvFormats := append([]*statement.CSVFormat{}, statement.DefaultCSVFormats...)
+	for _, f := range statementFormats {
+		csvFormat := &statement.CSVFormat{
+			Name:              f.Name,
+			Encoding:          f.Encoding,
+			SkipRows:          f.SkipRows,
+			DateColumn:        f.DateColumn,
+			DateLayout:        f.DateLayout,
+			AmountColumn:      f.AmountColumn,
+			DescriptionColumn: f.DescriptionColumn,
+		}
+		if err := csvFormat.Validate(); err != nil {
+			log.Logger.Error("invalid statement format config", zap.Error(err))
+			os.Exit(1)
+		}
+		csvFormats = append(csvFormats, csvFormat)
+	}
+
+	paymentDraftRepository := infra.NewPaymentDraftRepository(db.Pool)
+	paymentDraftUsecase := usecase.NewPaymentDraftUseCase(paymentDraftRepository, paymentRepository, txManager, csvFormats)
+	paymentDraftsHandler := handler.NewPaymentDraftsHandler(paymentDraftUsecase)
+
+	groupRepository := infra.NewGroupRepository(db.Pool)
+	groupUsecase := usecase.NewGroupUseCase(groupRepository, txManager)
+	groupsHandler := handler.NewGroupsHandler(groupUsecase)
+
+	settlementRepository := infra.NewSettlementRepository(db.Pool)
+	settlementUsecase := usecase.NewSettlementUseCase(settlementRepository)
+	settlementsHandler := handler.NewSettlementsHandler(settlementUsecase)
+
 	r.Route("/warikan/v1", func(r chi.Router) {
-		r.Route("/users/{user_id}/payments", func(r chi.Router) {
-			r.Get("/", paymentsHandler.GetData)
-			r.Post("/", paymentsHandler.CreateData)
-			r.Patch("/{payment_id}", paymentsHandler.UpdateData)
-			r.Delete("/{payment_id}", paymentsHandler.DeleteData)
-			r.Get("/monthly_cost", paymentsHandler.FetchDate)
+		r.Post("/signup", authHandler.Signup)
+		r.Post("/login", authHandler.Login)
+		r.Route("/users/{user_id}", func(r chi.Router) {
+			// アカウントの操作は本人のみ
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.Authorize)
+				r.Get("/", usersHandler.GetData)
+				r.Patch("/", usersHandler.UpdateData)
+				r.Delete("/", usersHandler.DeleteData)
+				r.Get("/household", householdsHandler.GetData)
+				r.Post("/household/invitation", householdsHandler.Invite)
+				r.Post("/household/accept", householdsHandler.Accept)
+			})
+			// 家計簿のデータは家計簿に参加しているパートナーも扱える
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.AuthorizeLedger)
+				r.Route("/payments", func(r chi.Router) {
+					r.Get("/", paymentsHandler.GetData)
+					r.Post("/", paymentsHandler.CreateData)
+					r.Get("/{payment_id}", paymentsHandler.GetDetail)
+					r.Patch("/{payment_id}", paymentsHandler.UpdateData)
+					r.Delete("/{payment_id}", paymentsHandler.DeleteData)
+					r.Post("/{payment_id}/approve", paymentsHandler.Approve)
+					r.Post("/{payment_id}/reject", paymentsHandler.Reject)
+					r.Get("/monthly_cost", paymentsHandler.FetchMonthlyCost)
+					r.Get("/export", paymentsHandler.Export)
+					r.Post("/import", paymentImportHandler.Import)
+				})
+				r.Route("/payment_drafts", func(r chi.Router) {
+					r.Get("/", paymentDraftsHandler.GetData)
+					r.Post("/import", paymentDraftsHandler.Import)
+					r.Post("/{payment_draft_id}/confirm", paymentDraftsHandler.Confirm)
+					r.Delete("/{payment_draft_id}", paymentDraftsHandler.DeleteData)
+				})
+				r.Route("/fixed_costs", func(r chi.Router) {
+					r.Get("/", fixedCostsHandler.GetData)
+					r.Post("/", fixedCostsHandler.CreateData)
+					r.Patch("/{fixed_cost_id}", fixedCostsHandler.UpdateData)
+					r.Delete("/{fixed_cost_id}", fixedCostsHandler.DeleteData)
+				})
+				r.Route("/categories", func(r chi.Router) {
+					r.Get("/", categoriesHandler.GetData)
+					r.Post("/", categoriesHandler.CreateData)
+					r.Patch("/{category_id}", categoriesHandler.UpdateData)
+					r.Delete("/{category_id}", categoriesHandler.DeleteData)
+				})
+				r.Route("/groups", func(r chi.Router) {
+					r.Get("/", groupsHandler.GetData)
+					r.Post("/", groupsHandler.CreateData)
+					r.Get("/{group_id}", groupsHandler.GetDetail)
+					r.Delete("/{group_id}", groupsHandler.DeleteData)
+					r.Post("/{group_id}/members", groupsHandler.CreateMember)
+					r.Get("/{group_id}/payments", groupsHandler.GetPayments)
+					r.Post("/{group_id}/payments", groupsHandler.CreatePayment)
+					r.Delete("/{group_id}/payments/{group_payment_id}", groupsHandler.DeletePayment)
+					r.Get("/{group_id}/settlement", groupsHandler.GetSettlement)
+				})
+				r.Get("/payers", payersHandler.GetData)
+				r.Get("/settlements/{year_month}", settlementsHandler.GetData)
+			})
 		})
 		r.Get("/health", healthHandler.Check)
 	})
 
+	// シャットダウンがタイムアウトした場合に、実行中のリクエストのクエリを中断する
+	baseCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+
 	srv := &http.Server{
-		Addr:    port,
-		Handler: r,
+		Addr:        port,
+		Handler:     r,
+		BaseContext: func(net.Listener) context.Context { return baseCtx },
 	}
 	go func() {
 		if err := srv.ListenAndServe(); err != nil {
@@ -101,6 +246,7 @@ func main() {
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Logger.Error("err", zap.Error(err))
+		cancelRequests()
 	}
 	log.Logger.Info("shutdown warikan-api server", zap.String("version", version))
 }
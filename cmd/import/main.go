@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"go.uber.org/zap"
+
+	"github.com/warikan/api/infra"
+	"github.com/warikan/api/usecase"
+	"github.com/warikan/config"
+	"github.com/warikan/db"
+	"github.com/warikan/log"
+)
+
+var (
+	maxconn        = 1
+	configFilePath = "_config/config.yaml"
+	userID         = 0
+	filePath       = ""
+	dryRun         = false
+)
+
+// 表計算ソフトなどから出力した支払いのCSVを指定ユーザーに取り込む。不正な行がある場合は何も登録しない
+func main() {
+	flag.StringVar(&configFilePath, "configFilePath", configFilePath, "config filePath")
+	flag.IntVar(&maxconn, "maxconn", maxconn, "max db connection")
+	flag.IntVar(&userID, "user", userID, "user id to import payments into")
+	flag.StringVar(&filePath, "file", filePath, "csv file to import")
+	flag.BoolVar(&dryRun, "dryRun", dryRun, "validate the csv without inserting payments")
+	flag.Parse()
+
+	log.Init()
+	// nolint:errcheck
+	defer log.Logger.Sync()
+
+	if userID == 0 || filePath == "" {
+		log.Logger.Error("user and file are required")
+		os.Exit(2)
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		log.Logger.Error("failed to open file", zap.String("file", filePath), zap.Error(err))
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	if err := db.Init(maxconn, configFilePath); err != nil {
+		log.Logger.Error("failed to initialize db", zap.Error(err))
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	queryTimeout, err := config.GetQueryTimeout(configFilePath)
+	if err != nil {
+		log.Logger.Error("failed to load query timeout config", zap.Error(err))
+		os.Exit(1)
+	}
+
+	paymentImportUsecase := usecase.NewPaymentImportUseCase(
+		infra.NewPaymentsRepository(db.Pool, queryTimeout),
+		infra.NewCategoryRepository(db.Pool),
+		infra.NewPayerRepository(db.Pool),
+		infra.NewTxManager(db.Pool),
+	)
+
+	res, err := paymentImportUsecase.Import(context.Background(), userID, userID, f, dryRun)
+	if err != nil {
+		log.Logger.Error("failed to import payments", zap.String("file", filePath), zap.Any("error", err))
+		os.Exit(1)
+	}
+	for _, e := range res.Errors {
+		log.Logger.Warn("invalid row", zap.Int("row", e.Row), zap.Any("fields", e.Fields))
+	}
+	log.Logger.Info("imported payments",
+		zap.String("file", filePath),
+		zap.Bool("dryRun", res.DryRun),
+		zap.Int("rows", res.Rows),
+		zap.Int("imported", res.Imported),
+		zap.Ints("duplicates", res.Duplicates),
+		zap.Int("errors", len(res.Errors)),
+	)
+	if len(res.Errors) > 0 {
+		os.Exit(1)
+	}
+}
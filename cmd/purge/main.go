@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"time"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"go.uber.org/zap"
+
+	"github.com/warikan/api/infra"
+	"github.com/warikan/api/usecase"
+	"github.com/warikan/db"
+	"github.com/warikan/log"
+)
+
+var (
+	maxconn        = 1
+	configFilePath = "_config/config.yaml"
+	retentionDays  = 30
+)
+
+// 退会から保持期間を過ぎたユーザーのデータを物理削除する。cronなどで定期実行する想定
+func main() {
+	flag.StringVar(&configFilePath, "configFilePath", configFilePath, "config filePath")
+	flag.IntVar(&maxconn, "maxconn", maxconn, "max db connection")
+	flag.IntVar(&retentionDays, "retentionDays", retentionDays, "days to keep soft-deleted users before purging")
+	flag.Parse()
+
+	log.Init()
+	// nolint:errcheck
+	defer log.Logger.Sync()
+
+	if err := db.Init(maxconn, configFilePath); err != nil {
+		log.Logger.Error("failed to initialize db", zap.Error(err))
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	userRepository := infra.NewUserRepository(db.Pool)
+	txManager := infra.NewTxManager(db.Pool)
+	userUsecase := usecase.NewUserUseCase(userRepository, txManager)
+
+	n, err := userUsecase.Purge(context.Background(), time.Duration(retentionDays)*24*time.Hour)
+	if err != nil {
+		log.Logger.Error("failed to purge users", zap.Int("retentionDays", retentionDays), zap.Error(err))
+		os.Exit(1)
+	}
+	log.Logger.Info("purged users", zap.Int("retentionDays", retentionDays), zap.Int("purged", n))
+}
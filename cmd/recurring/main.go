@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"time"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"go.uber.org/zap"
+
+	"github.com/warikan/api/infra"
+	"github.com/warikan/api/usecase"
+	"github.com/warikan/api/usecase/util"
+	"github.com/warikan/db"
+	"github.com/warikan/log"
+)
+
+var (
+	maxconn        = 1
+	configFilePath = "_config/config.yaml"
+	yearMonth      = ""
+)
+
+// 固定費から指定月(デフォルトは当月)の支払いを作成する。cronなどで定期実行する想定
+func main() {
+	flag.StringVar(&configFilePath, "configFilePath", configFilePath, "config filePath")
+	flag.IntVar(&maxconn, "maxconn", maxconn, "max db connection")
+	flag.StringVar(&yearMonth, "month", yearMonth, "target month (yyyy-mm), defaults to the current month in JST")
+	flag.Parse()
+
+	log.Init()
+	// nolint:errcheck
+	defer log.Logger.Sync()
+
+	if yearMonth == "" {
+		yearMonth = util.JST(time.Now()).Format("2006-01")
+	}
+
+	if err := db.Init(maxconn, configFilePath); err != nil {
+		log.Logger.Error("failed to initialize db", zap.Error(err))
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	fixedCostRepository := infra.NewFixedCostRepository(db.Pool)
+	fixedCostUsecase := usecase.NewFixedCostUseCase(fixedCostRepository, infra.NewTxManager(db.Pool))
+
+	n, err := fixedCostUsecase.Materialize(context.Background(), yearMonth)
+	if err != nil {
+		log.Logger.Error("failed to materialize fixed costs", zap.String("month", yearMonth), zap.Int("created", n), zap.Error(err))
+		os.Exit(1)
+	}
+	log.Logger.Info("materialized fixed costs", zap.String("month", yearMonth), zap.Int("created", n))
+}
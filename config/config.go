@@ -3,13 +3,18 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
+	"time"
 
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 )
 
 type Config struct {
-	DB DB
+	DB               DB
+	Auth             Auth
+	Pagination       Pagination
+	StatementFormats []StatementFormat `yaml:"statement_formats"`
 }
 
 type DB struct {
@@ -19,6 +24,30 @@ type DB struct {
 	SSLMode  string
 	User     string
 	Password string
+	// QueryTimeout : 1回のクエリのタイムアウト(秒)。0の場合はタイムアウトしない。省略した場合は5秒
+	QueryTimeout *int `yaml:"query_timeout"`
+}
+
+// Auth : Secretはトークンの署名鍵。設定ファイルには書かず、環境変数WARIKAN_AUTH_SECRETで指定する
+type Auth struct {
+	Secret      string `yaml:"-"`
+	ExpireHours int    `yaml:"expire_hours"`
+}
+
+type Pagination struct {
+	PageSize    int `yaml:"page_size"`
+	MaxPageSize int `yaml:"max_page_size"`
+}
+
+// StatementFormat : カード会社の利用明細のCSVの列の対応。列の番号は0始まり。EncodingはUTF-8またはShift_JIS
+type StatementFormat struct {
+	Name              string
+	Encoding          string
+	SkipRows          int    `yaml:"skip_rows"`
+	DateColumn        int    `yaml:"date_column"`
+	DateLayout        string `yaml:"date_layout"`
+	AmountColumn      int    `yaml:"amount_column"`
+	DescriptionColumn int    `yaml:"description_column"`
 }
 
 var conf *Config
@@ -40,6 +69,66 @@ func GetDSN(filePath string) (string, error) {
 	return dsn, nil
 }
 
+// GetQueryTimeout : 0の場合はタイムアウトしないことを表す
+func GetQueryTimeout(filePath string) (time.Duration, error) {
+	c, err := load(filePath)
+	if err != nil {
+		return 0, err
+	}
+	t := c.DB.QueryTimeout
+	if t == nil {
+		return 5 * time.Second, nil
+	}
+	if *t < 0 {
+		return 0, errors.Errorf("query timeout must not be negative: %d", *t)
+	}
+	return time.Duration(*t) * time.Second, nil
+}
+
+func GetAuth(filePath string) (Auth, error) {
+	c, err := load(filePath)
+	if err != nil {
+		return Auth{}, err
+	}
+
+	a := c.Auth
+	a.Secret = os.Getenv("WARIKAN_AUTH_SECRET")
+	if a.Secret == "" {
+		return Auth{}, errors.New("auth secret is not configured: set WARIKAN_AUTH_SECRET")
+	}
+	if a.ExpireHours <= 0 {
+		a.ExpireHours = 24 * 30
+	}
+
+	return a, nil
+}
+
+func GetPagination(filePath string) (Pagination, error) {
+	c, err := load(filePath)
+	if err != nil {
+		return Pagination{}, err
+	}
+
+	p := c.Pagination
+	if p.PageSize <= 0 {
+		p.PageSize = 20
+	}
+	if p.MaxPageSize < p.PageSize {
+		p.MaxPageSize = p.PageSize
+	}
+
+	return p, nil
+}
+
+func GetStatementFormats(filePath string) ([]StatementFormat, error) {
+	c, err := load(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.StatementFormats, nil
+}
+
 func load(filePath string) (*Config, error) {
 	if conf != nil {
 		return conf, nil